@@ -0,0 +1,231 @@
+// Package acl implements Redis's access control lists: the users
+// connections authenticate as, and the commands, keys and channels each of
+// them may use.
+package acl
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// DefaultUser is the user connections are authenticated as when they connect
+const DefaultUser = "default"
+
+// ErrDefaultUser is returned when trying to delete the default user
+var ErrDefaultUser = errors.New("The 'default' user cannot be removed")
+
+// ACL holds the users and the log of denied commands and authentications.
+type ACL struct {
+	mu    sync.RWMutex
+	users map[string]*User
+
+	log Log
+}
+
+var (
+	// instance is the process-wide ACL
+	instance     *ACL
+	instanceOnce sync.Once
+)
+
+// NewACL creates an ACL holding only the default user.
+func NewACL() *ACL {
+	return &ACL{
+		users: map[string]*User{DefaultUser: newDefaultUser()},
+	}
+}
+
+// GetACL returns the process-wide ACL.
+func GetACL() *ACL {
+	instanceOnce.Do(func() {
+		instance = NewACL()
+	})
+	return instance
+}
+
+// setRequirePass gives the default user password as its only password, or
+// lets it authenticate without one if password is empty
+func (a *ACL) setRequirePass(password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	user := a.users[DefaultUser].clone()
+	user.passwords = nil
+	user.noPass = password == ""
+	if password != "" {
+		user.passwords = []string{hashPassword(password)}
+	}
+	a.users[DefaultUser] = user
+}
+
+// User returns the user with the given name.
+func (a *ACL) User(name string) (*User, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	user, ok := a.users[name]
+	return user, ok
+}
+
+// Users returns the names of the users in alphabetical order.
+func (a *ACL) Users() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return slices.Sorted(maps.Keys(a.users))
+}
+
+// Authenticate returns the user with the given name if it is enabled and
+// password is one of its passwords.
+func (a *ACL) Authenticate(username, password string) (*User, bool) {
+	user, ok := a.User(username)
+	if !ok || !user.Enabled() || !user.CheckPassword(password) {
+		return nil, false
+	}
+	return user, true
+}
+
+// SetUser creates or modifies a user by applying rules in order, as ACL
+// SETUSER does. Either every rule is applied or, if one is invalid, none is.
+func (a *ACL) SetUser(name string, rules []string, lookup CommandLookup) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	user, ok := a.users[name]
+	if ok {
+		user = user.clone()
+	} else {
+		user = newUser(name)
+	}
+	for _, rule := range rules {
+		if err := user.apply(rule, lookup); err != nil {
+			return fmt.Errorf("Error in ACL SETUSER modifier '%s': %v", rule, err)
+		}
+	}
+	a.users[name] = user
+	return nil
+}
+
+// DeleteUsers deletes the named users and returns how many existed.
+func (a *ACL) DeleteUsers(names []string) (int, error) {
+	if slices.Contains(names, DefaultUser) {
+		return 0, ErrDefaultUser
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	deleted := 0
+	for _, name := range names {
+		if _, ok := a.users[name]; ok {
+			delete(a.users, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// List describes every user, in alphabetical order, as ACL LIST does.
+func (a *ACL) List() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	lines := make([]string, 0, len(a.users))
+	for _, name := range slices.Sorted(maps.Keys(a.users)) {
+		lines = append(lines, a.users[name].String())
+	}
+	return lines
+}
+
+// Log returns the log of denied commands and authentications.
+func (a *ACL) Log() *Log {
+	return &a.log
+}
+
+// Save writes the users to the ACL file at path, replacing it atomically.
+func (a *ACL) Save(path string) error {
+	var b strings.Builder
+	for _, line := range a.List() {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load replaces the users with the ones defined in the ACL file at path,
+// one "user <name> <rules...>" line each. If any line is invalid the users
+// are left unchanged. The default user is recreated with its default rules
+// if the file doesn't define it.
+func (a *ACL) Load(path string, lookup CommandLookup) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	users := make(map[string]*User)
+	var errs []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lineErr := func(format string, args ...any) {
+			errs = append(errs, fmt.Sprintf("%s:%d: %s", path, i+1, fmt.Sprintf(format, args...)))
+		}
+
+		args, ok := resp.SplitArgs([]byte(line))
+		if !ok {
+			lineErr("unbalanced quotes in acl line")
+			continue
+		}
+		if len(args) < 2 || string(args[0]) != "user" {
+			lineErr("line should start with user keyword")
+			continue
+		}
+		name := string(args[1])
+		if _, ok := users[name]; ok {
+			lineErr("Duplicate user '%s' found", name)
+			continue
+		}
+
+		user := newUser(name)
+		for _, arg := range args[2:] {
+			if err := user.apply(string(arg), lookup); err != nil {
+				lineErr("%v", err)
+				break
+			}
+		}
+		users[name] = user
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ". "))
+	}
+
+	if _, ok := users[DefaultUser]; !ok {
+		users[DefaultUser] = newDefaultUser()
+	}
+	a.mu.Lock()
+	a.users = users
+	a.mu.Unlock()
+	return nil
+}
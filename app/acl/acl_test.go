@@ -0,0 +1,145 @@
+package acl
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestACL_SetUser(t *testing.T) {
+	a := NewACL()
+
+	if err := a.SetUser("alice", []string{"on", ">secret", "~cache:*", "+get"}, knownCommands); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if users := a.Users(); !slices.Equal(users, []string{"alice", "default"}) {
+		t.Errorf("Expected alice and default, got %q", users)
+	}
+
+	// An invalid rule leaves the user unchanged
+	err := a.SetUser("alice", []string{"+set", "+unknown"}, knownCommands)
+	if err == nil || err.Error() != "Error in ACL SETUSER modifier '+unknown': Unknown command or category name in ACL" {
+		t.Errorf("Expected an error naming the rule, got %v", err)
+	}
+	user, _ := a.User("alice")
+	if user.CanRun("set", "", nil) {
+		t.Errorf("Expected the rules before the invalid one not to be applied")
+	}
+	if err := a.SetUser("bob", []string{"bogus"}, knownCommands); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if _, ok := a.User("bob"); ok {
+		t.Errorf("Expected a user not to be created by invalid rules")
+	}
+}
+
+func TestACL_Authenticate(t *testing.T) {
+	a := NewACL()
+	a.SetUser("alice", []string{">secret"}, knownCommands)
+
+	if _, ok := a.Authenticate("default", "anything"); !ok {
+		t.Errorf("Expected the default user to need no password")
+	}
+	if _, ok := a.Authenticate("alice", "secret"); ok {
+		t.Errorf("Expected a disabled user not to authenticate")
+	}
+	a.SetUser("alice", []string{"on"}, knownCommands)
+	if user, ok := a.Authenticate("alice", "secret"); !ok || user.Name != "alice" {
+		t.Errorf("Expected alice to authenticate")
+	}
+	if _, ok := a.Authenticate("alice", "wrong"); ok {
+		t.Errorf("Expected the wrong password to be refused")
+	}
+	if _, ok := a.Authenticate("nobody", ""); ok {
+		t.Errorf("Expected an unknown user to be refused")
+	}
+}
+
+func TestACL_RequirePass(t *testing.T) {
+	a := NewACL()
+
+	a.setRequirePass("secret")
+	if _, ok := a.Authenticate("default", "anything"); ok {
+		t.Errorf("Expected requirepass to be the default user's password")
+	}
+	if _, ok := a.Authenticate("default", "secret"); !ok {
+		t.Errorf("Expected the requirepass password to be accepted")
+	}
+
+	a.setRequirePass("")
+	if _, ok := a.Authenticate("default", "anything"); !ok {
+		t.Errorf("Expected clearing requirepass to make the default user need no password")
+	}
+}
+
+func TestACL_DeleteUsers(t *testing.T) {
+	a := NewACL()
+	a.SetUser("alice", nil, knownCommands)
+
+	if _, err := a.DeleteUsers([]string{"alice", "default"}); err != ErrDefaultUser {
+		t.Errorf("Expected the default user not to be deletable, got %v", err)
+	}
+	if n, err := a.DeleteUsers([]string{"alice", "nobody"}); err != nil || n != 1 {
+		t.Errorf("Expected one user to be deleted, got %d (%v)", n, err)
+	}
+	if _, ok := a.User("alice"); ok {
+		t.Errorf("Expected alice to be deleted")
+	}
+}
+
+func TestACL_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.acl")
+
+	a := NewACL()
+	a.SetUser("alice", []string{"on", ">secret", "%R~cache:*", "&news", "+@read", "-get"}, knownCommands)
+	a.SetUser("default", []string{"resetpass", ">admin"}, knownCommands)
+	if err := a.Save(path); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+
+	loaded := NewACL()
+	if err := loaded.Load(path, knownCommands); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+	if !slices.Equal(loaded.List(), a.List()) {
+		t.Errorf("Expected %q, got %q", a.List(), loaded.List())
+	}
+
+	// The default user is recreated when the file doesn't define it
+	os.WriteFile(path, []byte("user alice on nopass +@all ~*\n\n"), 0o644)
+	if err := loaded.Load(path, knownCommands); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+	if list := loaded.List(); !slices.Equal(list, []string{"user alice on nopass ~* resetchannels +@all", "user default on nopass ~* &* +@all"}) {
+		t.Errorf("Unexpected users %q", list)
+	}
+}
+
+func TestACL_LoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.acl")
+	a := NewACL()
+	a.SetUser("alice", []string{"on"}, knownCommands)
+
+	os.WriteFile(path, []byte("user bob on\nbob off\nuser carol +unknown\nuser bob off\nuser \"dave\n"), 0o644)
+	err := a.Load(path, knownCommands)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	for _, expected := range []string{
+		path + ":2: line should start with user keyword",
+		path + ":3: Unknown command or category name in ACL",
+		path + ":4: Duplicate user 'bob' found",
+		path + ":5: unbalanced quotes in acl line",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to contain %q, got %q", expected, err)
+		}
+	}
+
+	// Nothing is loaded from an invalid file
+	if users := a.Users(); !slices.Equal(users, []string{"alice", "default"}) {
+		t.Errorf("Expected the users to be unchanged, got %q", users)
+	}
+}
@@ -0,0 +1,105 @@
+package acl
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+// DefaultLogMaxLen is the default number of entries kept in the ACL log
+const DefaultLogMaxLen = 128
+
+// logGroupingWindow is how long after an entry was last updated an identical
+// denial is counted in it rather than logged as a new entry
+const logGroupingWindow = 60 * time.Second
+
+// Reasons an entry is logged for
+const (
+	ReasonCommand = "command"
+	ReasonKey     = "key"
+	ReasonChannel = "channel"
+	ReasonAuth    = "auth"
+)
+
+// LogEntry records a denied command or a failed authentication, shown by
+// ACL LOG.
+type LogEntry struct {
+	// Count is how many identical denials the entry stands for
+	Count int64
+	// Reason is ReasonCommand, ReasonKey, ReasonChannel or ReasonAuth
+	Reason string
+	// Context is where the command was run, always "toplevel"
+	Context string
+	// Object is the command, key or channel that was denied, or "AUTH"
+	Object string
+	// Username is the user the client was authenticated as, or tried to
+	// authenticate as
+	Username string
+	// ClientInfo describes the client, as in CLIENT LIST
+	ClientInfo string
+	// ID numbers the entries in the order they were created
+	ID int64
+	// Created and Updated are when the first and last denial happened
+	Created time.Time
+	Updated time.Time
+}
+
+// Log is the ACL log, most recent entries first.
+type Log struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	nextID  int64
+}
+
+// Add records a denial. A denial identical to one logged within the last
+// minute increments that entry's count instead of adding a new entry.
+func (l *Log) Add(reason, object, username, clientInfo string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for i, entry := range l.entries {
+		if entry.Reason == reason && entry.Object == object && entry.Username == username && now.Sub(entry.Updated) < logGroupingWindow {
+			entry.Count++
+			entry.ClientInfo = clientInfo
+			entry.Updated = now
+			// Move it back to the front, as the most recent entry
+			l.entries = slices.Insert(slices.Delete(l.entries, i, i+1), 0, entry)
+			return
+		}
+	}
+
+	l.entries = slices.Insert(l.entries, 0, LogEntry{
+		Count:      1,
+		Reason:     reason,
+		Context:    "toplevel",
+		Object:     object,
+		Username:   username,
+		ClientInfo: clientInfo,
+		ID:         l.nextID,
+		Created:    now,
+		Updated:    now,
+	})
+	l.nextID++
+
+	maxLen := config.GetInt("acl-log-max-len", DefaultLogMaxLen)
+	if len(l.entries) > maxLen {
+		l.entries = l.entries[:max(maxLen, 0)]
+	}
+}
+
+// Entries returns up to count of the most recent entries.
+func (l *Log) Entries(count int) []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries[:min(count, len(l.entries))])
+}
+
+// Reset removes every entry.
+func (l *Log) Reset() {
+	l.mu.Lock()
+	l.entries = nil
+	l.mu.Unlock()
+}
@@ -0,0 +1,48 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+func TestLog_Add(t *testing.T) {
+	var log Log
+
+	log.Add(ReasonCommand, "get", "alice", "id=1")
+	log.Add(ReasonKey, "secret", "alice", "id=1")
+	log.Add(ReasonCommand, "get", "alice", "id=2")
+
+	entries := log.Entries(10)
+	if len(entries) != 2 {
+		t.Fatalf("Expected identical denials to be grouped, got %d entries", len(entries))
+	}
+	if entries[0].Object != "get" || entries[0].Count != 2 || entries[0].ClientInfo != "id=2" || entries[0].ID != 0 {
+		t.Errorf("Expected the grouped entry first, with the latest client, got %+v", entries[0])
+	}
+	if entries[1].Reason != ReasonKey || entries[1].Context != "toplevel" || entries[1].ID != 1 {
+		t.Errorf("Unexpected entry %+v", entries[1])
+	}
+	if entries := log.Entries(1); len(entries) != 1 || entries[0].Object != "get" {
+		t.Errorf("Expected only the most recent entry, got %+v", entries)
+	}
+
+	log.Reset()
+	if entries := log.Entries(10); len(entries) != 0 {
+		t.Errorf("Expected no entries after a reset, got %d", len(entries))
+	}
+}
+
+func TestLog_MaxLen(t *testing.T) {
+	config.SetConfig("acl-log-max-len", "2")
+	defer config.SetConfig("acl-log-max-len", "128")
+
+	var log Log
+	for _, key := range []string{"a", "b", "c"} {
+		log.Add(ReasonKey, key, "alice", "")
+	}
+	entries := log.Entries(10)
+	if len(entries) != 2 || entries[0].Object != "c" || entries[1].Object != "b" {
+		t.Errorf("Expected the two most recent entries, got %+v", entries)
+	}
+}
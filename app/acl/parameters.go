@@ -0,0 +1,27 @@
+package acl
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+// init declares the parameters of the ACL
+func init() {
+	for _, p := range []config.Parameter{
+		{Name: "requirepass", Type: config.TypeString, Apply: applyRequirePass},
+		{Name: "aclfile", Type: config.TypeString, Immutable: true},
+		{Name: "acl-log-max-len", Type: config.TypeInt, Default: strconv.Itoa(DefaultLogMaxLen), Min: 0, Max: math.MaxInt32},
+	} {
+		config.Register(p)
+	}
+}
+
+// applyRequirePass gives the default user the password of the requirepass
+// setting, or no password if it is empty
+func applyRequirePass() error {
+	password, _ := config.Get("requirepass")
+	GetACL().setRequirePass(password)
+	return nil
+}
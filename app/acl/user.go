@@ -0,0 +1,446 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/glob"
+)
+
+// Categories are the command categories rules may refer to with @category,
+// in the order ACL CAT lists them
+var Categories = []string{
+	"keyspace", "read", "write", "set", "sortedset", "list", "hash", "string",
+	"bitmap", "hyperloglog", "geo", "stream", "pubsub", "admin", "fast", "slow",
+	"blocking", "dangerous", "connection", "transaction", "scripting",
+}
+
+// Permission is the kind of access a command needs to a key
+type Permission int
+
+const (
+	// PermissionRead is needed by commands reading the key's value
+	PermissionRead Permission = 1 << iota
+	// PermissionWrite is needed by commands modifying or deleting the key
+	PermissionWrite
+)
+
+// CommandLookup reports whether a command, or one of its subcommands if
+// subcommand isn't empty, exists. Names are lowercase.
+type CommandLookup func(command, subcommand string) bool
+
+// Errors of ACL SETUSER rules, worded as in Redis
+var (
+	errSyntax           = errors.New("Syntax error")
+	errUnknownCommand   = errors.New("Unknown command or category name in ACL")
+	errNoSuchPassword   = errors.New("The password you are trying to remove from the user does not exist")
+	errBadPasswordHash  = errors.New("The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters")
+	errKeysAfterAll     = errors.New("Adding a pattern after the * pattern (or the 'allkeys' flag) is not valid and does not have any effect. Try 'resetkeys' to start with an empty list of patterns")
+	errChannelsAfterAll = errors.New("Adding a pattern after the * pattern (or the 'allchannels' flag) is not valid and does not have any effect. Try 'resetchannels' to start with an empty list of channels")
+	errSelectors        = errors.New("Selectors are not supported")
+)
+
+// commandRule allows or denies a command, one of its subcommands or a
+// category of commands. The last rule matching a command decides whether a
+// user may run it.
+type commandRule struct {
+	allow      bool
+	category   string
+	command    string
+	subcommand string
+}
+
+// String returns the rule as written in ACL SETUSER
+func (r commandRule) String() string {
+	var b strings.Builder
+	if r.allow {
+		b.WriteByte('+')
+	} else {
+		b.WriteByte('-')
+	}
+	switch {
+	case r.category != "":
+		b.WriteString("@" + r.category)
+	case r.subcommand != "":
+		b.WriteString(r.command + "|" + r.subcommand)
+	default:
+		b.WriteString(r.command)
+	}
+	return b.String()
+}
+
+// keyPattern grants access to the keys matching a glob-style pattern
+type keyPattern struct {
+	pattern     string
+	permissions Permission
+}
+
+// String returns the pattern as written in ACL SETUSER
+func (k keyPattern) String() string {
+	switch k.permissions {
+	case PermissionRead:
+		return "%R~" + k.pattern
+	case PermissionWrite:
+		return "%W~" + k.pattern
+	default:
+		return "~" + k.pattern
+	}
+}
+
+// User is an ACL user. Users are immutable once created; ACL SETUSER
+// replaces a user with a modified copy, so a User may be used without locks.
+type User struct {
+	// Name is the name users authenticate with
+	Name string
+
+	enabled   bool
+	noPass    bool
+	passwords []string // SHA-256 digests in hex
+
+	commands []commandRule
+
+	allKeys bool
+	keys    []keyPattern
+
+	allChannels bool
+	channels    []string
+}
+
+// newUser creates a user that is disabled and may do nothing
+func newUser(name string) *User {
+	return &User{Name: name}
+}
+
+// newDefaultUser creates the default user, which needs no password and may
+// run every command on every key and channel
+func newDefaultUser() *User {
+	return &User{
+		Name:        DefaultUser,
+		enabled:     true,
+		noPass:      true,
+		commands:    []commandRule{{allow: true, category: "all"}},
+		allKeys:     true,
+		allChannels: true,
+	}
+}
+
+// clone returns a copy of u that can be modified without affecting u
+func (u *User) clone() *User {
+	c := *u
+	c.passwords = slices.Clone(u.passwords)
+	c.commands = slices.Clone(u.commands)
+	c.keys = slices.Clone(u.keys)
+	c.channels = slices.Clone(u.channels)
+	return &c
+}
+
+// Enabled reports whether the user may authenticate.
+func (u *User) Enabled() bool {
+	return u.enabled
+}
+
+// NoPass reports whether the user accepts any password.
+func (u *User) NoPass() bool {
+	return u.noPass
+}
+
+// CheckPassword reports whether password is one of the user's passwords,
+// comparing digests so that the time taken doesn't depend on the passwords.
+func (u *User) CheckPassword(password string) bool {
+	if u.noPass {
+		return true
+	}
+	given := hashPassword(password)
+	matched := false
+	for _, hash := range u.passwords {
+		if hashesEqual(given, hash) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// CanRun reports whether the user may run command, or its subcommand if
+// subcommand isn't empty, given the command's categories.
+func (u *User) CanRun(command, subcommand string, categories []string) bool {
+	allowed := false
+	for _, rule := range u.commands {
+		var matches bool
+		switch {
+		case rule.category == "all":
+			matches = true
+		case rule.category != "":
+			matches = slices.Contains(categories, rule.category)
+		default:
+			matches = rule.command == command && (rule.subcommand == "" || rule.subcommand == subcommand)
+		}
+		if matches {
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// CanAccessKey reports whether the user may access key with all of the
+// given permissions.
+func (u *User) CanAccessKey(key string, permissions Permission) bool {
+	if u.allKeys {
+		return true
+	}
+	for _, k := range u.keys {
+		if k.permissions&permissions == permissions && glob.Match(k.pattern, key, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flags returns the flags shown by ACL GETUSER
+func (u *User) Flags() []string {
+	flags := []string{"off"}
+	if u.enabled {
+		flags[0] = "on"
+	}
+	if u.noPass {
+		flags = append(flags, "nopass")
+	}
+	return flags
+}
+
+// Passwords returns the digests of the user's passwords.
+func (u *User) Passwords() []string {
+	return slices.Clone(u.passwords)
+}
+
+// CommandRules describes the commands the user may run, as shown by ACL
+// GETUSER. Rules overridden by a later +@all or -@all aren't kept.
+func (u *User) CommandRules() string {
+	rules := make([]string, 0, len(u.commands)+1)
+	if len(u.commands) == 0 || u.commands[0].category != "all" {
+		rules = append(rules, "-@all")
+	}
+	for _, rule := range u.commands {
+		rules = append(rules, rule.String())
+	}
+	return strings.Join(rules, " ")
+}
+
+// KeyRules describes the keys the user may access, as shown by ACL GETUSER.
+func (u *User) KeyRules() string {
+	if u.allKeys {
+		return "~*"
+	}
+	patterns := make([]string, len(u.keys))
+	for i, k := range u.keys {
+		patterns[i] = k.String()
+	}
+	return strings.Join(patterns, " ")
+}
+
+// ChannelRules describes the channels the user may access, as shown by ACL
+// GETUSER.
+func (u *User) ChannelRules() string {
+	if u.allChannels {
+		return "&*"
+	}
+	patterns := make([]string, len(u.channels))
+	for i, channel := range u.channels {
+		patterns[i] = "&" + channel
+	}
+	return strings.Join(patterns, " ")
+}
+
+// String describes the user as a line of ACL LIST or the ACL file, from
+// which the same user can be created again.
+func (u *User) String() string {
+	parts := []string{"user", u.Name}
+	parts = append(parts, u.Flags()...)
+	for _, hash := range u.passwords {
+		parts = append(parts, "#"+hash)
+	}
+	if keys := u.KeyRules(); keys != "" {
+		parts = append(parts, keys)
+	}
+	if !u.allChannels {
+		parts = append(parts, "resetchannels")
+	}
+	if channels := u.ChannelRules(); channels != "" {
+		parts = append(parts, channels)
+	}
+	parts = append(parts, u.CommandRules())
+	return strings.Join(parts, " ")
+}
+
+// apply changes the user according to a single ACL SETUSER rule.
+func (u *User) apply(rule string, lookup CommandLookup) error {
+	lower := strings.ToLower(rule)
+	switch {
+	case lower == "on":
+		u.enabled = true
+	case lower == "off":
+		u.enabled = false
+	case lower == "sanitize-payload", lower == "skip-sanitize-payload", lower == "clearselectors":
+		// Payload sanitization and selectors don't apply to this server
+	case lower == "nopass":
+		u.noPass = true
+		u.passwords = nil
+	case lower == "resetpass":
+		u.noPass = false
+		u.passwords = nil
+	case lower == "allkeys", rule == "~*":
+		u.allKeys = true
+		u.keys = nil
+	case lower == "resetkeys":
+		u.allKeys = false
+		u.keys = nil
+	case lower == "allchannels", rule == "&*":
+		u.allChannels = true
+		u.channels = nil
+	case lower == "resetchannels":
+		u.allChannels = false
+		u.channels = nil
+	case lower == "allcommands":
+		u.commands = []commandRule{{allow: true, category: "all"}}
+	case lower == "nocommands":
+		u.commands = nil
+	case lower == "reset":
+		*u = User{Name: u.Name}
+	case strings.HasPrefix(rule, ">"):
+		hash := hashPassword(rule[1:])
+		u.noPass = false
+		if !slices.Contains(u.passwords, hash) {
+			u.passwords = append(u.passwords, hash)
+		}
+	case strings.HasPrefix(rule, "#"):
+		if !validHash(rule[1:]) {
+			return errBadPasswordHash
+		}
+		u.noPass = false
+		if !slices.Contains(u.passwords, rule[1:]) {
+			u.passwords = append(u.passwords, rule[1:])
+		}
+	case strings.HasPrefix(rule, "<"):
+		return u.removePassword(hashPassword(rule[1:]))
+	case strings.HasPrefix(rule, "!"):
+		if !validHash(rule[1:]) {
+			return errBadPasswordHash
+		}
+		return u.removePassword(rule[1:])
+	case strings.HasPrefix(rule, "~"), strings.HasPrefix(rule, "%"):
+		return u.addKeyPattern(rule)
+	case strings.HasPrefix(rule, "&"):
+		if u.allChannels {
+			return errChannelsAfterAll
+		}
+		if !slices.Contains(u.channels, rule[1:]) {
+			u.channels = append(u.channels, rule[1:])
+		}
+	case strings.HasPrefix(rule, "+"), strings.HasPrefix(rule, "-"):
+		return u.addCommandRule(rule[0] == '+', lower[1:], lookup)
+	case strings.HasPrefix(rule, "("):
+		return errSelectors
+	default:
+		return errSyntax
+	}
+	return nil
+}
+
+// removePassword removes a password given by its digest
+func (u *User) removePassword(hash string) error {
+	i := slices.Index(u.passwords, hash)
+	if i < 0 {
+		return errNoSuchPassword
+	}
+	u.passwords = slices.Delete(u.passwords, i, i+1)
+	return nil
+}
+
+// addKeyPattern adds a ~pattern, or a %R~, %W~ or %RW~ pattern granting
+// only read or write access
+func (u *User) addKeyPattern(rule string) error {
+	permissions := PermissionRead | PermissionWrite
+	pattern := rule[1:]
+	if rule[0] == '%' {
+		flags, rest, ok := strings.Cut(rule[1:], "~")
+		if !ok || flags == "" {
+			return errSyntax
+		}
+		permissions = 0
+		for _, flag := range strings.ToUpper(flags) {
+			switch flag {
+			case 'R':
+				permissions |= PermissionRead
+			case 'W':
+				permissions |= PermissionWrite
+			default:
+				return errSyntax
+			}
+		}
+		pattern = rest
+	}
+
+	if u.allKeys {
+		return errKeysAfterAll
+	}
+	if pattern == "*" && permissions == PermissionRead|PermissionWrite {
+		u.allKeys = true
+		u.keys = nil
+		return nil
+	}
+	k := keyPattern{pattern: pattern, permissions: permissions}
+	if !slices.Contains(u.keys, k) {
+		u.keys = append(u.keys, k)
+	}
+	return nil
+}
+
+// addCommandRule adds a +command, +command|subcommand or +@category rule,
+// or its - counterpart
+func (u *User) addCommandRule(allow bool, name string, lookup CommandLookup) error {
+	rule := commandRule{allow: allow}
+	if category, ok := strings.CutPrefix(name, "@"); ok {
+		if category != "all" && !slices.Contains(Categories, category) {
+			return errUnknownCommand
+		}
+		if category == "all" {
+			// Every earlier rule is overridden
+			u.commands = nil
+		}
+		rule.category = category
+	} else {
+		rule.command, rule.subcommand, _ = strings.Cut(name, "|")
+		if rule.command == "" || strings.Contains(rule.subcommand, "|") || !lookup(rule.command, rule.subcommand) {
+			return errUnknownCommand
+		}
+	}
+	u.commands = append(u.commands, rule)
+	return nil
+}
+
+// hashPassword returns the digest passwords are stored as
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashesEqual compares two digests in constant time
+func hashesEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// validHash reports whether hash is a SHA-256 digest in lowercase hex
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for i := 0; i < len(hash); i++ {
+		if !(hash[i] >= '0' && hash[i] <= '9' || hash[i] >= 'a' && hash[i] <= 'f') {
+			return false
+		}
+	}
+	return true
+}
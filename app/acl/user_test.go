@@ -0,0 +1,176 @@
+package acl
+
+import (
+	"strings"
+	"testing"
+)
+
+// knownCommands is a CommandLookup accepting a few commands
+func knownCommands(command, subcommand string) bool {
+	switch command {
+	case "get", "set":
+		return subcommand == ""
+	case "config":
+		return subcommand == "" || subcommand == "get" || subcommand == "set"
+	}
+	return false
+}
+
+// userWith creates a user from rules, failing the test if one is invalid
+func userWith(t *testing.T, rules string) *User {
+	t.Helper()
+	user := newUser("alice")
+	for _, rule := range strings.Fields(rules) {
+		if err := user.apply(rule, knownCommands); err != nil {
+			t.Fatalf("Unexpected error applying %q: %v", rule, err)
+		}
+	}
+	return user
+}
+
+func TestUser_CanRun(t *testing.T) {
+	tests := []struct {
+		rules      string
+		command    string
+		subcommand string
+		categories []string
+		expected   bool
+	}{
+		{rules: "", command: "get", categories: []string{"read"}, expected: false},
+		{rules: "+@all", command: "get", categories: []string{"read"}, expected: true},
+		{rules: "+@all -get", command: "get", categories: []string{"read"}, expected: false},
+		{rules: "+@all -get", command: "set", categories: []string{"write"}, expected: true},
+		{rules: "+@read", command: "get", categories: []string{"read"}, expected: true},
+		{rules: "+@read", command: "set", categories: []string{"write"}, expected: false},
+		{rules: "-@all +get", command: "get", categories: []string{"read"}, expected: true},
+		{rules: "+get -@read", command: "get", categories: []string{"read"}, expected: false},
+		{rules: "+config", command: "config", subcommand: "set", categories: []string{"admin"}, expected: true},
+		{rules: "+config|get", command: "config", subcommand: "get", categories: []string{"admin"}, expected: true},
+		{rules: "+config|get", command: "config", subcommand: "set", categories: []string{"admin"}, expected: false},
+		{rules: "+config -config|set", command: "config", subcommand: "set", categories: []string{"admin"}, expected: false},
+		{rules: "+get nocommands", command: "get", categories: []string{"read"}, expected: false},
+		{rules: "allcommands", command: "set", categories: []string{"write"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		user := userWith(t, tt.rules)
+		if result := user.CanRun(tt.command, tt.subcommand, tt.categories); result != tt.expected {
+			t.Errorf("Rules %q: expected CanRun(%q, %q) to be %v", tt.rules, tt.command, tt.subcommand, tt.expected)
+		}
+	}
+}
+
+func TestUser_CanAccessKey(t *testing.T) {
+	read, write := PermissionRead, PermissionWrite
+	tests := []struct {
+		rules       string
+		key         string
+		permissions Permission
+		expected    bool
+	}{
+		{rules: "", key: "foo", permissions: read, expected: false},
+		{rules: "~*", key: "foo", permissions: read | write, expected: true},
+		{rules: "allkeys", key: "foo", permissions: write, expected: true},
+		{rules: "~cache:*", key: "cache:1", permissions: read | write, expected: true},
+		{rules: "~cache:*", key: "other", permissions: read, expected: false},
+		{rules: "%R~cache:*", key: "cache:1", permissions: read, expected: true},
+		{rules: "%R~cache:*", key: "cache:1", permissions: write, expected: false},
+		{rules: "%W~cache:*", key: "cache:1", permissions: write, expected: true},
+		{rules: "%R~cache:* %W~cache:*", key: "cache:1", permissions: read | write, expected: false},
+		{rules: "%RW~cache:*", key: "cache:1", permissions: read | write, expected: true},
+		{rules: "%R~cache:*", key: "cache:1", permissions: 0, expected: true},
+		{rules: "~cache:* resetkeys", key: "cache:1", permissions: read, expected: false},
+	}
+
+	for _, tt := range tests {
+		user := userWith(t, tt.rules)
+		if result := user.CanAccessKey(tt.key, tt.permissions); result != tt.expected {
+			t.Errorf("Rules %q: expected CanAccessKey(%q, %d) to be %v", tt.rules, tt.key, tt.permissions, tt.expected)
+		}
+	}
+}
+
+func TestUser_Passwords(t *testing.T) {
+	user := userWith(t, "on >secret >other")
+	if !user.CheckPassword("secret") || !user.CheckPassword("other") || user.CheckPassword("wrong") {
+		t.Errorf("Expected both passwords, and only them, to be accepted")
+	}
+
+	user = userWith(t, "on >secret <secret")
+	if user.CheckPassword("secret") || len(user.Passwords()) != 0 {
+		t.Errorf("Expected the password to be removed")
+	}
+
+	hash := hashPassword("secret")
+	user = userWith(t, "on #"+hash)
+	if !user.CheckPassword("secret") || user.Passwords()[0] != hash {
+		t.Errorf("Expected the password to be set from its hash")
+	}
+	user = userWith(t, "on #"+hash+" !"+hash)
+	if user.CheckPassword("secret") {
+		t.Errorf("Expected the password to be removed by its hash")
+	}
+
+	user = userWith(t, ">secret nopass")
+	if !user.CheckPassword("anything") || len(user.Passwords()) != 0 {
+		t.Errorf("Expected nopass to accept any password")
+	}
+	user = userWith(t, "nopass resetpass")
+	if user.CheckPassword("anything") {
+		t.Errorf("Expected resetpass to accept no password")
+	}
+}
+
+func TestUser_ApplyErrors(t *testing.T) {
+	tests := []struct {
+		rules  string
+		errMsg string
+	}{
+		{rules: "bogus", errMsg: "Syntax error"},
+		{rules: "+unknown", errMsg: "Unknown command or category name in ACL"},
+		{rules: "+@unknown", errMsg: "Unknown command or category name in ACL"},
+		{rules: "+get|sub", errMsg: "Unknown command or category name in ACL"},
+		{rules: "<missing", errMsg: "The password you are trying to remove from the user does not exist"},
+		{rules: "#abc", errMsg: "The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters"},
+		{rules: "~* ~foo", errMsg: "Adding a pattern after the * pattern (or the 'allkeys' flag) is not valid and does not have any effect. Try 'resetkeys' to start with an empty list of patterns"},
+		{rules: "&* &foo", errMsg: "Adding a pattern after the * pattern (or the 'allchannels' flag) is not valid and does not have any effect. Try 'resetchannels' to start with an empty list of channels"},
+		{rules: "%X~foo", errMsg: "Syntax error"},
+		{rules: "(~foo +get)", errMsg: "Selectors are not supported"},
+	}
+
+	for _, tt := range tests {
+		user := newUser("alice")
+		var err error
+		for _, rule := range strings.Fields(tt.rules) {
+			if err = user.apply(rule, knownCommands); err != nil {
+				break
+			}
+		}
+		if err == nil || err.Error() != tt.errMsg {
+			t.Errorf("Rules %q: expected error %q, got %v", tt.rules, tt.errMsg, err)
+		}
+	}
+}
+
+func TestUser_String(t *testing.T) {
+	tests := []struct {
+		rules    string
+		expected string
+	}{
+		{rules: "", expected: "user alice off resetchannels -@all"},
+		{rules: "on nopass ~* &* +@all", expected: "user alice on nopass ~* &* +@all"},
+		{rules: "on >secret ~cache:* %R~logs:* &news.* +@read -get", expected: "user alice on #" + hashPassword("secret") + " ~cache:* %R~logs:* resetchannels &news.* -@all +@read -get"},
+		{rules: "+get +@all -set", expected: "user alice off resetchannels +@all -set"},
+		{rules: "+@all ~* reset", expected: "user alice off resetchannels -@all"},
+	}
+
+	for _, tt := range tests {
+		if result := userWith(t, tt.rules).String(); result != tt.expected {
+			t.Errorf("Rules %q: expected %q, got %q", tt.rules, tt.expected, result)
+		}
+	}
+
+	if result := newDefaultUser().String(); result != "user default on nopass ~* &* +@all" {
+		t.Errorf("Unexpected default user %q", result)
+	}
+}
@@ -0,0 +1,243 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// aclHelp is the reply to ACL HELP
+var aclHelp = []string{
+	"ACL <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"CAT [<category>]",
+	"    List all commands that belong to <category>, or all command categories",
+	"    when no category is specified.",
+	"DELUSER <username> [<username> ...]",
+	"    Delete a list of users.",
+	"DRYRUN <username> <command> [<arg> ...]",
+	"    Returns whether the user can execute the given command without executing the command.",
+	"GETUSER <username>",
+	"    Get the user's details.",
+	"LIST",
+	"    Show users details in config file format.",
+	"LOAD",
+	"    Reload users from the ACL file.",
+	"LOG [<count> | RESET]",
+	"    Show the ACL log entries.",
+	"SAVE",
+	"    Save the current config to the ACL file.",
+	"SETUSER <username> <attribute> [<attribute> ...]",
+	"    Create or modify a user with the specified attributes.",
+	"USERS",
+	"    List all the registered usernames.",
+	"WHOAMI",
+	"    Return the current connection username.",
+	"HELP",
+	"    Print this help.",
+}
+
+// noACLFileMessage is the error of ACL SAVE and LOAD without an aclfile setting
+const noACLFileMessage = "This Redis instance is not configured to use an ACL file. You may want to specify users via the ACL SETUSER command and then issue a CONFIG REWRITE (assuming you have a Redis configuration file set) in order to store users in the Redis configuration."
+
+// ACLCommand implements the ACL command
+type ACLCommand struct {
+	registry *Registry
+	clients  *Clients
+}
+
+// NewACLCommand creates a new ACL command. Rules are checked against the
+// commands of registry, and the clients of deleted users are disconnected.
+func NewACLCommand(registry *Registry, clients *Clients) *ACLCommand {
+	return &ACLCommand{registry: registry, clients: clients}
+}
+
+// Name returns the command name
+func (c *ACLCommand) Name() string {
+	return "ACL"
+}
+
+// Execute handles the ACL command
+func (c *ACLCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	name := args[0]
+	subcommand := strings.ToUpper(name)
+	args = args[1:]
+	wrongArity := errors.New(errors.ErrorTypeCommand, fmt.Sprintf("wrong number of arguments for 'acl|%s' command", strings.ToLower(name)))
+	a := acl.GetACL()
+
+	switch subcommand {
+	case "CAT":
+		if len(args) > 1 {
+			return resp.Reply{}, wrongArity
+		}
+		return c.cat(args)
+	case "SETUSER":
+		if err := a.SetUser(args[0], args[1:], c.registry.CommandExists); err != nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, err.Error())
+		}
+		return resp.SimpleString("OK"), nil
+	case "GETUSER":
+		user, ok := a.User(args[0])
+		if !ok {
+			return resp.Null(), nil
+		}
+		return resp.Map([]resp.Reply{
+			resp.BulkString("flags"), resp.BulkStrings(user.Flags()),
+			resp.BulkString("passwords"), resp.BulkStrings(user.Passwords()),
+			resp.BulkString("commands"), resp.BulkString(user.CommandRules()),
+			resp.BulkString("keys"), resp.BulkString(user.KeyRules()),
+			resp.BulkString("channels"), resp.BulkString(user.ChannelRules()),
+			resp.BulkString("selectors"), resp.Array(nil),
+		}), nil
+	case "DELUSER":
+		deleted, err := a.DeleteUsers(args)
+		if err != nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, err.Error())
+		}
+		c.disconnectDeletedUsers(client)
+		return resp.Integer(int64(deleted)), nil
+	case "LIST":
+		return resp.BulkStrings(a.List()), nil
+	case "USERS":
+		return resp.BulkStrings(a.Users()), nil
+	case "WHOAMI":
+		return resp.BulkString(client.User), nil
+	case "DRYRUN":
+		return c.dryRun(args)
+	case "LOG":
+		if len(args) > 1 {
+			return resp.Reply{}, wrongArity
+		}
+		return c.log(args)
+	case "SAVE":
+		path, _ := config.Get("aclfile")
+		if path == "" {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, noACLFileMessage)
+		}
+		if err := a.Save(path); err != nil {
+			log.Printf("Error saving ACLs to %s: %v", path, err)
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "There was an error trying to save the ACLs. Please check the server logs for more information")
+		}
+		return resp.SimpleString("OK"), nil
+	case "LOAD":
+		path, _ := config.Get("aclfile")
+		if path == "" {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, noACLFileMessage)
+		}
+		if err := a.Load(path, c.registry.CommandExists); err != nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, err.Error())
+		}
+		c.disconnectDeletedUsers(client)
+		return resp.SimpleString("OK"), nil
+	case "HELP":
+		lines := make([]resp.Reply, len(aclHelp))
+		for i, line := range aclHelp {
+			lines[i] = resp.SimpleString(line)
+		}
+		return resp.Array(lines), nil
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("unknown subcommand '%s'. Try ACL HELP.", name))
+	}
+}
+
+// cat handles ACL CAT [category]
+func (c *ACLCommand) cat(args []string) (resp.Reply, error) {
+	if len(args) == 0 {
+		return resp.BulkStrings(acl.Categories), nil
+	}
+
+	category := strings.ToLower(args[0])
+	if !slices.Contains(acl.Categories, category) {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("Unknown category '%s'", args[0]))
+	}
+	var names []string
+	for _, name := range c.registry.Names() {
+		meta, _ := c.registry.Metadata(name)
+		if meta.Subcommands == nil && slices.Contains(meta.AllCategories(), category) {
+			names = append(names, strings.ToLower(name))
+		}
+		for _, sub := range slices.Sorted(maps.Keys(meta.Subcommands)) {
+			if slices.Contains(meta.Subcommands[sub].AllCategories(), category) {
+				names = append(names, strings.ToLower(name)+"|"+sub)
+			}
+		}
+	}
+	return resp.BulkStrings(names), nil
+}
+
+// dryRun handles ACL DRYRUN username command [arg ...]
+func (c *ACLCommand) dryRun(args []string) (resp.Reply, error) {
+	user, ok := acl.GetACL().User(args[0])
+	if !ok {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("User '%s' not found", args[0]))
+	}
+	name := strings.ToUpper(args[1])
+	meta, ok := c.registry.Metadata(name)
+	if !ok {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("Command '%s' not found", args[1]))
+	}
+
+	if _, _, err := checkPermissions(user, meta, name, args[2:]); err != nil {
+		return resp.BulkString(err.Error()), nil
+	}
+	return resp.SimpleString("OK"), nil
+}
+
+// log handles ACL LOG [count | RESET]
+func (c *ACLCommand) log(args []string) (resp.Reply, error) {
+	aclLog := acl.GetACL().Log()
+	count := 10
+	if len(args) == 1 {
+		if strings.EqualFold(args[0], "RESET") {
+			aclLog.Reset()
+			return resp.SimpleString("OK"), nil
+		}
+		n, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "value is not an integer or out of range")
+		}
+		if n < 0 {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "value is out of range, must be positive")
+		}
+		count = int(min(n, math.MaxInt32))
+	}
+
+	now := time.Now()
+	entries := aclLog.Entries(count)
+	replies := make([]resp.Reply, len(entries))
+	for i, entry := range entries {
+		replies[i] = resp.Map([]resp.Reply{
+			resp.BulkString("count"), resp.Integer(entry.Count),
+			resp.BulkString("reason"), resp.BulkString(entry.Reason),
+			resp.BulkString("context"), resp.BulkString(entry.Context),
+			resp.BulkString("object"), resp.BulkString(entry.Object),
+			resp.BulkString("username"), resp.BulkString(entry.Username),
+			resp.BulkString("age-seconds"), resp.Double(now.Sub(entry.Created).Seconds()),
+			resp.BulkString("client-info"), resp.BulkString(entry.ClientInfo),
+			resp.BulkString("entry-id"), resp.Integer(entry.ID),
+			resp.BulkString("timestamp-created"), resp.Integer(entry.Created.UnixMilli()),
+			resp.BulkString("timestamp-last-updated"), resp.Integer(entry.Updated.UnixMilli()),
+		})
+	}
+	return resp.Array(replies), nil
+}
+
+// disconnectDeletedUsers closes the connections authenticated as users that
+// no longer exist. The calling client is closed once it has been replied to.
+func (c *ACLCommand) disconnectDeletedUsers(client *Client) {
+	a := acl.GetACL()
+	for _, other := range c.clients.All() {
+		if _, ok := a.User(other.authUser()); !ok {
+			killClient(client, other)
+		}
+	}
+}
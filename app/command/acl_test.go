@@ -0,0 +1,188 @@
+package command
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// newACLTestCommand creates an ACL command for a registry holding a few
+// commands, and removes the users and log entries the test creates
+func newACLTestCommand(t *testing.T) (*ACLCommand, *Clients) {
+	t.Helper()
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	registry.Register(NewSetCommand())
+	registry.Register(NewPingCommand())
+	registry.Register(NewClientCommand(NewClients()))
+	clients := NewClients()
+	t.Cleanup(func() {
+		a := acl.GetACL()
+		for _, name := range a.Users() {
+			if name != DefaultUser {
+				a.DeleteUsers([]string{name})
+			}
+		}
+		a.Log().Reset()
+	})
+	return NewACLCommand(registry, clients), clients
+}
+
+func TestACLCommand_Name(t *testing.T) {
+	cmd := NewACLCommand(NewRegistry(), NewClients())
+	if cmd.Name() != "ACL" {
+		t.Errorf("Expected command name to be 'ACL', got %s", cmd.Name())
+	}
+}
+
+func TestACLCommand_Execute(t *testing.T) {
+	cmd, _ := newACLTestCommand(t)
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "no subcommand", args: []string{}, errMsg: "wrong number of arguments for 'acl' command"},
+		{name: "unknown subcommand", args: []string{"FOO"}, errMsg: "unknown subcommand 'FOO'. Try ACL HELP."},
+		{name: "setuser arity", args: []string{"SETUSER"}, errMsg: "wrong number of arguments for 'acl|setuser' command"},
+		{name: "setuser", args: []string{"SETUSER", "alice", "on", "nopass", "~cache:*", "+get", "+client|id"}, expected: "+OK\r\n"},
+		{name: "setuser unknown command", args: []string{"SETUSER", "alice", "+nosuchcommand"}, errMsg: "Error in ACL SETUSER modifier '+nosuchcommand': Unknown command or category name in ACL"},
+		{name: "setuser unknown subcommand", args: []string{"SETUSER", "alice", "+client|nosuch"}, errMsg: "Error in ACL SETUSER modifier '+client|nosuch': Unknown command or category name in ACL"},
+		{name: "users", args: []string{"USERS"}, expected: "*2\r\n$5\r\nalice\r\n$7\r\ndefault\r\n"},
+		{name: "list", args: []string{"LIST"}, expected: "*2\r\n$65\r\nuser alice on nopass ~cache:* resetchannels -@all +get +client|id\r\n$34\r\nuser default on nopass ~* &* +@all\r\n"},
+		{name: "getuser missing", args: []string{"GETUSER", "nobody"}, expected: "$-1\r\n"},
+		{name: "whoami", args: []string{"WHOAMI"}, expected: "$7\r\ndefault\r\n"},
+		{name: "dryrun allowed", args: []string{"DRYRUN", "alice", "GET", "cache:1"}, expected: "+OK\r\n"},
+		{name: "dryrun command denied", args: []string{"DRYRUN", "alice", "SET", "cache:1", "x"}, expected: "$54\r\nUser alice has no permissions to run the 'set' command\r\n"},
+		{name: "dryrun key denied", args: []string{"DRYRUN", "alice", "GET", "other"}, expected: "$55\r\nUser alice has no permissions to access the 'other' key\r\n"},
+		{name: "dryrun unknown user", args: []string{"DRYRUN", "nobody", "GET", "x"}, errMsg: "User 'nobody' not found"},
+		{name: "dryrun unknown command", args: []string{"DRYRUN", "alice", "NOSUCH"}, errMsg: "Command 'NOSUCH' not found"},
+		{name: "cat unknown category", args: []string{"CAT", "nosuch"}, errMsg: "Unknown category 'nosuch'"},
+		{name: "cat category", args: []string{"CAT", "string"}, expected: "*2\r\n$3\r\nget\r\n$3\r\nset\r\n"},
+		{name: "cat subcommands", args: []string{"CAT", "admin"}, expected: "*5\r\n$11\r\nclient|kill\r\n$11\r\nclient|list\r\n$15\r\nclient|no-evict\r\n$12\r\nclient|pause\r\n$14\r\nclient|unpause\r\n"},
+		{name: "deluser default", args: []string{"DELUSER", "default"}, errMsg: "The 'default' user cannot be removed"},
+		{name: "deluser", args: []string{"DELUSER", "alice", "nobody"}, expected: ":1\r\n"},
+		{name: "log count", args: []string{"LOG", "-1"}, errMsg: "value is out of range, must be positive"},
+		{name: "log reset", args: []string{"LOG", "RESET"}, expected: "+OK\r\n"},
+		{name: "save without aclfile", args: []string{"SAVE"}, errMsg: noACLFileMessage},
+	})
+}
+
+func TestACLCommand_GetUser(t *testing.T) {
+	cmd, _ := newACLTestCommand(t)
+	client := NewClient(io.Discard)
+
+	cmd.Execute(client, []string{"SETUSER", "alice", "on", ">secret", "%R~logs:*", "&news", "+@read"})
+	reply, err := cmd.Execute(client, []string{"GETUSER", "alice"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := encodeRESP2(reply)
+	for _, field := range []string{
+		"$5\r\nflags\r\n*1\r\n$2\r\non\r\n",
+		"$9\r\npasswords\r\n*1\r\n$64\r\n2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b\r\n",
+		"$8\r\ncommands\r\n$12\r\n-@all +@read\r\n",
+		"$4\r\nkeys\r\n$9\r\n%R~logs:*\r\n",
+		"$8\r\nchannels\r\n$5\r\n&news\r\n",
+		"$9\r\nselectors\r\n*0\r\n",
+	} {
+		if !strings.Contains(result, field) {
+			t.Errorf("Expected ACL GETUSER to contain %q, got %q", field, result)
+		}
+	}
+}
+
+func TestACLCommand_Log(t *testing.T) {
+	cmd, _ := newACLTestCommand(t)
+	client := NewClient(io.Discard)
+
+	cmd.Execute(client, []string{"SETUSER", "alice", "on", "nopass", "+get", "~cache:*"})
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	registry.Register(NewSetCommand())
+	alice := NewClient(io.Discard)
+	alice.SetUser("alice")
+
+	if _, err := registry.Execute(alice, "SET", []string{"cache:1", "x"}); err == nil || err.Error() != "User alice has no permissions to run the 'set' command" {
+		t.Errorf("Expected SET to be denied, got %v", err)
+	}
+	if _, err := registry.Execute(alice, "GET", []string{"other"}); err == nil || err.Error() != "No permissions to access a key" {
+		t.Errorf("Expected the key to be denied, got %v", err)
+	}
+	if _, err := registry.Execute(alice, "GET", []string{"cache:1"}); err != nil {
+		t.Errorf("Expected GET to be allowed, got %v", err)
+	}
+
+	reply, err := cmd.Execute(client, []string{"LOG"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(reply.Elems) != 2 {
+		t.Fatalf("Expected two entries, got %d", len(reply.Elems))
+	}
+	latest := encodeReply(reply.Elems[0], resp.RESP3)
+	for _, field := range []string{"$6\r\nreason\r\n$3\r\nkey\r\n", "$6\r\nobject\r\n$5\r\nother\r\n", "$8\r\nusername\r\n$5\r\nalice\r\n", "$7\r\ncontext\r\n$8\r\ntoplevel\r\n"} {
+		if !strings.Contains(latest, field) {
+			t.Errorf("Expected the latest entry to contain %q, got %q", field, latest)
+		}
+	}
+	if reply, _ := cmd.Execute(client, []string{"LOG", "1"}); len(reply.Elems) != 1 {
+		t.Errorf("Expected a single entry, got %d", len(reply.Elems))
+	}
+}
+
+func TestACLCommand_DelUserDisconnects(t *testing.T) {
+	cmd, clients := newACLTestCommand(t)
+	client, alice := NewClient(io.Discard), NewClient(io.Discard)
+	clients.Add(client)
+	clients.Add(alice)
+
+	cmd.Execute(client, []string{"SETUSER", "alice", "on", "nopass"})
+	alice.SetUser("alice")
+	if reply, _ := cmd.Execute(client, []string{"DELUSER", "alice"}); encodeRESP2(reply) != ":1\r\n" {
+		t.Fatalf("Expected alice to be deleted, got %q", encodeRESP2(reply))
+	}
+	if !alice.Killed() || client.Killed() {
+		t.Errorf("Expected only alice's connection to be closed")
+	}
+}
+
+func TestACLCommand_SaveAndLoad(t *testing.T) {
+	cmd, clients := newACLTestCommand(t)
+	path := filepath.Join(t.TempDir(), "users.acl")
+	config.SetConfig("aclfile", path)
+	defer config.SetConfig("aclfile", "")
+
+	client, alice := NewClient(io.Discard), NewClient(io.Discard)
+	clients.Add(alice)
+	cmd.Execute(client, []string{"SETUSER", "alice", "on", ">secret", "~*", "+@all"})
+	alice.SetUser("alice")
+	if reply, err := cmd.Execute(client, []string{"SAVE"}); err != nil || encodeRESP2(reply) != "+OK\r\n" {
+		t.Fatalf("Expected the users to be saved, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "user alice on #") || !strings.HasSuffix(string(data), "\nuser default on nopass ~* &* +@all\n") {
+		t.Errorf("Unexpected ACL file %q", data)
+	}
+
+	// Loading replaces the users, disconnecting those that no longer exist
+	cmd.Execute(client, []string{"SETUSER", "bob", "on"})
+	os.WriteFile(path, []byte("user default on nopass ~* &* +@all\nuser bob off\n"), 0o644)
+	if reply, err := cmd.Execute(client, []string{"LOAD"}); err != nil || encodeRESP2(reply) != "+OK\r\n" {
+		t.Fatalf("Expected the users to be loaded, got %v", err)
+	}
+	if users := acl.GetACL().Users(); len(users) != 2 || users[0] != "bob" {
+		t.Errorf("Expected bob and default, got %q", users)
+	}
+	if !alice.Killed() {
+		t.Errorf("Expected alice's connection to be closed")
+	}
+
+	os.WriteFile(path, []byte("user bob +nosuch\n"), 0o644)
+	if _, err := cmd.Execute(client, []string{"LOAD"}); err == nil || err.Error() != path+":1: Unknown command or category name in ACL" {
+		t.Errorf("Expected the invalid line to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// AuthCommand implements the AUTH command
+type AuthCommand struct{}
+
+// NewAuthCommand creates a new AUTH command
+func NewAuthCommand() *AuthCommand {
+	return &AuthCommand{}
+}
+
+// Name returns the command name
+func (c *AuthCommand) Name() string {
+	return "AUTH"
+}
+
+// Execute handles AUTH [username] password. A failed attempt leaves the
+// connection authenticated as it was before.
+func (c *AuthCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	if len(args) > 2 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	username, password := DefaultUser, args[0]
+	if len(args) == 2 {
+		username, password = args[0], args[1]
+	} else if defaultUser, _ := acl.GetACL().User(DefaultUser); defaultUser.NoPass() {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+
+	if err := authenticate(client, username, password); err != nil {
+		return resp.Reply{}, err
+	}
+	return resp.SimpleString("OK"), nil
+}
+
+// authenticate authenticates client as username if password is one of the
+// user's passwords, as AUTH and HELLO AUTH do. Failures are recorded in the
+// ACL log.
+func authenticate(client *Client, username, password string) error {
+	if _, ok := acl.GetACL().Authenticate(username, password); !ok {
+		acl.GetACL().Log().Add(acl.ReasonAuth, "AUTH", username, client.Info())
+		return errors.NewWithCode("WRONGPASS", "invalid username-password pair or user is disabled.")
+	}
+	client.authenticated = true
+	client.SetUser(username)
+	return nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"io"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+func TestAuthCommand_Name(t *testing.T) {
+	cmd := NewAuthCommand()
+	if cmd.Name() != "AUTH" {
+		t.Errorf("Expected command name to be 'AUTH', got %s", cmd.Name())
+	}
+}
+
+func TestAuthCommand_Execute(t *testing.T) {
+	cmd := NewAuthCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'auth' command"},
+		{name: "too many arguments", args: []string{"default", "secret", "extra"}, errMsg: "syntax error"},
+		{name: "no password configured", args: []string{"secret"}, errMsg: "AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?"},
+		{name: "any password without requirepass", args: []string{"default", "anything"}, expected: "+OK\r\n"},
+		{name: "unknown user", args: []string{"alice", "secret"}, errMsg: "invalid username-password pair or user is disabled."},
+	})
+}
+
+func TestAuthCommand_RequirePass(t *testing.T) {
+	config.SetConfig("requirepass", "secret")
+	defer config.SetConfig("requirepass", "")
+
+	cmd := NewAuthCommand()
+	client := NewClient(io.Discard)
+	if client.Authenticated() {
+		t.Fatalf("Expected new clients to need to authenticate once requirepass is set")
+	}
+
+	for _, args := range [][]string{{"wrong"}, {"secre"}, {"secret2"}, {"default", "wrong"}, {"alice", "secret"}} {
+		if _, err := cmd.Execute(client, args); err == nil || err.Error() != "invalid username-password pair or user is disabled." {
+			t.Errorf("Expected AUTH %q to fail, got %v", args, err)
+		}
+	}
+	if client.Authenticated() {
+		t.Fatalf("Expected the client to stay unauthenticated")
+	}
+
+	for _, args := range [][]string{{"secret"}, {"default", "secret"}} {
+		reply, err := cmd.Execute(client, args)
+		if err != nil || encodeRESP2(reply) != "+OK\r\n" {
+			t.Errorf("Expected AUTH %q to succeed, got %v", args, err)
+		}
+		if !client.Authenticated() || client.User != DefaultUser {
+			t.Errorf("Expected the client to be authenticated as the default user")
+		}
+	}
+
+	// A failed attempt doesn't undo an earlier success
+	cmd.Execute(client, []string{"wrong"})
+	if !client.Authenticated() {
+		t.Errorf("Expected the client to stay authenticated after a failed AUTH")
+	}
+}
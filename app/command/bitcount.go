@@ -0,0 +1,69 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// BitCountCommand implements the BITCOUNT command
+type BitCountCommand struct {
+}
+
+// NewBitCountCommand creates a new BITCOUNT command
+func NewBitCountCommand() *BitCountCommand {
+	return &BitCountCommand{}
+}
+
+// Name returns the command name
+func (c *BitCountCommand) Name() string {
+	return "BITCOUNT"
+}
+
+// Execute handles the BITCOUNT command
+func (c *BitCountCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	var start, end int64
+	hasRange, isBit := false, false
+	switch len(args) {
+	case 1:
+	case 3, 4:
+		var err error
+		if start, err = parseInteger(args[1]); err != nil {
+			return resp.Reply{}, err
+		}
+		if end, err = parseInteger(args[2]); err != nil {
+			return resp.Reply{}, err
+		}
+		if len(args) == 4 {
+			if isBit, err = parseBitRangeUnit(args[3]); err != nil {
+				return resp.Reply{}, err
+			}
+		}
+		hasRange = true
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	var count int64
+	err := client.DB.ReadString(args[0], func(value []byte, exists bool) {
+		length := int64(len(value))
+		if !hasRange {
+			start, end = 0, length-1
+		} else if isBit {
+			length *= 8
+		}
+
+		startPos, endPos, ok := normalizeRange(start, end, length)
+		if !ok {
+			return
+		}
+		if !isBit {
+			startPos, endPos = startPos*8, endPos*8+7
+		}
+		count = countBits(value, startPos, endPos)
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(count), nil
+}
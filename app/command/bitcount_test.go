@@ -0,0 +1,52 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestBitCountCommand_Name(t *testing.T) {
+	cmd := NewBitCountCommand()
+	if cmd.Name() != "BITCOUNT" {
+		t.Errorf("Expected command name to be 'BITCOUNT', got %s", cmd.Name())
+	}
+}
+
+func TestBitCountCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	cmd := NewBitCountCommand()
+
+	storeInstance.Set("bitcount-key", "foobar", 0)
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "whole string", args: []string{"bitcount-key"}, expected: ":26\r\n"},
+		{name: "first byte", args: []string{"bitcount-key", "0", "0"}, expected: ":4\r\n"},
+		{name: "second byte", args: []string{"bitcount-key", "1", "1"}, expected: ":6\r\n"},
+		{name: "negative byte range", args: []string{"bitcount-key", "-2", "-1"}, expected: ":7\r\n"},
+		{name: "explicit byte unit", args: []string{"bitcount-key", "1", "1", "BYTE"}, expected: ":6\r\n"},
+		{name: "bit range", args: []string{"bitcount-key", "5", "30", "BIT"}, expected: ":17\r\n"},
+		{name: "bit range within one byte", args: []string{"bitcount-key", "1", "3", "bit"}, expected: ":2\r\n"},
+		{name: "start after end", args: []string{"bitcount-key", "4", "2"}, expected: ":0\r\n"},
+		{name: "end past string", args: []string{"bitcount-key", "0", "100"}, expected: ":26\r\n"},
+		{name: "missing key", args: []string{"bitcount-missing"}, expected: ":0\r\n"},
+		{name: "start without end", args: []string{"bitcount-key", "0"}, errMsg: "syntax error"},
+		{name: "invalid unit", args: []string{"bitcount-key", "0", "1", "WORD"}, errMsg: "syntax error"},
+		{name: "invalid start", args: []string{"bitcount-key", "x", "1"}, errMsg: "value is not an integer or out of range"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'bitcount' command"},
+	})
+}
+
+func TestCountBits(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = 0xff
+	}
+
+	if got := countBits(data, 0, 799); got != 800 {
+		t.Errorf("Expected 800 set bits, got %d", got)
+	}
+	if got := countBits(data, 3, 796); got != 794 {
+		t.Errorf("Expected 794 set bits, got %d", got)
+	}
+}
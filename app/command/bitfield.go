@@ -0,0 +1,300 @@
+package command
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// bitfieldOpcode identifies a BITFIELD subcommand
+type bitfieldOpcode int
+
+const (
+	bitfieldGet bitfieldOpcode = iota
+	bitfieldSet
+	bitfieldIncrBy
+)
+
+// overflowType identifies how BITFIELD handles SET and INCRBY overflows
+type overflowType int
+
+const (
+	overflowWrap overflowType = iota
+	overflowSat
+	overflowFail
+)
+
+// bitfieldOp is a single parsed BITFIELD operation
+type bitfieldOp struct {
+	opcode   bitfieldOpcode
+	offset   int64
+	width    int64
+	signed   bool
+	value    int64
+	overflow overflowType
+}
+
+// BitFieldCommand implements the BITFIELD and BITFIELD_RO commands
+type BitFieldCommand struct {
+	readOnly bool
+}
+
+// NewBitFieldCommand creates a new BITFIELD command
+func NewBitFieldCommand() *BitFieldCommand {
+	return &BitFieldCommand{}
+}
+
+// NewBitFieldRoCommand creates a new BITFIELD_RO command, which only accepts GET
+func NewBitFieldRoCommand() *BitFieldCommand {
+	return &BitFieldCommand{readOnly: true}
+}
+
+// Name returns the command name
+func (c *BitFieldCommand) Name() string {
+	if c.readOnly {
+		return "BITFIELD_RO"
+	}
+	return "BITFIELD"
+}
+
+// Execute handles the BITFIELD command
+func (c *BitFieldCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	ops, err := parseBitfieldOps(args[1:])
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	// The highest byte touched by a write decides how far the value must grow
+	writes := false
+	highestByte := int64(0)
+	for _, op := range ops {
+		if op.opcode != bitfieldGet {
+			writes = true
+			highestByte = max(highestByte, (op.offset+op.width-1)>>3)
+		}
+	}
+
+	if writes && c.readOnly {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "BITFIELD_RO only supports the GET subcommand")
+	}
+
+	replies := make([]resp.Reply, 0, len(ops))
+	if !writes {
+		err = client.DB.ReadString(args[0], func(value []byte, exists bool) {
+			for _, op := range ops {
+				replies = append(replies, resp.Integer(readBitfield(value, op)))
+			}
+		})
+		if err != nil {
+			return resp.Reply{}, err
+		}
+		return resp.Array(replies), nil
+	}
+
+	err = client.DB.UpdateString(args[0], func(value []byte, exists bool) ([]byte, error) {
+		value = growBytes(value, int(highestByte)+1)
+		for _, op := range ops {
+			replies = append(replies, applyBitfieldOp(value, op))
+		}
+		return value, nil
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Array(replies), nil
+}
+
+// parseBitfieldOps parses the subcommands following the key of a BITFIELD call
+func parseBitfieldOps(args []string) ([]bitfieldOp, error) {
+	ops := make([]bitfieldOp, 0, len(args)/3)
+	overflow := overflowWrap
+
+	for j := 0; j < len(args); j++ {
+		remaining := len(args) - j - 1
+		var opcode bitfieldOpcode
+
+		switch sub := strings.ToUpper(args[j]); {
+		case sub == "GET" && remaining >= 2:
+			opcode = bitfieldGet
+		case sub == "SET" && remaining >= 3:
+			opcode = bitfieldSet
+		case sub == "INCRBY" && remaining >= 3:
+			opcode = bitfieldIncrBy
+		case sub == "OVERFLOW" && remaining >= 1:
+			j++
+			switch strings.ToUpper(args[j]) {
+			case "WRAP":
+				overflow = overflowWrap
+			case "SAT":
+				overflow = overflowSat
+			case "FAIL":
+				overflow = overflowFail
+			default:
+				return nil, errors.New(errors.ErrorTypeCommand, "Invalid OVERFLOW type specified")
+			}
+			continue
+		default:
+			return nil, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+
+		signed, width, err := parseBitfieldType(args[j+1])
+		if err != nil {
+			return nil, err
+		}
+
+		offset, err := parseBitOffset(args[j+2], true, width)
+		if err != nil {
+			return nil, err
+		}
+
+		op := bitfieldOp{opcode: opcode, offset: offset, width: width, signed: signed, overflow: overflow}
+		if opcode != bitfieldGet {
+			if op.value, err = parseInteger(args[j+3]); err != nil {
+				return nil, err
+			}
+			j++
+		}
+		j += 2
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// parseBitfieldType parses a type such as i8 or u16
+func parseBitfieldType(arg string) (bool, int64, error) {
+	invalid := errors.New(errors.ErrorTypeCommand, "Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+	if len(arg) < 2 {
+		return false, 0, invalid
+	}
+
+	var signed bool
+	switch arg[0] {
+	case 'i', 'I':
+		signed = true
+	case 'u', 'U':
+		signed = false
+	default:
+		return false, 0, invalid
+	}
+
+	width, err := strconv.ParseInt(arg[1:], 10, 64)
+	if err != nil || width < 1 || (signed && width > 64) || (!signed && width > 63) {
+		return false, 0, invalid
+	}
+	return signed, width, nil
+}
+
+// readBitfield returns the current value of the field addressed by op
+func readBitfield(data []byte, op bitfieldOp) int64 {
+	if op.signed {
+		return getSignedBitfield(data, op.offset, op.width)
+	}
+	return int64(getUnsignedBitfield(data, op.offset, op.width))
+}
+
+// applyBitfieldOp executes op against data and returns its reply
+func applyBitfieldOp(data []byte, op bitfieldOp) resp.Reply {
+	if op.opcode == bitfieldGet {
+		return resp.Integer(readBitfield(data, op))
+	}
+
+	old := readBitfield(data, op)
+
+	var newValue int64
+	var overflowed bool
+	if op.signed {
+		if op.opcode == bitfieldSet {
+			newValue, overflowed = signedBitfieldOverflow(op.value, 0, op.width, op.overflow)
+		} else {
+			newValue, overflowed = signedBitfieldOverflow(old, op.value, op.width, op.overflow)
+		}
+	} else {
+		var result uint64
+		if op.opcode == bitfieldSet {
+			result, overflowed = unsignedBitfieldOverflow(uint64(op.value), 0, op.width, op.overflow)
+		} else {
+			result, overflowed = unsignedBitfieldOverflow(uint64(old), op.value, op.width, op.overflow)
+		}
+		newValue = int64(result)
+	}
+
+	if overflowed && op.overflow == overflowFail {
+		return resp.Null()
+	}
+
+	setBitfield(data, op.offset, op.width, uint64(newValue))
+
+	if op.opcode == bitfieldSet {
+		return resp.Integer(old)
+	}
+	return resp.Integer(newValue)
+}
+
+// signedBitfieldOverflow computes value+incr for a signed field of the given
+// width, applying the overflow policy. It reports whether an overflow occurred.
+func signedBitfieldOverflow(value, incr, width int64, overflow overflowType) (int64, bool) {
+	maxValue := int64(math.MaxInt64)
+	if width < 64 {
+		maxValue = int64(1)<<(width-1) - 1
+	}
+	minValue := -maxValue - 1
+
+	// maxIncr and minIncr may wrap, but are only consulted once value is in range
+	maxIncr := int64(uint64(maxValue) - uint64(value))
+	minIncr := minValue - value
+
+	var limit int64
+	switch {
+	case value > maxValue || (width != 64 && incr > maxIncr) || (value >= 0 && incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case value < minValue || (width != 64 && incr < minIncr) || (value < 0 && incr < 0 && incr < minIncr):
+		limit = minValue
+	default:
+		return value + incr, false
+	}
+
+	if overflow != overflowWrap {
+		return limit, true
+	}
+
+	// Add as unsigned so wrapping is well defined, then sign-extend from the field width
+	result := uint64(value) + uint64(incr)
+	if width < 64 {
+		mask := ^uint64(0) << width
+		if result&(1<<(width-1)) != 0 {
+			result |= mask
+		} else {
+			result &^= mask
+		}
+	}
+	return int64(result), true
+}
+
+// unsignedBitfieldOverflow computes value+incr for an unsigned field of the
+// given width, applying the overflow policy. It reports whether an overflow occurred.
+func unsignedBitfieldOverflow(value uint64, incr, width int64, overflow overflowType) (uint64, bool) {
+	maxValue := uint64(1)<<width - 1
+	maxIncr := int64(maxValue - value)
+	minIncr := -int64(value)
+
+	var limit uint64
+	switch {
+	case value > maxValue || (incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case incr < 0 && incr < minIncr:
+		limit = 0
+	default:
+		return value + uint64(incr), false
+	}
+
+	if overflow != overflowWrap {
+		return limit, true
+	}
+	return (value + uint64(incr)) &^ (^uint64(0) << width), true
+}
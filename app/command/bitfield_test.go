@@ -0,0 +1,60 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestBitFieldCommand_Name(t *testing.T) {
+	if cmd := NewBitFieldCommand(); cmd.Name() != "BITFIELD" {
+		t.Errorf("Expected command name to be 'BITFIELD', got %s", cmd.Name())
+	}
+	if cmd := NewBitFieldRoCommand(); cmd.Name() != "BITFIELD_RO" {
+		t.Errorf("Expected command name to be 'BITFIELD_RO', got %s", cmd.Name())
+	}
+}
+
+func TestBitFieldCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("bitfield-key")
+	cmd := NewBitFieldCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "get from missing key", args: []string{"bitfield-key", "GET", "u8", "0"}, expected: "*1\r\n:0\r\n"},
+		{name: "set returns old value", args: []string{"bitfield-key", "SET", "i8", "0", "-100", "GET", "u8", "0"}, expected: "*2\r\n:0\r\n:156\r\n"},
+		{name: "incrby returns new value", args: []string{"bitfield-key", "INCRBY", "i8", "0", "10"}, expected: "*1\r\n:-90\r\n"},
+		{name: "hash offset", args: []string{"bitfield-key", "SET", "u8", "#1", "200", "GET", "u8", "8"}, expected: "*2\r\n:0\r\n:200\r\n"},
+		{name: "unsigned wrap", args: []string{"bitfield-key", "INCRBY", "u8", "#1", "100"}, expected: "*1\r\n:44\r\n"},
+		{name: "unsigned saturate", args: []string{"bitfield-key", "OVERFLOW", "SAT", "INCRBY", "u8", "#1", "250"}, expected: "*1\r\n:255\r\n"},
+		{name: "unsigned fail", args: []string{"bitfield-key", "OVERFLOW", "FAIL", "INCRBY", "u8", "#1", "1", "GET", "u8", "#1"}, expected: "*2\r\n$-1\r\n:255\r\n"},
+		{name: "signed wrap", args: []string{"bitfield-key", "SET", "i8", "#2", "127", "INCRBY", "i8", "#2", "1"}, expected: "*2\r\n:0\r\n:-128\r\n"},
+		{name: "signed saturate", args: []string{"bitfield-key", "OVERFLOW", "SAT", "INCRBY", "i8", "#2", "-1"}, expected: "*1\r\n:-128\r\n"},
+		{name: "set with overflow wraps value", args: []string{"bitfield-key", "SET", "u4", "100", "18", "GET", "u4", "100"}, expected: "*2\r\n:0\r\n:2\r\n"},
+		{name: "i64 field", args: []string{"bitfield-key", "SET", "i64", "200", "-1", "GET", "i64", "200"}, expected: "*2\r\n:0\r\n:-1\r\n"},
+		{name: "u63 field", args: []string{"bitfield-key", "SET", "u63", "300", "9223372036854775807", "GET", "u63", "300"}, expected: "*2\r\n:0\r\n:9223372036854775807\r\n"},
+		{name: "no operations", args: []string{"bitfield-key"}, expected: "*0\r\n"},
+		{name: "u64 is rejected", args: []string{"bitfield-key", "GET", "u64", "0"}, errMsg: "Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is."},
+		{name: "invalid type", args: []string{"bitfield-key", "GET", "x8", "0"}, errMsg: "Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is."},
+		{name: "invalid offset", args: []string{"bitfield-key", "GET", "u8", "-1"}, errMsg: "bit offset is not an integer or out of range"},
+		{name: "invalid overflow", args: []string{"bitfield-key", "OVERFLOW", "CLAMP"}, errMsg: "Invalid OVERFLOW type specified"},
+		{name: "missing set value", args: []string{"bitfield-key", "SET", "u8", "0"}, errMsg: "syntax error"},
+		{name: "invalid increment", args: []string{"bitfield-key", "INCRBY", "u8", "0", "x"}, errMsg: "value is not an integer or out of range"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'bitfield' command"},
+	})
+}
+
+func TestBitFieldRoCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("bitfield-ro-key", "\x05", 0)
+	cmd := NewBitFieldRoCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "get", args: []string{"bitfield-ro-key", "GET", "u4", "4", "GET", "i4", "4"}, expected: "*2\r\n:5\r\n:5\r\n"},
+		{name: "set is rejected", args: []string{"bitfield-ro-key", "SET", "u8", "0", "1"}, errMsg: "BITFIELD_RO only supports the GET subcommand"},
+	})
+
+	if value, _ := storeInstance.Get("bitfield-ro-key"); value != "\x05" {
+		t.Errorf("Expected BITFIELD_RO to leave the value untouched, got %q", value)
+	}
+}
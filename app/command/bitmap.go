@@ -0,0 +1,182 @@
+package command
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+)
+
+// maxStringSize is the largest string a bit command may create (512MB, the
+// default proto-max-bulk-len), which bounds the bit offsets it accepts.
+const maxStringSize = 512 * 1024 * 1024
+
+// parseBitOffset parses a bit offset argument. When allowHash is true, an
+// offset of the form #N is interpreted as N*width, as used by BITFIELD.
+func parseBitOffset(arg string, allowHash bool, width int64) (int64, error) {
+	useHash := false
+	if allowHash && strings.HasPrefix(arg, "#") {
+		useHash = true
+		arg = arg[1:]
+	}
+
+	offset, err := strconv.ParseInt(arg, 10, 64)
+	if err == nil && useHash {
+		if offset > (maxStringSize*8)/width {
+			err = strconv.ErrRange
+		}
+		offset *= width
+	}
+	if err != nil || offset < 0 || offset>>3 >= maxStringSize {
+		return 0, errors.New(errors.ErrorTypeCommand, "bit offset is not an integer or out of range")
+	}
+	return offset, nil
+}
+
+// parseInteger parses a 64-bit signed integer argument.
+func parseInteger(arg string) (int64, error) {
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errors.New(errors.ErrorTypeCommand, "value is not an integer or out of range")
+	}
+	return value, nil
+}
+
+// parseBitRangeUnit parses the optional BYTE|BIT argument of BITCOUNT and BITPOS,
+// reporting whether the range is expressed in bits.
+func parseBitRangeUnit(arg string) (bool, error) {
+	switch strings.ToUpper(arg) {
+	case "BIT":
+		return true, nil
+	case "BYTE":
+		return false, nil
+	default:
+		return false, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+}
+
+// growBytes extends b to size bytes, zero-filling the new tail. Capacity grows
+// geometrically, so setting increasing offsets one at a time stays amortised O(1)
+// instead of copying the whole value on every call.
+func growBytes(b []byte, size int) []byte {
+	if size <= len(b) {
+		return b
+	}
+	oldLen := len(b)
+	b = slices.Grow(b, size-oldLen)[:size]
+	clear(b[oldLen:])
+	return b
+}
+
+// normalizeRange converts inclusive start/end indexes, which may be negative to
+// count from the end, into a range clamped to [0, length). The returned bool is
+// false if the resulting range is empty.
+func normalizeRange(start, end, length int64) (int64, int64, bool) {
+	if start < 0 {
+		start += length
+	}
+	if end < 0 {
+		end += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= length {
+		end = length - 1
+	}
+	return start, end, start <= end
+}
+
+// countBits returns the number of set bits between startBit and endBit inclusive.
+func countBits(data []byte, startBit, endBit int64) int64 {
+	startByte, endByte := startBit>>3, endBit>>3
+
+	firstMask := byte(0xff >> (startBit & 7))
+	lastMask := byte(0xff << (7 - endBit&7))
+	if startByte == endByte {
+		return int64(bits.OnesCount8(data[startByte] & firstMask & lastMask))
+	}
+
+	count := int64(bits.OnesCount8(data[startByte]&firstMask) + bits.OnesCount8(data[endByte]&lastMask))
+
+	middle := data[startByte+1 : endByte]
+	for len(middle) >= 8 {
+		count += int64(bits.OnesCount64(binary.LittleEndian.Uint64(middle)))
+		middle = middle[8:]
+	}
+	for _, b := range middle {
+		count += int64(bits.OnesCount8(b))
+	}
+	return count
+}
+
+// findBit returns the position of the first bit equal to bit between startBit
+// and endBit inclusive, or -1 if there is none.
+func findBit(data []byte, bit byte, startBit, endBit int64) int64 {
+	skip := byte(0x00)
+	if bit == 0 {
+		skip = 0xff
+	}
+
+	for pos := startBit; pos <= endBit; {
+		b := data[pos>>3]
+		if pos&7 == 0 && pos+7 <= endBit && b == skip {
+			pos += 8
+			continue
+		}
+		if (b>>(7-pos&7))&1 == bit {
+			return pos
+		}
+		pos++
+	}
+	return -1
+}
+
+// getBit returns the bit at offset, treating bits past the end of data as zero.
+func getBit(data []byte, offset int64) byte {
+	byteIndex := offset >> 3
+	if byteIndex >= int64(len(data)) {
+		return 0
+	}
+	return (data[byteIndex] >> (7 - offset&7)) & 1
+}
+
+// getUnsignedBitfield reads a big-endian unsigned integer of the given width
+// starting at the bit offset.
+func getUnsignedBitfield(data []byte, offset int64, width int64) uint64 {
+	var value uint64
+	for j := int64(0); j < width; j++ {
+		value = value<<1 | uint64(getBit(data, offset+j))
+	}
+	return value
+}
+
+// getSignedBitfield reads a big-endian two's complement integer of the given
+// width starting at the bit offset.
+func getSignedBitfield(data []byte, offset int64, width int64) int64 {
+	value := getUnsignedBitfield(data, offset, width)
+	if width < 64 && value&(1<<(width-1)) != 0 {
+		value |= ^uint64(0) << width
+	}
+	return int64(value)
+}
+
+// setBitfield writes the low width bits of value at the bit offset. data must
+// already be large enough to hold the field.
+func setBitfield(data []byte, offset int64, width int64, value uint64) {
+	for j := int64(0); j < width; j++ {
+		pos := offset + j
+		mask := byte(1 << (7 - pos&7))
+		if value&(1<<(width-1-j)) != 0 {
+			data[pos>>3] |= mask
+		} else {
+			data[pos>>3] &^= mask
+		}
+	}
+}
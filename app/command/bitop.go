@@ -0,0 +1,105 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// BitOpCommand implements the BITOP command
+type BitOpCommand struct {
+}
+
+// NewBitOpCommand creates a new BITOP command
+func NewBitOpCommand() *BitOpCommand {
+	return &BitOpCommand{}
+}
+
+// Name returns the command name
+func (c *BitOpCommand) Name() string {
+	return "BITOP"
+}
+
+// Execute handles the BITOP command
+func (c *BitOpCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	op := strings.ToUpper(args[0])
+	destKey := args[1]
+	sourceKeys := args[2:]
+
+	switch op {
+	case "AND", "OR", "XOR":
+	case "NOT":
+		if len(sourceKeys) != 1 {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "BITOP NOT must be called with a single source key.")
+		}
+	case "DIFF":
+		if len(sourceKeys) < 2 {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "BITOP DIFF must be called with at least two source keys.")
+		}
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	// Snapshot the sources; missing keys behave as empty strings
+	sources := make([][]byte, len(sourceKeys))
+	maxLen := 0
+	for i, key := range sourceKeys {
+		err := client.DB.ReadString(key, func(value []byte, exists bool) {
+			sources[i] = append([]byte(nil), value...)
+		})
+		if err != nil {
+			return resp.Reply{}, err
+		}
+		maxLen = max(maxLen, len(sources[i]))
+	}
+
+	result := bitOp(op, sources, maxLen)
+	if len(result) == 0 {
+		client.DB.Delete(destKey)
+		return resp.Integer(0), nil
+	}
+
+	client.DB.Set(destKey, string(result), 0)
+	return resp.Integer(int64(len(result))), nil
+}
+
+// bitOp combines the sources byte by byte, treating bytes past the end of a
+// shorter source as zero.
+func bitOp(op string, sources [][]byte, length int) []byte {
+	byteAt := func(src []byte, i int) byte {
+		if i < len(src) {
+			return src[i]
+		}
+		return 0
+	}
+
+	result := make([]byte, length)
+	for i := range result {
+		out := byteAt(sources[0], i)
+		switch op {
+		case "NOT":
+			out = ^out
+		case "DIFF":
+			var others byte
+			for _, src := range sources[1:] {
+				others |= byteAt(src, i)
+			}
+			out &^= others
+		default:
+			for _, src := range sources[1:] {
+				b := byteAt(src, i)
+				switch op {
+				case "AND":
+					out &= b
+				case "OR":
+					out |= b
+				case "XOR":
+					out ^= b
+				}
+			}
+		}
+		result[i] = out
+	}
+	return result
+}
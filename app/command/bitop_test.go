@@ -0,0 +1,69 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestBitOpCommand_Name(t *testing.T) {
+	cmd := NewBitOpCommand()
+	if cmd.Name() != "BITOP" {
+		t.Errorf("Expected command name to be 'BITOP', got %s", cmd.Name())
+	}
+}
+
+func TestBitOpCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	cmd := NewBitOpCommand()
+
+	storeInstance.Set("bitop-a", "\xf0\x0f", 0)
+	storeInstance.Set("bitop-b", "\xff", 0)
+	storeInstance.Set("bitop-c", "\x3c\x01\x80", 0)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		value    string
+	}{
+		{name: "and", args: []string{"AND", "bitop-dest", "bitop-a", "bitop-b"}, expected: ":2\r\n", value: "\xf0\x00"},
+		{name: "or", args: []string{"OR", "bitop-dest", "bitop-a", "bitop-c"}, expected: ":3\r\n", value: "\xfc\x0f\x80"},
+		{name: "xor", args: []string{"xor", "bitop-dest", "bitop-a", "bitop-b"}, expected: ":2\r\n", value: "\x0f\x0f"},
+		{name: "not", args: []string{"NOT", "bitop-dest", "bitop-a"}, expected: ":2\r\n", value: "\x0f\xf0"},
+		{name: "diff", args: []string{"DIFF", "bitop-dest", "bitop-a", "bitop-b", "bitop-c"}, expected: ":3\r\n", value: "\x00\x0e\x00"},
+		{name: "missing source is empty", args: []string{"AND", "bitop-dest", "bitop-a", "bitop-missing"}, expected: ":2\r\n", value: "\x00\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := execute(cmd, tt.args)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected result %q, got %q", tt.expected, result)
+			}
+
+			value, err := storeInstance.Get("bitop-dest")
+			if err != nil {
+				t.Fatalf("Expected destination to exist, got error: %v", err)
+			}
+			if value != tt.value {
+				t.Errorf("Expected destination %q, got %q", tt.value, value)
+			}
+		})
+	}
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "empty result deletes destination", args: []string{"OR", "bitop-dest", "bitop-missing"}, expected: ":0\r\n"},
+		{name: "not with several keys", args: []string{"NOT", "bitop-dest", "bitop-a", "bitop-b"}, errMsg: "BITOP NOT must be called with a single source key."},
+		{name: "diff with one key", args: []string{"DIFF", "bitop-dest", "bitop-a"}, errMsg: "BITOP DIFF must be called with at least two source keys."},
+		{name: "unknown operation", args: []string{"NAND", "bitop-dest", "bitop-a"}, errMsg: "syntax error"},
+		{name: "wrong number of arguments", args: []string{"AND", "bitop-dest"}, errMsg: "wrong number of arguments for 'bitop' command"},
+	})
+
+	if _, err := storeInstance.Get("bitop-dest"); err == nil {
+		t.Error("Expected empty BITOP result to delete the destination key")
+	}
+}
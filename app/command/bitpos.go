@@ -0,0 +1,96 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// BitPosCommand implements the BITPOS command
+type BitPosCommand struct {
+}
+
+// NewBitPosCommand creates a new BITPOS command
+func NewBitPosCommand() *BitPosCommand {
+	return &BitPosCommand{}
+}
+
+// Name returns the command name
+func (c *BitPosCommand) Name() string {
+	return "BITPOS"
+}
+
+// Execute handles the BITPOS command
+func (c *BitPosCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	if len(args) > 5 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	bitArg, err := parseInteger(args[1])
+	if err != nil {
+		return resp.Reply{}, err
+	}
+	if bitArg != 0 && bitArg != 1 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "The bit argument must be 1 or 0.")
+	}
+	bit := byte(bitArg)
+
+	var start, end int64
+	endGiven, isBit := false, false
+	if len(args) >= 3 {
+		if start, err = parseInteger(args[2]); err != nil {
+			return resp.Reply{}, err
+		}
+	}
+	if len(args) >= 4 {
+		if end, err = parseInteger(args[3]); err != nil {
+			return resp.Reply{}, err
+		}
+		endGiven = true
+	}
+	if len(args) == 5 {
+		if isBit, err = parseBitRangeUnit(args[4]); err != nil {
+			return resp.Reply{}, err
+		}
+	}
+
+	// A missing key is an infinite run of zero bits
+	pos := int64(-1)
+	if bit == 0 {
+		pos = 0
+	}
+
+	err = client.DB.ReadString(args[0], func(value []byte, exists bool) {
+		if !exists {
+			return
+		}
+
+		length := int64(len(value))
+		if isBit {
+			length *= 8
+		}
+		if !endGiven {
+			end = length - 1
+		}
+
+		startPos, endPos, ok := normalizeRange(start, end, length)
+		if !ok {
+			pos = -1
+			return
+		}
+		if !isBit {
+			startPos, endPos = startPos*8, endPos*8+7
+		}
+
+		pos = findBit(value, bit, startPos, endPos)
+
+		// Without an explicit end the string is considered zero-padded on the right
+		if pos == -1 && bit == 0 && !endGiven {
+			pos = endPos + 1
+		}
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(pos), nil
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestBitPosCommand_Name(t *testing.T) {
+	cmd := NewBitPosCommand()
+	if cmd.Name() != "BITPOS" {
+		t.Errorf("Expected command name to be 'BITPOS', got %s", cmd.Name())
+	}
+}
+
+func TestBitPosCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	cmd := NewBitPosCommand()
+
+	storeInstance.Set("bitpos-ones", "\xff\xf0\x00", 0)
+	storeInstance.Set("bitpos-full", "\xff\xff\xff", 0)
+	storeInstance.Set("bitpos-zeros", "\x00\x00\x00", 0)
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "first clear bit", args: []string{"bitpos-ones", "0"}, expected: ":12\r\n"},
+		{name: "first set bit", args: []string{"bitpos-ones", "1"}, expected: ":0\r\n"},
+		{name: "set bit from byte 2", args: []string{"bitpos-ones", "1", "2"}, expected: ":-1\r\n"},
+		{name: "clear bit from byte 2", args: []string{"bitpos-ones", "0", "2"}, expected: ":16\r\n"},
+		{name: "bit range", args: []string{"bitpos-ones", "1", "7", "15", "BIT"}, expected: ":7\r\n"},
+		{name: "bit range clear", args: []string{"bitpos-ones", "0", "2", "12", "bit"}, expected: ":12\r\n"},
+		{name: "clear bit in full string", args: []string{"bitpos-full", "0"}, expected: ":24\r\n"},
+		{name: "clear bit in full string with end", args: []string{"bitpos-full", "0", "0", "-1"}, expected: ":-1\r\n"},
+		{name: "set bit in zero string", args: []string{"bitpos-zeros", "1"}, expected: ":-1\r\n"},
+		{name: "empty range", args: []string{"bitpos-ones", "1", "2", "1"}, expected: ":-1\r\n"},
+		{name: "missing key looking for clear bit", args: []string{"bitpos-missing", "0"}, expected: ":0\r\n"},
+		{name: "missing key looking for set bit", args: []string{"bitpos-missing", "1"}, expected: ":-1\r\n"},
+		{name: "invalid bit", args: []string{"bitpos-ones", "2"}, errMsg: "The bit argument must be 1 or 0."},
+		{name: "invalid unit", args: []string{"bitpos-ones", "1", "0", "1", "WORD"}, errMsg: "syntax error"},
+		{name: "too many arguments", args: []string{"bitpos-ones", "1", "0", "1", "BIT", "x"}, errMsg: "syntax error"},
+		{name: "wrong number of arguments", args: []string{"bitpos-ones"}, errMsg: "wrong number of arguments for 'bitpos' command"},
+	})
+}
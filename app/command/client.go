@@ -0,0 +1,369 @@
+package command
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// nextClientID is the ID given to the next connection
+var nextClientID atomic.Int64
+
+// ClientFlags is a set of flags describing the state of a connection
+type ClientFlags uint64
+
+const (
+	// ClientCloseAfterReply closes the connection once the pending replies
+	// have been written, as after QUIT
+	ClientCloseAfterReply ClientFlags = 1 << iota
+	// ClientNoEvict excludes the connection from client eviction (CLIENT NO-EVICT)
+	ClientNoEvict
+	// ClientReplyOff suppresses all replies (CLIENT REPLY OFF)
+	ClientReplyOff
+	// ClientReplySkipNext suppresses the reply to the next command (CLIENT REPLY SKIP)
+	ClientReplySkipNext
+	// ClientReplySkip suppresses the reply to the command being executed
+	ClientReplySkip
+	// ClientBlocked marks a connection waiting for its command to be allowed
+	// to run, such as during CLIENT PAUSE
+	ClientBlocked
+	// ClientUnixSocket marks a connection made through a Unix socket
+	ClientUnixSocket
+)
+
+// DefaultUser is the user connections are authenticated as when they connect
+const DefaultUser = acl.DefaultUser
+
+// Client holds the state of the connection a command is executed for.
+//
+// The exported fields belong to the connection's goroutine, which may read
+// them directly but must change them with the setters, as other connections
+// read them through Info (for CLIENT LIST) while holding the client's lock.
+type Client struct {
+	// ID uniquely identifies the connection for the lifetime of the process
+	ID int64
+	// Name is the connection name set with CLIENT SETNAME or HELLO SETNAME
+	Name string
+	// Protocol is the RESP version replies are encoded with, RESP2 by default
+	Protocol int
+	// DB is the database selected by the connection, 0 by default
+	DB *store.Store
+	// User is the user the connection is authenticated as
+	User string
+	// Flags holds the ClientFlags set on the connection
+	Flags ClientFlags
+	// Addr and LocalAddr are the remote and local addresses of the connection
+	Addr      string
+	LocalAddr string
+	// CreatedAt is when the connection was accepted
+	CreatedAt time.Time
+
+	mu sync.Mutex
+	// conn is the connection, if the client has one, closed by Kill
+	conn io.Closer
+	// fd is the connection's file descriptor, or -1
+	fd int
+	// killed is set by Kill; the connection is closed as soon as possible
+	killed atomic.Bool
+	// authenticated is set once the connection has authenticated with AUTH
+	// or HELLO, or from the start if the default user needs no password
+	authenticated bool
+	// lastCommand and lastInteraction describe the most recent command
+	lastCommand     string
+	lastInteraction time.Time
+	// queryBuffered is how much unparsed input was buffered after the most
+	// recent command was read
+	queryBuffered int
+
+	// output holds the replies waiting to be written to the connection
+	output *outputBuffer
+	// writer buffers encoded replies on their way to output
+	writer *bufio.Writer
+	// encoder writes replies to writer
+	encoder *resp.Encoder
+}
+
+// NewClient creates the state of a new connection whose replies are written
+// to w. If w is a net.Conn, the client records its addresses and can be
+// killed. It speaks RESP2, is logged in as the default user, though it must
+// authenticate first unless that user needs no password, and has database 0
+// selected.
+func NewClient(w io.Writer) *Client {
+	output := &outputBuffer{w: w}
+	writer := bufio.NewWriter(output)
+	now := time.Now()
+	defaultUser, _ := acl.GetACL().User(DefaultUser)
+	c := &Client{
+		ID:              nextClientID.Add(1),
+		Protocol:        resp.RESP2,
+		DB:              store.GetStore(),
+		User:            DefaultUser,
+		CreatedAt:       now,
+		fd:              -1,
+		lastCommand:     "NULL",
+		lastInteraction: now,
+		authenticated:   defaultUser.NoPass() && defaultUser.Enabled(),
+		output:          output,
+		writer:          writer,
+		encoder:         resp.NewEncoder(writer),
+	}
+
+	if conn, ok := w.(net.Conn); ok {
+		c.conn = conn
+		c.Addr = conn.RemoteAddr().String()
+		c.LocalAddr = conn.LocalAddr().String()
+		if _, ok := conn.(*net.UnixConn); ok {
+			// As in Redis, both ends are shown as the socket's path
+			c.Addr = conn.LocalAddr().String() + ":0"
+			c.LocalAddr = c.Addr
+			c.Flags |= ClientUnixSocket
+		}
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			conn = tlsConn.NetConn()
+		}
+		if sc, ok := conn.(syscall.Conn); ok {
+			if raw, err := sc.SyscallConn(); err == nil {
+				raw.Control(func(fd uintptr) { c.fd = int(fd) })
+			}
+		}
+	}
+	return c
+}
+
+// SetName sets the connection name.
+func (c *Client) SetName(name string) {
+	c.mu.Lock()
+	c.Name = name
+	c.mu.Unlock()
+}
+
+// SetProtocol sets the RESP version replies are encoded with.
+func (c *Client) SetProtocol(protocol int) {
+	c.mu.Lock()
+	c.Protocol = protocol
+	c.mu.Unlock()
+}
+
+// SetUser sets the user the connection is authenticated as.
+func (c *Client) SetUser(user string) {
+	c.mu.Lock()
+	c.User = user
+	c.mu.Unlock()
+}
+
+// SelectDB selects the database commands run against.
+func (c *Client) SelectDB(db *store.Store) {
+	c.mu.Lock()
+	c.DB = db
+	c.mu.Unlock()
+}
+
+// SetFlags sets flags on the connection.
+func (c *Client) SetFlags(flags ClientFlags) {
+	c.mu.Lock()
+	c.Flags |= flags
+	c.mu.Unlock()
+}
+
+// ClearFlags clears flags on the connection.
+func (c *Client) ClearFlags(flags ClientFlags) {
+	c.mu.Lock()
+	c.Flags &^= flags
+	c.mu.Unlock()
+}
+
+// BeginCommand records that the connection sent the command name, leaving
+// queryBuffered bytes of further input unparsed.
+func (c *Client) BeginCommand(name string, queryBuffered int) {
+	c.mu.Lock()
+	c.lastCommand = strings.ToLower(name)
+	c.lastInteraction = time.Now()
+	c.queryBuffered = queryBuffered
+	c.mu.Unlock()
+}
+
+// Idle returns how long ago the connection last sent a command or was sent
+// replies.
+func (c *Client) Idle() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Since(c.lastInteraction)
+}
+
+// Authenticated reports whether the connection may run commands other than
+// those needed to authenticate.
+func (c *Client) Authenticated() bool {
+	return c.authenticated
+}
+
+// AuthenticateCertificate authenticates the connection as the ACL user named
+// by the common name of its verified TLS client certificate, if that user
+// exists and is enabled, and reports whether it did.
+func (c *Client) AuthenticateCertificate(state tls.ConnectionState) bool {
+	if len(state.VerifiedChains) == 0 {
+		return false
+	}
+	name := state.VerifiedChains[0][0].Subject.CommonName
+	user, ok := acl.GetACL().User(name)
+	if !ok || !user.Enabled() {
+		return false
+	}
+	c.authenticated = true
+	c.SetUser(name)
+	return true
+}
+
+// Blocked reports whether the connection is waiting for its command to be
+// allowed to run.
+func (c *Client) Blocked() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Flags&ClientBlocked != 0
+}
+
+// authUser returns User for use by other connections
+func (c *Client) authUser() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.User
+}
+
+// Kill closes the connection. It may be called from any goroutine; the
+// connection's own goroutine stops once its next read fails.
+func (c *Client) Kill() {
+	c.killed.Store(true)
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Killed reports whether the connection has been killed.
+func (c *Client) Killed() bool {
+	return c.killed.Load()
+}
+
+// WriteReply buffers a reply to the client, encoded with the protocol version
+// the connection currently speaks. Commands reply by returning a resp.Reply;
+// WriteReply is for replies sent outside of that, such as RESP3 pushes.
+//
+// Replies to a killed client are discarded.
+func (c *Client) WriteReply(reply resp.Reply) error {
+	if c.Killed() {
+		return nil
+	}
+	c.encoder.SetProtocol(c.Protocol)
+	if err := c.encoder.Encode(reply); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+// ReplyToCommand buffers the reply to the command just executed, unless
+// replies are switched off with CLIENT REPLY OFF or SKIP. A SKIP applies to
+// the command following it.
+func (c *Client) ReplyToCommand(reply resp.Reply) error {
+	suppress := c.Flags&(ClientReplyOff|ClientReplySkip|ClientReplySkipNext) != 0
+
+	flags := c.Flags &^ ClientReplySkip
+	if flags&ClientReplySkipNext != 0 {
+		flags = flags&^ClientReplySkipNext | ClientReplySkip
+	}
+	if flags != c.Flags {
+		c.mu.Lock()
+		c.Flags = flags
+		c.mu.Unlock()
+	}
+
+	if suppress {
+		return nil
+	}
+	return c.WriteReply(reply)
+}
+
+// Flush writes the buffered replies to the connection. It blocks until the
+// client has read them, or the connection is killed, which discards them.
+func (c *Client) Flush() error {
+	err := c.writer.Flush()
+	if err == nil {
+		err = c.output.flush()
+	}
+	if c.Killed() {
+		c.output.discard()
+		err = nil
+	}
+
+	c.mu.Lock()
+	c.lastInteraction = time.Now()
+	c.mu.Unlock()
+	return err
+}
+
+// Class returns the class of the connection for output buffer limits. The
+// server has neither pub/sub nor replication, so every connection is a normal
+// client, which is why the limits of the other classes can't be changed.
+func (c *Client) Class() ClientClass {
+	return ClassNormal
+}
+
+// OutputBufferLimitExceeded checks the replies waiting to be written against
+// the output buffer limit of the client's class, and describes the limit
+// that was exceeded, or returns "" if none was. It may be called from any
+// goroutine, and must be called whenever replies are added and periodically
+// while they are being written for soft limits to be enforced.
+func (c *Client) OutputBufferLimitExceeded() string {
+	class := c.Class()
+	if reason := c.output.exceeds(outputBufferLimit(class)); reason != "" {
+		return fmt.Sprintf("%s client %s", class, reason)
+	}
+	return ""
+}
+
+// Info describes the connection in the format of CLIENT LIST and CLIENT INFO.
+func (c *Client) Info() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	pending := c.output.pending.Load()
+	return fmt.Sprintf("id=%d addr=%s laddr=%s fd=%d name=%s age=%d idle=%d flags=%s db=%d sub=0 psub=0 ssub=0 multi=-1 watch=0 qbuf=%d qbuf-free=%d obl=%d oll=0 omem=%d cmd=%s user=%s redir=-1 resp=%d",
+		c.ID, c.Addr, c.LocalAddr, c.fd, c.Name,
+		int64(now.Sub(c.CreatedAt).Seconds()), int64(now.Sub(c.lastInteraction).Seconds()),
+		c.flagString(), c.DB.ID(), c.queryBuffered, resp.ReadBufferSize-c.queryBuffered,
+		pending, pending, c.lastCommand, c.User, c.Protocol)
+}
+
+// flagString returns the flags of the connection as letters, as shown by
+// CLIENT LIST
+func (c *Client) flagString() string {
+	var flags []byte
+	if c.Flags&ClientBlocked != 0 {
+		flags = append(flags, 'b')
+	}
+	if c.Flags&ClientCloseAfterReply != 0 {
+		flags = append(flags, 'c')
+	}
+	if c.killed.Load() {
+		flags = append(flags, 'A')
+	}
+	if c.Flags&ClientUnixSocket != 0 {
+		flags = append(flags, 'U')
+	}
+	if c.Flags&ClientNoEvict != 0 {
+		flags = append(flags, 'e')
+	}
+	if len(flags) == 0 {
+		return "N"
+	}
+	return string(flags)
+}
@@ -0,0 +1,314 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// clientHelp is the reply to CLIENT HELP
+var clientHelp = []string{
+	"CLIENT <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"GETNAME",
+	"    Return the name of the current connection.",
+	"ID",
+	"    Return the ID of the current connection.",
+	"INFO",
+	"    Return information about the current client connection.",
+	"KILL <ip:port>",
+	"    Kill connection made from <ip:port>.",
+	"KILL <option> <value> [<option> <value> [...]]",
+	"    Kill connections. Options are:",
+	"    * ADDR (<ip:port>|<unixsocket>:0)",
+	"      Kill connections made from the specified address",
+	"    * LADDR (<ip:port>|<unixsocket>:0)",
+	"      Kill connections made to specified local address",
+	"    * TYPE (NORMAL|MASTER|REPLICA|PUBSUB)",
+	"      Kill connections by type.",
+	"    * USER <username>",
+	"      Kill connections authenticated by <username>.",
+	"    * SKIPME (YES|NO)",
+	"      Skip killing current connection (default: yes).",
+	"    * ID <client-id>",
+	"      Kill connections by client id.",
+	"    * MAXAGE <maxage>",
+	"      Kill connections older than the specified age.",
+	"LIST [options ...]",
+	"    Return information about client connections. Options:",
+	"    * TYPE (NORMAL|MASTER|REPLICA|PUBSUB)",
+	"      Return clients of specified type.",
+	"UNPAUSE",
+	"    Stop the current client pause, resuming traffic.",
+	"PAUSE <timeout> [WRITE|ALL]",
+	"    Suspend all, or just write, clients for <timeout> milliseconds.",
+	"REPLY (ON|OFF|SKIP)",
+	"    Control the replies sent to the current connection.",
+	"SETNAME <name>",
+	"    Assign the name <name> to the current connection.",
+	"NO-EVICT (ON|OFF)",
+	"    Protect current client connection from eviction.",
+	"HELP",
+	"    Print this help.",
+}
+
+// ClientCommand implements the CLIENT command
+type ClientCommand struct {
+	clients *Clients
+}
+
+// NewClientCommand creates a new CLIENT command managing the given clients
+func NewClientCommand(clients *Clients) *ClientCommand {
+	return &ClientCommand{clients: clients}
+}
+
+// Name returns the command name
+func (c *ClientCommand) Name() string {
+	return "CLIENT"
+}
+
+// Execute handles the CLIENT command
+func (c *ClientCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	name := args[0]
+	subcommand := strings.ToUpper(name)
+	args = args[1:]
+	wrongArity := errors.New(errors.ErrorTypeCommand, fmt.Sprintf("wrong number of arguments for 'client|%s' command", strings.ToLower(name)))
+
+	switch subcommand {
+	case "ID":
+		return resp.Integer(client.ID), nil
+	case "INFO":
+		return resp.Verbatim("txt", client.Info()+"\n"), nil
+	case "LIST":
+		return c.list(args)
+	case "SETNAME":
+		if err := validateClientName(args[0]); err != nil {
+			return resp.Reply{}, err
+		}
+		client.SetName(args[0])
+		return resp.SimpleString("OK"), nil
+	case "GETNAME":
+		if client.Name == "" {
+			return resp.Null(), nil
+		}
+		return resp.BulkString(client.Name), nil
+	case "KILL":
+		return c.kill(client, args)
+	case "PAUSE":
+		if len(args) > 2 {
+			return resp.Reply{}, wrongArity
+		}
+		return c.pause(args)
+	case "UNPAUSE":
+		c.clients.Unpause()
+		return resp.SimpleString("OK"), nil
+	case "NO-EVICT":
+		switch strings.ToUpper(args[0]) {
+		case "ON":
+			client.SetFlags(ClientNoEvict)
+		case "OFF":
+			client.ClearFlags(ClientNoEvict)
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+		return resp.SimpleString("OK"), nil
+	case "REPLY":
+		// OFF and SKIP suppress their own +OK as well
+		switch strings.ToUpper(args[0]) {
+		case "ON":
+			client.ClearFlags(ClientReplyOff | ClientReplySkip)
+		case "OFF":
+			client.SetFlags(ClientReplyOff)
+		case "SKIP":
+			if client.Flags&ClientReplyOff == 0 {
+				client.SetFlags(ClientReplySkipNext)
+			}
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+		return resp.SimpleString("OK"), nil
+	case "HELP":
+		lines := make([]resp.Reply, len(clientHelp))
+		for i, line := range clientHelp {
+			lines[i] = resp.SimpleString(line)
+		}
+		return resp.Array(lines), nil
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("unknown subcommand '%s'. Try CLIENT HELP.", name))
+	}
+}
+
+// list handles CLIENT LIST [TYPE type | ID id [id ...]]
+func (c *ClientCommand) list(args []string) (resp.Reply, error) {
+	var ids map[int64]bool
+	normal := true
+
+	switch {
+	case len(args) == 0:
+	case len(args) == 2 && strings.EqualFold(args[0], "TYPE"):
+		var err error
+		if normal, err = parseClientType(args[1]); err != nil {
+			return resp.Reply{}, err
+		}
+	case len(args) > 1 && strings.EqualFold(args[0], "ID"):
+		ids = make(map[int64]bool)
+		for _, arg := range args[1:] {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || id < 1 {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "Invalid client ID")
+			}
+			ids[id] = true
+		}
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	var b strings.Builder
+	for _, other := range c.clients.All() {
+		if !normal || (ids != nil && !ids[other.ID]) {
+			continue
+		}
+		b.WriteString(other.Info())
+		b.WriteByte('\n')
+	}
+	return resp.Verbatim("txt", b.String()), nil
+}
+
+// kill handles both forms of CLIENT KILL: the legacy one taking an address,
+// which fails if there is no such client, and the one taking filters, which
+// replies with the number of clients killed
+func (c *ClientCommand) kill(client *Client, args []string) (resp.Reply, error) {
+	if len(args) == 1 {
+		for _, other := range c.clients.All() {
+			if other.Addr == args[0] {
+				killClient(client, other)
+				return resp.SimpleString("OK"), nil
+			}
+		}
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "No such client")
+	}
+	if len(args)%2 != 0 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	var id, maxAge int64
+	var addr, laddr, user string
+	normal, skipMe := true, true
+	for i := 0; i < len(args); i += 2 {
+		value := args[i+1]
+		switch strings.ToUpper(args[i]) {
+		case "ID":
+			var err error
+			id, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || id < 1 {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "client-id should be greater than 0")
+			}
+		case "TYPE":
+			var err error
+			if normal, err = parseClientType(value); err != nil {
+				return resp.Reply{}, err
+			}
+		case "ADDR":
+			addr = value
+		case "LADDR":
+			laddr = value
+		case "USER":
+			if !userExists(value) {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("No such user '%s'", value))
+			}
+			user = value
+		case "SKIPME":
+			switch strings.ToLower(value) {
+			case "yes":
+				skipMe = true
+			case "no":
+				skipMe = false
+			default:
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+			}
+		case "MAXAGE":
+			var err error
+			if maxAge, err = parseInteger(value); err != nil {
+				return resp.Reply{}, err
+			}
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	}
+
+	now := time.Now()
+	killed := int64(0)
+	for _, other := range c.clients.All() {
+		switch {
+		case !normal,
+			id != 0 && other.ID != id,
+			addr != "" && other.Addr != addr,
+			laddr != "" && other.LocalAddr != laddr,
+			user != "" && other.authUser() != user,
+			skipMe && other == client,
+			maxAge != 0 && int64(now.Sub(other.CreatedAt).Seconds()) <= maxAge:
+			continue
+		}
+		killClient(client, other)
+		killed++
+	}
+	return resp.Integer(killed), nil
+}
+
+// pause handles CLIENT PAUSE timeout [WRITE|ALL]
+func (c *ClientCommand) pause(args []string) (resp.Reply, error) {
+	timeout, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "timeout is not an integer or out of range")
+	}
+	if timeout < 0 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "timeout is negative")
+	}
+
+	mode := PauseAll
+	if len(args) == 2 {
+		switch strings.ToUpper(args[1]) {
+		case "WRITE":
+			mode = PauseWrite
+		case "ALL":
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "CLIENT PAUSE mode must be WRITE or ALL")
+		}
+	}
+
+	c.clients.Pause(mode, time.Now().Add(time.Duration(timeout)*time.Millisecond))
+	return resp.SimpleString("OK"), nil
+}
+
+// killClient kills other on behalf of client. A client killing itself is
+// closed once the reply to CLIENT KILL has been sent.
+func killClient(client, other *Client) {
+	if other == client {
+		client.SetFlags(ClientCloseAfterReply)
+		return
+	}
+	other.Kill()
+}
+
+// parseClientType parses a client type given to CLIENT LIST or KILL and
+// reports whether it is "normal", the type of every connection to this server
+func parseClientType(name string) (bool, error) {
+	switch strings.ToLower(name) {
+	case "normal":
+		return true, nil
+	case "master", "slave", "replica", "pubsub":
+		return false, nil
+	default:
+		return false, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("Unknown client type '%s'", name))
+	}
+}
+
+// userExists reports whether a user with the given name exists
+func userExists(name string) bool {
+	_, ok := acl.GetACL().User(name)
+	return ok
+}
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientCommand_Name(t *testing.T) {
+	cmd := NewClientCommand(NewClients())
+	if cmd.Name() != "CLIENT" {
+		t.Errorf("Expected command name to be 'CLIENT', got %s", cmd.Name())
+	}
+}
+
+func TestClientCommand_Execute(t *testing.T) {
+	cmd := NewClientCommand(NewClients())
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "no subcommand", args: []string{}, errMsg: "wrong number of arguments for 'client' command"},
+		{name: "unknown subcommand", args: []string{"foo"}, errMsg: "unknown subcommand 'foo'. Try CLIENT HELP."},
+		{name: "id with arguments", args: []string{"ID", "x"}, errMsg: "wrong number of arguments for 'client|id' command"},
+		{name: "setname without name", args: []string{"setname"}, errMsg: "wrong number of arguments for 'client|setname' command"},
+		{name: "setname with space", args: []string{"SETNAME", "a b"}, errMsg: "Client names cannot contain spaces, newlines or special characters."},
+		{name: "setname", args: []string{"SETNAME", "worker-1"}, expected: "+OK\r\n"},
+		{name: "getname without name", args: []string{"GETNAME"}, expected: "$-1\r\n"},
+		{name: "list unknown type", args: []string{"LIST", "TYPE", "robot"}, errMsg: "Unknown client type 'robot'"},
+		{name: "list invalid id", args: []string{"LIST", "ID", "1", "x"}, errMsg: "Invalid client ID"},
+		{name: "list bad option", args: []string{"LIST", "FOO", "bar"}, errMsg: "syntax error"},
+		{name: "list replicas", args: []string{"LIST", "TYPE", "replica"}, expected: "$0\r\n\r\n"},
+		{name: "kill unknown address", args: []string{"KILL", "127.0.0.1:1"}, errMsg: "No such client"},
+		{name: "kill odd filters", args: []string{"KILL", "ID", "1", "SKIPME"}, errMsg: "syntax error"},
+		{name: "kill bad id", args: []string{"KILL", "ID", "0"}, errMsg: "client-id should be greater than 0"},
+		{name: "kill unknown user", args: []string{"KILL", "USER", "alice"}, errMsg: "No such user 'alice'"},
+		{name: "kill bad skipme", args: []string{"KILL", "SKIPME", "maybe"}, errMsg: "syntax error"},
+		{name: "kill bad maxage", args: []string{"KILL", "MAXAGE", "old"}, errMsg: "value is not an integer or out of range"},
+		{name: "kill unknown filter", args: []string{"KILL", "COLOR", "red"}, errMsg: "syntax error"},
+		{name: "kill no match", args: []string{"KILL", "ID", "999999"}, expected: ":0\r\n"},
+		{name: "pause bad timeout", args: []string{"PAUSE", "soon"}, errMsg: "timeout is not an integer or out of range"},
+		{name: "pause negative timeout", args: []string{"PAUSE", "-1"}, errMsg: "timeout is negative"},
+		{name: "pause bad mode", args: []string{"PAUSE", "10", "READ"}, errMsg: "CLIENT PAUSE mode must be WRITE or ALL"},
+		{name: "pause", args: []string{"PAUSE", "0", "WRITE"}, expected: "+OK\r\n"},
+		{name: "unpause", args: []string{"UNPAUSE"}, expected: "+OK\r\n"},
+		{name: "no-evict bad value", args: []string{"NO-EVICT", "maybe"}, errMsg: "syntax error"},
+		{name: "no-evict", args: []string{"NO-EVICT", "on"}, expected: "+OK\r\n"},
+		{name: "reply bad mode", args: []string{"REPLY", "LATER"}, errMsg: "syntax error"},
+		{name: "reply on", args: []string{"REPLY", "ON"}, expected: "+OK\r\n"},
+	})
+}
+
+func TestClientCommand_Connection(t *testing.T) {
+	clients := NewClients()
+	cmd := NewClientCommand(clients)
+	client := NewClient(io.Discard)
+	clients.Add(client)
+
+	reply, err := cmd.Execute(client, []string{"SETNAME", "worker-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reply, _ = cmd.Execute(client, []string{"GETNAME"})
+	if result := encodeRESP2(reply); result != "$8\r\nworker-1\r\n" {
+		t.Errorf("Expected the name to be set, got %q", result)
+	}
+
+	reply, _ = cmd.Execute(client, []string{"ID"})
+	if result, expected := encodeRESP2(reply), ":"+fmt.Sprint(client.ID)+"\r\n"; result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	client.BeginCommand("CLIENT", 0)
+	reply, _ = cmd.Execute(client, []string{"INFO"})
+	info := reply.Str
+	for _, field := range []string{"id=" + fmt.Sprint(client.ID) + " ", " name=worker-1 ", " flags=N ", " db=0 ", " cmd=client ", " user=default ", " resp=2"} {
+		if !strings.Contains(info, field) {
+			t.Errorf("Expected CLIENT INFO to contain %q, got %q", field, info)
+		}
+	}
+	if reply.Format != "txt" || !strings.HasSuffix(info, "\n") {
+		t.Errorf("Expected a verbatim text reply ending with a newline, got %q", info)
+	}
+
+	other := NewClient(io.Discard)
+	clients.Add(other)
+	reply, _ = cmd.Execute(client, []string{"LIST"})
+	if lines := strings.Split(strings.TrimSuffix(reply.Str, "\n"), "\n"); len(lines) != 2 {
+		t.Errorf("Expected two clients, got %q", reply.Str)
+	}
+	reply, _ = cmd.Execute(client, []string{"LIST", "ID", fmt.Sprint(other.ID)})
+	if !strings.HasPrefix(reply.Str, "id="+fmt.Sprint(other.ID)+" ") || strings.Count(reply.Str, "\n") != 1 {
+		t.Errorf("Expected only the other client, got %q", reply.Str)
+	}
+}
+
+func TestClientCommand_Kill(t *testing.T) {
+	clients := NewClients()
+	cmd := NewClientCommand(clients)
+	client, other, third := NewClient(io.Discard), NewClient(io.Discard), NewClient(io.Discard)
+	for _, c := range []*Client{client, other, third} {
+		clients.Add(c)
+	}
+
+	reply, err := cmd.Execute(client, []string{"KILL", "ID", fmt.Sprint(other.ID)})
+	if err != nil || encodeRESP2(reply) != ":1\r\n" || !other.Killed() {
+		t.Fatalf("Expected the client to be killed, got %q (err: %v)", encodeRESP2(reply), err)
+	}
+
+	// MAXAGE only matches connections older than the given age
+	reply, _ = cmd.Execute(client, []string{"KILL", "MAXAGE", "3600"})
+	if encodeRESP2(reply) != ":0\r\n" || third.Killed() {
+		t.Errorf("Expected no client older than an hour, got %q", encodeRESP2(reply))
+	}
+
+	// The caller is skipped by default, and is closed after the reply otherwise
+	reply, _ = cmd.Execute(client, []string{"KILL", "USER", "default", "SKIPME", "no"})
+	if encodeRESP2(reply) != ":3\r\n" || !third.Killed() {
+		t.Errorf("Expected every client to be killed, got %q", encodeRESP2(reply))
+	}
+	if client.Killed() || client.Flags&ClientCloseAfterReply == 0 {
+		t.Errorf("Expected the caller to be closed after its reply")
+	}
+}
+
+func TestClientCommand_Pause(t *testing.T) {
+	clients := NewClients()
+	cmd := NewClientCommand(clients)
+	client := NewClient(io.Discard)
+
+	if _, err := cmd.Execute(client, []string{"PAUSE", "100", "WRITE"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	clients.WaitIfPaused(client, commandTable["GET"])
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("Expected reads not to be paused")
+	}
+	clients.WaitIfPaused(client, commandTable["SET"])
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected writes to be paused for 100ms, waited %v", elapsed)
+	}
+}
+
+func TestClientCommand_Reply(t *testing.T) {
+	var out strings.Builder
+	cmd := NewClientCommand(NewClients())
+	client := NewClient(&out)
+
+	run := func(args ...string) {
+		t.Helper()
+		reply, err := cmd.Execute(client, args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		client.ReplyToCommand(reply)
+	}
+
+	run("REPLY", "SKIP")
+	run("ID")
+	run("GETNAME")
+	run("REPLY", "OFF")
+	run("ID")
+	run("REPLY", "ON")
+	client.Flush()
+
+	if out.String() != "$-1\r\n+OK\r\n" {
+		t.Errorf("Expected only the replies to GETNAME and REPLY ON, got %q", out.String())
+	}
+}
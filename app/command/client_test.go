@@ -0,0 +1,147 @@
+package command
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+func TestNewClient(t *testing.T) {
+	first, second := NewClient(io.Discard), NewClient(io.Discard)
+	if second.ID <= first.ID {
+		t.Errorf("Expected increasing client IDs, got %d and %d", first.ID, second.ID)
+	}
+	if first.Protocol != resp.RESP2 || first.User != DefaultUser || first.DB.ID() != 0 || first.Flags != 0 {
+		t.Errorf("Unexpected initial client state: %+v", first)
+	}
+}
+
+func TestClient_WriteReply(t *testing.T) {
+	var out strings.Builder
+	client := NewClient(&out)
+
+	if err := client.WriteReply(resp.Null()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.Protocol = resp.RESP3
+	if err := client.WriteReply(resp.Null()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected replies to be buffered until flushed, got %q", out.String())
+	}
+
+	if err := client.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != "$-1\r\n_\r\n" {
+		t.Errorf("Expected replies in each client's protocol, got %q", out.String())
+	}
+}
+
+func TestClient_Info(t *testing.T) {
+	client := NewClient(io.Discard)
+	if info := client.Info(); !strings.Contains(info, " fd=-1 name= ") || !strings.Contains(info, " flags=N ") || !strings.Contains(info, " cmd=NULL ") {
+		t.Errorf("Unexpected info for a new client: %q", info)
+	}
+
+	client.WriteReply(resp.SimpleString("OK"))
+	if info := client.Info(); !strings.Contains(info, " obl=5 ") || !strings.Contains(info, " omem=5 ") {
+		t.Errorf("Expected 5 bytes in the output buffer, got %q", info)
+	}
+
+	client.SetFlags(ClientCloseAfterReply | ClientNoEvict)
+	client.Kill()
+	if info := client.Info(); !strings.Contains(info, " flags=cAe ") {
+		t.Errorf("Expected flags 'cAe', got %q", info)
+	}
+}
+
+func TestClient_Idle(t *testing.T) {
+	client := NewClient(io.Discard)
+	time.Sleep(20 * time.Millisecond)
+	if client.Idle() < 20*time.Millisecond {
+		t.Errorf("Expected the client to be idle since it was created, got %v", client.Idle())
+	}
+
+	client.BeginCommand("PING", 0)
+	if client.Idle() >= 20*time.Millisecond {
+		t.Errorf("Expected a command to reset the idle time, got %v", client.Idle())
+	}
+}
+
+func TestClient_AuthenticateCertificate(t *testing.T) {
+	a := acl.GetACL()
+	if err := a.SetUser("certuser", []string{"on", "nopass"}, nil); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := a.SetUser("certoff", []string{"off"}, nil); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	t.Cleanup(func() { a.DeleteUsers([]string{"certuser", "certoff"}) })
+
+	verified := func(cn string) tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		return tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+	unverified := verified("certuser")
+	unverified.VerifiedChains = nil
+
+	tests := []struct {
+		name  string
+		state tls.ConnectionState
+		user  string
+	}{
+		{"existing user", verified("certuser"), "certuser"},
+		{"unknown user", verified("nobody"), DefaultUser},
+		{"disabled user", verified("certoff"), DefaultUser},
+		{"unverified certificate", unverified, DefaultUser},
+		{"no certificate", tls.ConnectionState{}, DefaultUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(io.Discard)
+			ok := client.AuthenticateCertificate(tt.state)
+			if ok != (tt.user != DefaultUser) || client.User != tt.user {
+				t.Errorf("Expected user %s, got %s (authenticated: %v)", tt.user, client.User, ok)
+			}
+		})
+	}
+}
+
+func TestClient_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	dialed, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer dialed.Close()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("Failed to accept: %v", err)
+	}
+	defer conn.Close()
+
+	client := NewClient(conn)
+	info := client.Info()
+	for _, field := range []string{"addr=" + path + ":0 ", "laddr=" + path + ":0 ", "flags=U "} {
+		if !strings.Contains(info, field) {
+			t.Errorf("Expected %q in %q", field, info)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PauseMode selects which commands CLIENT PAUSE holds back
+type PauseMode int
+
+const (
+	// PauseNone lets all commands run
+	PauseNone PauseMode = iota
+	// PauseWrite holds back commands that may modify the dataset
+	PauseWrite
+	// PauseAll holds back every command
+	PauseAll
+)
+
+// Clients tracks the connected clients and whether commands are paused
+type Clients struct {
+	mu      sync.RWMutex
+	clients map[int64]*Client
+
+	pauseMu    sync.Mutex
+	pauseMode  PauseMode
+	pauseUntil time.Time
+	// unpaused is closed, and replaced, whenever the pause is lifted or changed
+	unpaused chan struct{}
+}
+
+// NewClients creates an empty set of clients
+func NewClients() *Clients {
+	return &Clients{
+		clients:  make(map[int64]*Client),
+		unpaused: make(chan struct{}),
+	}
+}
+
+// Add registers a connected client
+func (cs *Clients) Add(client *Client) {
+	cs.mu.Lock()
+	cs.clients[client.ID] = client
+	cs.mu.Unlock()
+}
+
+// Remove unregisters a client once its connection is closed
+func (cs *Clients) Remove(client *Client) {
+	cs.mu.Lock()
+	delete(cs.clients, client.ID)
+	cs.mu.Unlock()
+}
+
+// Get returns the client with the given ID
+func (cs *Clients) Get(id int64) (*Client, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	client, ok := cs.clients[id]
+	return client, ok
+}
+
+// Count returns the number of connected clients
+func (cs *Clients) Count() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return len(cs.clients)
+}
+
+// All returns the connected clients in ID order
+func (cs *Clients) All() []*Client {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return slices.SortedFunc(maps.Values(cs.clients), func(a, b *Client) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// Pause holds back commands matching mode until the given time. Pausing
+// again replaces the mode and extends, but never shortens, the pause.
+func (cs *Clients) Pause(mode PauseMode, until time.Time) {
+	cs.pauseMu.Lock()
+	defer cs.pauseMu.Unlock()
+
+	cs.pauseMode = mode
+	if until.After(cs.pauseUntil) {
+		cs.pauseUntil = until
+	}
+	// Wake up paused clients so they re-check the new mode
+	close(cs.unpaused)
+	cs.unpaused = make(chan struct{})
+}
+
+// Unpause lets all held back commands run
+func (cs *Clients) Unpause() {
+	cs.pauseMu.Lock()
+	defer cs.pauseMu.Unlock()
+
+	cs.pauseMode = PauseNone
+	cs.pauseUntil = time.Time{}
+	close(cs.unpaused)
+	cs.unpaused = make(chan struct{})
+}
+
+// WaitIfPaused blocks while the command sent by client, described by meta,
+// is held back by CLIENT PAUSE. CLIENT PAUSE WRITE holds back the commands
+// that write or may replicate a write; meta is nil for unknown commands,
+// which aren't held back by it. The client is flagged as blocked while it
+// waits.
+func (cs *Clients) WaitIfPaused(client *Client, meta *Metadata) {
+	for {
+		cs.pauseMu.Lock()
+		mode, until, unpaused := cs.pauseMode, cs.pauseUntil, cs.unpaused
+		cs.pauseMu.Unlock()
+
+		paused := mode == PauseAll || (mode == PauseWrite && meta != nil && meta.Flags&(FlagWrite|FlagMayReplicate) != 0)
+		wait := time.Until(until)
+		if !paused || wait <= 0 {
+			if client.Flags&ClientBlocked != 0 {
+				client.ClearFlags(ClientBlocked)
+			}
+			return
+		}
+		if client.Flags&ClientBlocked == 0 {
+			client.SetFlags(ClientBlocked)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-unpaused:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
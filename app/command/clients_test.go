@@ -0,0 +1,63 @@
+package command
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClients(t *testing.T) {
+	clients := NewClients()
+	first, second := NewClient(io.Discard), NewClient(io.Discard)
+	clients.Add(second)
+	clients.Add(first)
+
+	if all := clients.All(); len(all) != 2 || all[0] != first || all[1] != second {
+		t.Errorf("Expected the clients in ID order, got %v", all)
+	}
+	if clients.Count() != 2 {
+		t.Errorf("Expected 2 clients, got %d", clients.Count())
+	}
+	if got, ok := clients.Get(second.ID); !ok || got != second {
+		t.Errorf("Expected to find the client by ID")
+	}
+
+	clients.Remove(second)
+	if _, ok := clients.Get(second.ID); ok || len(clients.All()) != 1 {
+		t.Errorf("Expected the client to be removed")
+	}
+}
+
+func TestClients_Pause(t *testing.T) {
+	clients := NewClients()
+	client := NewClient(io.Discard)
+
+	// A later pause extends an earlier one, while a shorter one doesn't cut it short
+	clients.Pause(PauseAll, time.Now().Add(50*time.Millisecond))
+	clients.Pause(PauseAll, time.Now().Add(10*time.Millisecond))
+
+	start := time.Now()
+	clients.WaitIfPaused(client, commandTable["PING"])
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected PING to wait for the longer pause, waited %v", elapsed)
+	}
+
+	// Unpausing releases waiting commands straight away
+	clients.Pause(PauseAll, time.Now().Add(time.Hour))
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		if info := client.Info(); !strings.Contains(info, " flags=b ") {
+			t.Errorf("Expected the waiting client to be flagged as blocked, got %q", info)
+		}
+		clients.Unpause()
+	}()
+	start = time.Now()
+	clients.WaitIfPaused(client, commandTable["GET"])
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected UNPAUSE to release the command, waited %v", elapsed)
+	}
+	if client.Flags&ClientBlocked != 0 {
+		t.Errorf("Expected the client not to be blocked any more")
+	}
+}
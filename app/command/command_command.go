@@ -0,0 +1,316 @@
+package command
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/glob"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// commandHelp is the reply to COMMAND HELP
+var commandHelp = []string{
+	"COMMAND <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"(no subcommand)",
+	"    Return details about all Redis commands.",
+	"COUNT",
+	"    Return the total number of commands in this Redis server.",
+	"LIST",
+	"    Return a list of all commands in this Redis server.",
+	"INFO [<command-name> ...]",
+	"    Return details about multiple Redis commands.",
+	"    If no command names are given, documentation details for all",
+	"    commands are returned.",
+	"DOCS [<command-name> ...]",
+	"    Return documentation details about multiple Redis commands.",
+	"    If no command names are given, documentation details for all",
+	"    commands are returned.",
+	"GETKEYS <full-command>",
+	"    Return the keys from a full Redis command.",
+	"GETKEYSANDFLAGS <full-command>",
+	"    Return the keys and the access flags from a full Redis command.",
+	"HELP",
+	"    Print this help.",
+}
+
+// CommandCommand implements the COMMAND command, which describes the
+// commands of the registry
+type CommandCommand struct {
+	registry *Registry
+}
+
+// NewCommandCommand creates a new COMMAND command describing the commands of
+// registry
+func NewCommandCommand(registry *Registry) *CommandCommand {
+	return &CommandCommand{registry: registry}
+}
+
+// Name returns the command name
+func (c *CommandCommand) Name() string {
+	return "COMMAND"
+}
+
+// Execute handles the COMMAND command
+func (c *CommandCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	if len(args) == 0 {
+		return c.info(nil)
+	}
+
+	name := args[0]
+	args = args[1:]
+
+	switch strings.ToUpper(name) {
+	case "COUNT":
+		return resp.Integer(int64(len(c.registry.Names()))), nil
+	case "INFO":
+		return c.info(args)
+	case "DOCS":
+		return c.docs(args)
+	case "LIST":
+		return c.list(args)
+	case "GETKEYS", "GETKEYSANDFLAGS":
+		return c.getKeys(args, strings.EqualFold(name, "GETKEYSANDFLAGS"))
+	case "HELP":
+		lines := make([]resp.Reply, len(commandHelp))
+		for i, line := range commandHelp {
+			lines[i] = resp.SimpleString(line)
+		}
+		return resp.Array(lines), nil
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("unknown subcommand '%s'. Try COMMAND HELP.", name))
+	}
+}
+
+// find returns the metadata of the command with the given name, or of a
+// subcommand if name is of the form "command|subcommand", along with its
+// name in lowercase
+func (c *CommandCommand) find(name string) (string, *Metadata, bool) {
+	command, subcommand, isSub := strings.Cut(name, "|")
+	meta, ok := c.registry.Metadata(strings.ToUpper(command))
+	if !ok {
+		return "", nil, false
+	}
+	if isSub {
+		meta, ok = meta.Subcommands[strings.ToLower(subcommand)]
+	}
+	return strings.ToLower(name), meta, ok
+}
+
+// info handles COMMAND and COMMAND INFO [name ...], describing the named
+// commands, or every command if there are no names. Unknown commands are
+// described as null.
+func (c *CommandCommand) info(names []string) (resp.Reply, error) {
+	if len(names) == 0 {
+		names = c.registry.Names()
+	}
+	replies := make([]resp.Reply, len(names))
+	for i, name := range names {
+		fullName, meta, ok := c.find(name)
+		if !ok {
+			replies[i] = resp.NullArray()
+			continue
+		}
+		replies[i] = commandInfo(fullName, meta)
+	}
+	return resp.Array(replies), nil
+}
+
+// commandInfo describes a command in the format of COMMAND INFO
+func commandInfo(name string, meta *Metadata) resp.Reply {
+	flags := meta.Flags.Names()
+	first, last, step, movable := meta.keyRange()
+	if movable {
+		flags = append(flags, "movablekeys")
+	}
+
+	categories := meta.AllCategories()
+	for i, category := range categories {
+		categories[i] = "@" + category
+	}
+
+	keySpecs := make([]resp.Reply, len(meta.KeySpecs))
+	for i, spec := range meta.KeySpecs {
+		keySpecs[i] = keySpecInfo(spec)
+	}
+
+	var subcommands []resp.Reply
+	for _, sub := range slices.Sorted(maps.Keys(meta.Subcommands)) {
+		subcommands = append(subcommands, commandInfo(name+"|"+sub, meta.Subcommands[sub]))
+	}
+
+	return resp.Array([]resp.Reply{
+		resp.BulkString(name),
+		resp.Integer(int64(meta.Arity)),
+		simpleStringSet(flags),
+		resp.Integer(int64(first)),
+		resp.Integer(int64(last)),
+		resp.Integer(int64(step)),
+		simpleStringSet(categories),
+		simpleStringSet(meta.Tips),
+		resp.Array(keySpecs),
+		resp.Array(subcommands),
+	})
+}
+
+// keySpecInfo describes a key spec in the format of COMMAND INFO
+func keySpecInfo(spec KeySpec) resp.Reply {
+	beginSearch := resp.Map([]resp.Reply{
+		resp.BulkString("type"), resp.BulkString("index"),
+		resp.BulkString("spec"), resp.Map([]resp.Reply{
+			resp.BulkString("index"), resp.Integer(int64(spec.Index)),
+		}),
+	})
+	if spec.Keyword != "" {
+		beginSearch = resp.Map([]resp.Reply{
+			resp.BulkString("type"), resp.BulkString("keyword"),
+			resp.BulkString("spec"), resp.Map([]resp.Reply{
+				resp.BulkString("keyword"), resp.BulkString(spec.Keyword),
+				resp.BulkString("startfrom"), resp.Integer(int64(spec.Index)),
+			}),
+		})
+	}
+
+	return resp.Map([]resp.Reply{
+		resp.BulkString("flags"), simpleStringSet(spec.Flags),
+		resp.BulkString("begin_search"), beginSearch,
+		resp.BulkString("find_keys"), resp.Map([]resp.Reply{
+			resp.BulkString("type"), resp.BulkString("range"),
+			resp.BulkString("spec"), resp.Map([]resp.Reply{
+				resp.BulkString("lastkey"), resp.Integer(int64(spec.LastKey)),
+				resp.BulkString("keystep"), resp.Integer(int64(max(spec.Step, 1))),
+				resp.BulkString("limit"), resp.Integer(0),
+			}),
+		}),
+	})
+}
+
+// simpleStringSet replies with strs as a set of simple strings
+func simpleStringSet(strs []string) resp.Reply {
+	elems := make([]resp.Reply, len(strs))
+	for i, str := range strs {
+		elems[i] = resp.SimpleString(str)
+	}
+	return resp.Set(elems)
+}
+
+// docs handles COMMAND DOCS [name ...], documenting the named commands, or
+// every command if there are no names. Unknown commands are left out.
+func (c *CommandCommand) docs(names []string) (resp.Reply, error) {
+	if len(names) == 0 {
+		names = c.registry.Names()
+	}
+	var pairs []resp.Reply
+	for _, name := range names {
+		fullName, meta, ok := c.find(name)
+		if !ok {
+			continue
+		}
+		group := meta.Doc.Group
+		if parent, _, isSub := strings.Cut(fullName, "|"); isSub {
+			parentMeta, _ := c.registry.Metadata(strings.ToUpper(parent))
+			group = parentMeta.Doc.Group
+		}
+		pairs = append(pairs, resp.BulkString(fullName), commandDocs(fullName, group, meta))
+	}
+	return resp.Map(pairs), nil
+}
+
+// commandDocs documents a command in the format of COMMAND DOCS. Subcommands
+// are in the group of their command.
+func commandDocs(name, group string, meta *Metadata) resp.Reply {
+	doc := meta.Doc
+	pairs := []resp.Reply{
+		resp.BulkString("summary"), resp.BulkString(doc.Summary),
+		resp.BulkString("since"), resp.BulkString(doc.Since),
+		resp.BulkString("group"), resp.BulkString(group),
+		resp.BulkString("complexity"), resp.BulkString(doc.Complexity),
+	}
+	if doc.DeprecatedSince != "" {
+		pairs = append(pairs,
+			resp.BulkString("doc_flags"), simpleStringSet([]string{"deprecated"}),
+			resp.BulkString("deprecated_since"), resp.BulkString(doc.DeprecatedSince),
+			resp.BulkString("replaced_by"), resp.BulkString(doc.ReplacedBy),
+		)
+	}
+	if len(meta.Subcommands) > 0 {
+		var subcommands []resp.Reply
+		for _, sub := range slices.Sorted(maps.Keys(meta.Subcommands)) {
+			subName := name + "|" + sub
+			subcommands = append(subcommands, resp.BulkString(subName), commandDocs(subName, group, meta.Subcommands[sub]))
+		}
+		pairs = append(pairs, resp.BulkString("subcommands"), resp.Map(subcommands))
+	}
+	return resp.Map(pairs)
+}
+
+// list handles COMMAND LIST [FILTERBY MODULE name | ACLCAT category |
+// PATTERN pattern], listing the commands and subcommands
+func (c *CommandCommand) list(args []string) (resp.Reply, error) {
+	filter := func(string, *Metadata) bool { return true }
+	switch {
+	case len(args) == 0:
+	case len(args) == 3 && strings.EqualFold(args[0], "FILTERBY"):
+		value := args[2]
+		switch strings.ToUpper(args[1]) {
+		case "MODULE":
+			// There are no modules
+			filter = func(string, *Metadata) bool { return false }
+		case "ACLCAT":
+			category := strings.ToLower(value)
+			filter = func(_ string, meta *Metadata) bool { return slices.Contains(meta.AllCategories(), category) }
+		case "PATTERN":
+			filter = func(name string, _ *Metadata) bool { return glob.Match(value, name, true) }
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	default:
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	var names []string
+	for _, name := range c.registry.Names() {
+		meta, _ := c.registry.Metadata(name)
+		name = strings.ToLower(name)
+		if filter(name, meta) {
+			names = append(names, name)
+		}
+		for _, sub := range slices.Sorted(maps.Keys(meta.Subcommands)) {
+			if subName := name + "|" + sub; filter(subName, meta.Subcommands[sub]) {
+				names = append(names, subName)
+			}
+		}
+	}
+	return resp.BulkStrings(names), nil
+}
+
+// getKeys handles COMMAND GETKEYS and GETKEYSANDFLAGS, returning the keys of
+// the command argv, along with their flags if withFlags is set
+func (c *CommandCommand) getKeys(argv []string, withFlags bool) (resp.Reply, error) {
+	meta, ok := c.registry.Metadata(strings.ToUpper(argv[0]))
+	if !ok {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "Invalid command specified")
+	}
+	_, meta = meta.subcommand(argv[1:])
+	if len(meta.KeySpecs) == 0 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "The command has no key arguments")
+	}
+	if !meta.checkArity(len(argv)) {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "Invalid number of arguments specified for command")
+	}
+
+	keys, flags := meta.keys(argv)
+	if len(keys) == 0 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "Invalid arguments specified for command")
+	}
+	replies := make([]resp.Reply, len(keys))
+	for i, key := range keys {
+		replies[i] = resp.BulkString(key)
+		if withFlags {
+			replies[i] = resp.Array([]resp.Reply{resp.BulkString(key), simpleStringSet(flags[i])})
+		}
+	}
+	return resp.Array(replies), nil
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+// newCommandTestCommand creates a COMMAND command for a registry holding a
+// few commands
+func newCommandTestCommand() *CommandCommand {
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	registry.Register(NewGeoRadiusCommand())
+	registry.Register(NewConfigCommand())
+	cmd := NewCommandCommand(registry)
+	registry.Register(cmd)
+	return cmd
+}
+
+func TestCommandCommand_Name(t *testing.T) {
+	cmd := NewCommandCommand(NewRegistry())
+	if cmd.Name() != "COMMAND" {
+		t.Errorf("Expected command name to be 'COMMAND', got %s", cmd.Name())
+	}
+}
+
+func TestCommandCommand_Execute(t *testing.T) {
+	cmd := newCommandTestCommand()
+
+	getInfo := "*10\r\n$3\r\nget\r\n:2\r\n*2\r\n+readonly\r\n+fast\r\n:1\r\n:1\r\n:1\r\n*3\r\n+@read\r\n+@string\r\n+@fast\r\n*0\r\n" +
+		"*1\r\n*6\r\n$5\r\nflags\r\n*2\r\n+RO\r\n+access\r\n" +
+		"$12\r\nbegin_search\r\n*4\r\n$4\r\ntype\r\n$5\r\nindex\r\n$4\r\nspec\r\n*2\r\n$5\r\nindex\r\n:1\r\n" +
+		"$9\r\nfind_keys\r\n*4\r\n$4\r\ntype\r\n$5\r\nrange\r\n$4\r\nspec\r\n*6\r\n$7\r\nlastkey\r\n:0\r\n$7\r\nkeystep\r\n:1\r\n$5\r\nlimit\r\n:0\r\n" +
+		"*0\r\n"
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "count", args: []string{"COUNT"}, expected: ":4\r\n"},
+		{name: "count arity", args: []string{"COUNT", "x"}, errMsg: "wrong number of arguments for 'command|count' command"},
+		{name: "info", args: []string{"INFO", "get", "nosuch"}, expected: "*2\r\n" + getInfo + "*-1\r\n"},
+		{name: "info subcommand", args: []string{"INFO", "config|get"}, expected: "*1\r\n*10\r\n$10\r\nconfig|get\r\n:-3\r\n*4\r\n+admin\r\n+noscript\r\n+loading\r\n+stale\r\n:0\r\n:0\r\n:0\r\n*3\r\n+@admin\r\n+@slow\r\n+@dangerous\r\n*0\r\n*0\r\n*0\r\n"},
+		{name: "list", args: []string{"LIST"}, expected: "*13\r\n$7\r\ncommand\r\n$13\r\ncommand|count\r\n$12\r\ncommand|docs\r\n$15\r\ncommand|getkeys\r\n$23\r\ncommand|getkeysandflags\r\n$12\r\ncommand|help\r\n$12\r\ncommand|info\r\n$12\r\ncommand|list\r\n$6\r\nconfig\r\n$10\r\nconfig|get\r\n$10\r\nconfig|set\r\n$9\r\ngeoradius\r\n$3\r\nget\r\n"},
+		{name: "list by category", args: []string{"LIST", "FILTERBY", "ACLCAT", "admin"}, expected: "*2\r\n$10\r\nconfig|get\r\n$10\r\nconfig|set\r\n"},
+		{name: "list by pattern", args: []string{"LIST", "FILTERBY", "PATTERN", "ge*"}, expected: "*2\r\n$9\r\ngeoradius\r\n$3\r\nget\r\n"},
+		{name: "list by module", args: []string{"LIST", "FILTERBY", "MODULE", "json"}, expected: "*0\r\n"},
+		{name: "list syntax", args: []string{"LIST", "FILTERBY", "NAME", "get"}, errMsg: "syntax error"},
+		{name: "getkeys", args: []string{"GETKEYS", "GEORADIUS", "src", "0", "0", "1", "km", "STORE", "dest"}, expected: "*2\r\n$3\r\nsrc\r\n$4\r\ndest\r\n"},
+		{name: "getkeysandflags", args: []string{"GETKEYSANDFLAGS", "get", "key"}, expected: "*1\r\n*2\r\n$3\r\nkey\r\n*2\r\n+RO\r\n+access\r\n"},
+		{name: "getkeys unknown command", args: []string{"GETKEYS", "nosuch", "key"}, errMsg: "Invalid command specified"},
+		{name: "getkeys no keys", args: []string{"GETKEYS", "config", "get", "x"}, errMsg: "The command has no key arguments"},
+		{name: "getkeys arity", args: []string{"GETKEYS", "get", "a", "b"}, errMsg: "Invalid number of arguments specified for command"},
+		{name: "unknown subcommand", args: []string{"FOO"}, errMsg: "unknown subcommand 'FOO'. Try COMMAND HELP."},
+	})
+}
+
+func TestCommandCommand_Docs(t *testing.T) {
+	cmd := newCommandTestCommand()
+
+	result, err := execute(cmd, []string{"DOCS", "georadius", "config|set", "nosuch"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, expected := range []string{
+		"*4\r\n$9\r\ngeoradius\r\n",
+		"$7\r\nsummary\r\n$105\r\nQueries a geospatial index for members within a distance from a coordinate, optionally stores the result.\r\n",
+		"$5\r\ngroup\r\n$3\r\ngeo\r\n",
+		"$9\r\ndoc_flags\r\n*1\r\n+deprecated\r\n$16\r\ndeprecated_since\r\n$5\r\n6.2.0\r\n",
+		"$10\r\nconfig|set\r\n",
+		"$5\r\ngroup\r\n$6\r\nserver\r\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in %q", expected, result)
+		}
+	}
+}
+
+func TestCommandCommand_AllCommands(t *testing.T) {
+	cmd := newCommandTestCommand()
+
+	result, err := execute(cmd, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(result, "*4\r\n*10\r\n$7\r\ncommand\r\n") {
+		t.Errorf("Expected every command to be described, got %q", result)
+	}
+	// GEORADIUS finds its STORE keys with a keyword
+	if !strings.Contains(result, "+movablekeys\r\n") {
+		t.Errorf("Expected GEORADIUS to have movable keys, got %q", result)
+	}
+}
@@ -0,0 +1,244 @@
+package command
+
+// Key spec flags shared by many commands
+var (
+	readKey        = []string{KeyRO, KeyAccess}
+	existenceKey   = []string{KeyRO}
+	overwriteKey   = []string{KeyOW, KeyUpdate}
+	updateKey      = []string{KeyRW, KeyAccess, KeyUpdate}
+	removeKey      = []string{KeyRM, KeyDelete}
+	moveSourceKey  = []string{KeyRW, KeyAccess, KeyDelete}
+	insertKey      = []string{KeyRW, KeyInsert}
+	accessWriteKey = []string{KeyRW, KeyAccess}
+)
+
+// firstKey describes a command whose first argument is its only key
+func firstKey(flags []string) []KeySpec {
+	return []KeySpec{{Index: 1, Step: 1, Flags: flags}}
+}
+
+// allKeys describes a command whose arguments are all keys
+func allKeys(flags []string) []KeySpec {
+	return []KeySpec{{Index: 1, LastKey: -1, Step: 1, Flags: flags}}
+}
+
+// Tips shared by many commands
+var (
+	allShardsSucceededTips = []string{"request_policy:all_shards", "response_policy:all_succeeded"}
+	allNodesSucceededTips  = []string{"request_policy:all_nodes", "response_policy:all_succeeded"}
+	multiShardSumTips      = []string{"request_policy:multi_shard", "response_policy:agg_sum"}
+)
+
+// Flags shared by many commands
+const (
+	connectionFlags      = FlagNoScript | FlagLoading | FlagStale
+	adminConnectionFlags = FlagAdmin | FlagNoScript | FlagLoading | FlagStale
+)
+
+// helpMetadata describes the HELP subcommand of container commands
+func helpMetadata(since string, categories []string) *Metadata {
+	return &Metadata{Arity: 2, Flags: FlagLoading | FlagStale, Categories: categories,
+		Doc: Doc{Summary: "Returns helpful text about the different subcommands.", Since: since, Complexity: "O(1)"}}
+}
+
+// commandTable describes the commands of the server. Registry.Register gives
+// each command its entry, or defaultMetadata if it has none.
+var commandTable = map[string]*Metadata{
+	"PING": {Arity: -1, Flags: FlagFast, Categories: []string{"connection"}, Tips: allShardsSucceededTips,
+		Doc: Doc{Summary: "Returns the server's liveliness response.", Since: "1.0.0", Group: "connection", Complexity: "O(1)"}},
+	"ECHO": {Arity: 2, Flags: FlagFast, Categories: []string{"connection"},
+		Doc: Doc{Summary: "Returns the given string.", Since: "1.0.0", Group: "connection", Complexity: "O(1)"}},
+	"HELLO": {Arity: -1, Flags: connectionFlags | FlagFast | FlagNoAuth, Categories: []string{"connection"},
+		Doc: Doc{Summary: "Handshakes with the Redis server.", Since: "6.0.0", Group: "connection", Complexity: "O(1)"}},
+	"AUTH": {Arity: -2, Flags: connectionFlags | FlagFast | FlagNoAuth, Categories: []string{"connection"},
+		Doc: Doc{Summary: "Authenticates the connection.", Since: "1.0.0", Group: "connection", Complexity: "O(N) where N is the number of passwords defined for the user"}},
+	"QUIT": {Arity: -1, Flags: connectionFlags | FlagFast | FlagNoAuth, Categories: []string{"connection"},
+		Doc: Doc{Summary: "Closes the connection.", Since: "1.0.0", Group: "connection", Complexity: "O(1)", DeprecatedSince: "7.2.0", ReplacedBy: "just closing the connection"}},
+	"CLIENT": {Arity: -2,
+		Doc: Doc{Summary: "A container for client connection commands.", Since: "2.4.0", Group: "connection", Complexity: "Depends on subcommand."},
+		Subcommands: map[string]*Metadata{
+			"id": {Arity: 2, Flags: connectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Returns the unique client ID of the connection.", Since: "5.0.0", Complexity: "O(1)"}},
+			"info": {Arity: 2, Flags: connectionFlags, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output"},
+				Doc: Doc{Summary: "Returns information about the connection.", Since: "6.2.0", Complexity: "O(1)"}},
+			"list": {Arity: -2, Flags: adminConnectionFlags, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output"},
+				Doc: Doc{Summary: "Lists open connections.", Since: "2.4.0", Complexity: "O(N) where N is the number of client connections"}},
+			"setname": {Arity: 3, Flags: connectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Sets the connection name.", Since: "2.6.9", Complexity: "O(1)"}},
+			"getname": {Arity: 2, Flags: connectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Returns the name of the connection.", Since: "2.6.9", Complexity: "O(1)"}},
+			"kill": {Arity: -3, Flags: adminConnectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Terminates open connections.", Since: "2.4.0", Complexity: "O(N) where N is the number of client connections"}},
+			"pause": {Arity: -3, Flags: adminConnectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Suspends commands processing.", Since: "3.0.0", Complexity: "O(1)"}},
+			"unpause": {Arity: 2, Flags: adminConnectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Resumes processing commands from paused clients.", Since: "6.2.0", Complexity: "O(N) Where N is the number of paused clients"}},
+			"no-evict": {Arity: 3, Flags: adminConnectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Sets the client eviction mode of the connection.", Since: "7.0.0", Complexity: "O(1)"}},
+			"reply": {Arity: 3, Flags: connectionFlags, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Instructs the server whether to reply to commands.", Since: "3.2.0", Complexity: "O(1)"}},
+			"help": helpMetadata("5.0.0", []string{"connection"}),
+		}},
+	"ACL": {Arity: -2,
+		Doc: Doc{Summary: "A container for Access List Control commands.", Since: "6.0.0", Group: "server", Complexity: "Depends on subcommand."},
+		Subcommands: map[string]*Metadata{
+			"cat": {Arity: -2, Flags: connectionFlags,
+				Doc: Doc{Summary: "Lists the ACL categories, or the commands inside a category.", Since: "6.0.0", Complexity: "O(1) since the categories and commands are a fixed set."}},
+			"deluser": {Arity: -3, Flags: adminConnectionFlags, Tips: allNodesSucceededTips,
+				Doc: Doc{Summary: "Deletes ACL users, and terminates their connections.", Since: "6.0.0", Complexity: "O(1) amortized time considering the typical user."}},
+			"dryrun": {Arity: -4, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Simulates the execution of a command by a user, without executing the command.", Since: "7.0.0", Complexity: "O(1)."}},
+			"getuser": {Arity: 3, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Lists the ACL rules of a user.", Since: "6.0.0", Complexity: "O(N). Where N is the number of password, command and pattern rules that the user has."}},
+			"list": {Arity: 2, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Dumps the effective rules in ACL file format.", Since: "6.0.0", Complexity: "O(N). Where N is the number of configured users."}},
+			"load": {Arity: 2, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Reloads the rules from the configured ACL file.", Since: "6.0.0", Complexity: "O(N). Where N is the number of configured users."}},
+			"log": {Arity: -2, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Lists recent security events generated due to ACL rules.", Since: "6.0.0", Complexity: "O(N) with N being the number of entries shown."}},
+			"save": {Arity: 2, Flags: adminConnectionFlags, Tips: allNodesSucceededTips,
+				Doc: Doc{Summary: "Saves the effective ACL rules in the configured ACL file.", Since: "6.0.0", Complexity: "O(N). Where N is the number of configured users."}},
+			"setuser": {Arity: -3, Flags: adminConnectionFlags, Tips: allNodesSucceededTips,
+				Doc: Doc{Summary: "Creates and modifies an ACL user and its rules.", Since: "6.0.0", Complexity: "O(N). Where N is the number of rules provided."}},
+			"users": {Arity: 2, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Lists all ACL users.", Since: "6.0.0", Complexity: "O(N). Where N is the number of configured users."}},
+			"whoami": {Arity: 2, Flags: connectionFlags,
+				Doc: Doc{Summary: "Returns the authenticated username of the current connection.", Since: "6.0.0", Complexity: "O(1)"}},
+			"help": helpMetadata("6.0.0", nil),
+		}},
+	"CONFIG": {Arity: -2,
+		Doc: Doc{Summary: "A container for server configuration commands.", Since: "2.0.0", Group: "server", Complexity: "Depends on subcommand."},
+		Subcommands: map[string]*Metadata{
+			"get": {Arity: -3, Flags: adminConnectionFlags,
+				Doc: Doc{Summary: "Returns the effective values of configuration parameters.", Since: "2.0.0", Complexity: "O(N) when N is the number of configuration parameters provided"}},
+			"set": {Arity: -4, Flags: adminConnectionFlags, Tips: allNodesSucceededTips,
+				Doc: Doc{Summary: "Sets configuration parameters in-flight.", Since: "2.0.0", Complexity: "O(N) when N is the number of configuration parameters provided"}},
+		}},
+	"COMMAND": {Arity: -1, Flags: FlagLoading | FlagStale, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output_order"},
+		Doc: Doc{Summary: "Returns detailed information about all commands.", Since: "2.8.13", Group: "server", Complexity: "O(N) where N is the total number of Redis commands"},
+		Subcommands: map[string]*Metadata{
+			"count": {Arity: 2, Flags: FlagLoading | FlagStale, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Returns a count of commands.", Since: "2.8.13", Complexity: "O(1)"}},
+			"docs": {Arity: -2, Flags: FlagLoading | FlagStale, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output_order"},
+				Doc: Doc{Summary: "Returns documentary information about one, multiple or all commands.", Since: "7.0.0", Complexity: "O(N) where N is the number of commands to look up"}},
+			"getkeys": {Arity: -3, Flags: FlagLoading | FlagStale, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Extracts the key names from an arbitrary command.", Since: "2.8.13", Complexity: "O(N) where N is the number of arguments to the command"}},
+			"getkeysandflags": {Arity: -3, Flags: FlagLoading | FlagStale, Categories: []string{"connection"},
+				Doc: Doc{Summary: "Extracts the key names and access flags for an arbitrary command.", Since: "7.0.0", Complexity: "O(N) where N is the number of arguments to the command"}},
+			"info": {Arity: -2, Flags: FlagLoading | FlagStale, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output_order"},
+				Doc: Doc{Summary: "Returns information about one, multiple or all commands.", Since: "2.8.13", Complexity: "O(N) where N is the number of commands to look up"}},
+			"list": {Arity: -2, Flags: FlagLoading | FlagStale, Categories: []string{"connection"}, Tips: []string{"nondeterministic_output_order"},
+				Doc: Doc{Summary: "Returns a list of command names.", Since: "7.0.0", Complexity: "O(N) where N is the total number of Redis commands"}},
+			"help": helpMetadata("5.0.0", []string{"connection"}),
+		}},
+	"INFO": {Arity: -1, Flags: FlagLoading | FlagStale, Categories: []string{"dangerous"},
+		Tips: []string{"nondeterministic_output", "request_policy:all_shards", "response_policy:special"},
+		Doc:  Doc{Summary: "Returns information and statistics about the server.", Since: "1.0.0", Group: "server", Complexity: "O(1)"}},
+	"SELECT": {Arity: 2, Flags: FlagLoading | FlagStale | FlagFast, Categories: []string{"connection"},
+		Doc: Doc{Summary: "Changes the selected database.", Since: "1.0.0", Group: "connection", Complexity: "O(1)"}},
+	"SWAPDB": {Arity: 3, Flags: FlagWrite | FlagFast, Categories: []string{"keyspace", "dangerous"},
+		Doc: Doc{Summary: "Swaps two Redis databases.", Since: "4.0.0", Group: "server", Complexity: "O(N) where N is the count of clients watching or blocking on keys from both databases."}},
+
+	"SET": {Arity: -3, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"string"}, KeySpecs: firstKey(overwriteKey),
+		Doc: Doc{Summary: "Sets the string value of a key, ignoring its type. The key is created if it doesn't exist.", Since: "1.0.0", Group: "string", Complexity: "O(1)"}},
+	"GET": {Arity: 2, Flags: FlagReadOnly | FlagFast, Categories: []string{"string"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns the string value of a key.", Since: "1.0.0", Group: "string", Complexity: "O(1)"}},
+
+	"DEL": {Arity: -2, Flags: FlagWrite, Categories: []string{"keyspace"}, KeySpecs: allKeys(removeKey), Tips: multiShardSumTips,
+		Doc: Doc{Summary: "Deletes one or more keys.", Since: "1.0.0", Group: "generic", Complexity: "O(N) where N is the number of keys that will be removed. When a key to remove holds a value other than a string, the individual complexity for this key is O(M) where M is the number of elements in the list, set, sorted set or hash. Removing a single key that holds a string value is O(1)."}},
+	"UNLINK": {Arity: -2, Flags: FlagWrite | FlagFast, Categories: []string{"keyspace"}, KeySpecs: allKeys(removeKey), Tips: multiShardSumTips,
+		Doc: Doc{Summary: "Asynchronously deletes one or more keys.", Since: "4.0.0", Group: "generic", Complexity: "O(1) for each key removed regardless of its size. Then the command does O(N) work in a different thread in order to reclaim memory, where N is the number of allocations the deleted objects where composed of."}},
+	"EXISTS": {Arity: -2, Flags: FlagReadOnly | FlagFast, Categories: []string{"keyspace"}, KeySpecs: allKeys(existenceKey), Tips: multiShardSumTips,
+		Doc: Doc{Summary: "Determines whether one or more keys exist.", Since: "1.0.0", Group: "generic", Complexity: "O(N) where N is the number of keys to check."}},
+	"TYPE": {Arity: 2, Flags: FlagReadOnly | FlagFast, Categories: []string{"keyspace"}, KeySpecs: firstKey(existenceKey),
+		Doc: Doc{Summary: "Determines the type of value stored at a key.", Since: "1.0.0", Group: "generic", Complexity: "O(1)"}},
+	"RENAME": {Arity: 3, Flags: FlagWrite, Categories: []string{"keyspace"},
+		KeySpecs: []KeySpec{{Index: 1, Step: 1, Flags: moveSourceKey}, {Index: 2, Step: 1, Flags: overwriteKey}},
+		Doc:      Doc{Summary: "Renames a key and overwrites the destination.", Since: "1.0.0", Group: "generic", Complexity: "O(1)"}},
+	"RENAMENX": {Arity: 3, Flags: FlagWrite | FlagFast, Categories: []string{"keyspace"},
+		KeySpecs: []KeySpec{{Index: 1, Step: 1, Flags: moveSourceKey}, {Index: 2, Step: 1, Flags: []string{KeyOW, KeyInsert}}},
+		Doc:      Doc{Summary: "Renames a key only when the target key name doesn't exist.", Since: "1.0.0", Group: "generic", Complexity: "O(1)"}},
+	"COPY": {Arity: -3, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"keyspace"},
+		KeySpecs: []KeySpec{{Index: 1, Step: 1, Flags: readKey}, {Index: 2, Step: 1, Flags: overwriteKey}},
+		Doc:      Doc{Summary: "Copies the value of a key to a new key.", Since: "6.2.0", Group: "generic", Complexity: "O(N) worst case for collections, where N is the number of nested items. O(1) for string values."}},
+	"MOVE": {Arity: 3, Flags: FlagWrite | FlagFast, Categories: []string{"keyspace"}, KeySpecs: firstKey(moveSourceKey),
+		Doc: Doc{Summary: "Moves a key to another database.", Since: "1.0.0", Group: "generic", Complexity: "O(1)"}},
+	"TOUCH": {Arity: -2, Flags: FlagReadOnly | FlagFast, Categories: []string{"keyspace"}, KeySpecs: allKeys(existenceKey), Tips: multiShardSumTips,
+		Doc: Doc{Summary: "Returns the number of existing keys out of those specified after updating the time they were last accessed.", Since: "3.2.1", Group: "generic", Complexity: "O(N) where N is the number of keys that will be touched."}},
+	"RANDOMKEY": {Arity: 1, Flags: FlagReadOnly, Categories: []string{"keyspace"},
+		Tips: []string{"request_policy:all_shards", "response_policy:special", "nondeterministic_output"},
+		Doc:  Doc{Summary: "Returns a random key name from the database.", Since: "1.0.0", Group: "generic", Complexity: "O(1)"}},
+	"DBSIZE": {Arity: 1, Flags: FlagReadOnly | FlagFast, Categories: []string{"keyspace"},
+		Tips: []string{"request_policy:all_shards", "response_policy:agg_sum"},
+		Doc:  Doc{Summary: "Returns the number of keys in the database.", Since: "1.0.0", Group: "server", Complexity: "O(1)"}},
+	"SCAN": {Arity: -2, Flags: FlagReadOnly, Categories: []string{"keyspace"},
+		Tips: []string{"nondeterministic_output", "request_policy:special", "response_policy:special"},
+		Doc:  Doc{Summary: "Iterates over the key names in the database.", Since: "2.8.0", Group: "generic", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection."}},
+	"KEYS": {Arity: 2, Flags: FlagReadOnly, Categories: []string{"keyspace", "dangerous"},
+		Tips: []string{"request_policy:all_shards", "nondeterministic_output_order"},
+		Doc:  Doc{Summary: "Returns all key names that match a pattern.", Since: "1.0.0", Group: "generic", Complexity: "O(N) with N being the number of keys in the database, under the assumption that the key names in the database and the given pattern have limited length."}},
+	"FLUSHDB": {Arity: -1, Flags: FlagWrite, Categories: []string{"keyspace", "dangerous"}, Tips: allShardsSucceededTips,
+		Doc: Doc{Summary: "Removes all keys from the current database.", Since: "1.0.0", Group: "server", Complexity: "O(N) where N is the number of keys in the selected database"}},
+	"FLUSHALL": {Arity: -1, Flags: FlagWrite, Categories: []string{"keyspace", "dangerous"}, Tips: allShardsSucceededTips,
+		Doc: Doc{Summary: "Removes all keys from all databases.", Since: "1.0.0", Group: "server", Complexity: "O(N) where N is the total number of keys in all databases"}},
+
+	"SETBIT": {Arity: 4, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"bitmap"}, KeySpecs: firstKey(updateKey),
+		Doc: Doc{Summary: "Sets or clears the bit at offset of the string value. Creates the key if it doesn't exist.", Since: "2.2.0", Group: "bitmap", Complexity: "O(1)"}},
+	"GETBIT": {Arity: 3, Flags: FlagReadOnly | FlagFast, Categories: []string{"bitmap"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns a bit value by offset.", Since: "2.2.0", Group: "bitmap", Complexity: "O(1)"}},
+	"BITCOUNT": {Arity: -2, Flags: FlagReadOnly, Categories: []string{"bitmap"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Counts the number of set bits (population counting) in a string.", Since: "2.6.0", Group: "bitmap", Complexity: "O(N)"}},
+	"BITPOS": {Arity: -3, Flags: FlagReadOnly, Categories: []string{"bitmap"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Finds the first set (1) or clear (0) bit in a string.", Since: "2.8.7", Group: "bitmap", Complexity: "O(N)"}},
+	"BITOP": {Arity: -4, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"bitmap"},
+		KeySpecs: []KeySpec{{Index: 2, Step: 1, Flags: overwriteKey}, {Index: 3, LastKey: -1, Step: 1, Flags: readKey}},
+		Doc:      Doc{Summary: "Performs bitwise operations on multiple strings, and stores the result.", Since: "2.6.0", Group: "bitmap", Complexity: "O(N)"}},
+	"BITFIELD": {Arity: -2, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"bitmap"}, KeySpecs: firstKey(updateKey),
+		Doc: Doc{Summary: "Performs arbitrary bitfield integer operations on strings.", Since: "3.2.0", Group: "bitmap", Complexity: "O(1) for each subcommand specified"}},
+	"BITFIELD_RO": {Arity: -2, Flags: FlagReadOnly | FlagFast, Categories: []string{"bitmap"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Performs arbitrary read-only bitfield integer operations on strings.", Since: "6.0.0", Group: "bitmap", Complexity: "O(1) for each subcommand specified"}},
+
+	"PFADD": {Arity: -2, Flags: FlagWrite | FlagDenyOOM | FlagFast, Categories: []string{"hyperloglog"}, KeySpecs: firstKey(insertKey),
+		Doc: Doc{Summary: "Adds elements to a HyperLogLog key. Creates the key if it doesn't exist.", Since: "2.8.9", Group: "hyperloglog", Complexity: "O(1) to add every element."}},
+	"PFCOUNT": {Arity: -2, Flags: FlagReadOnly | FlagMayReplicate, Categories: []string{"hyperloglog"}, KeySpecs: allKeys(accessWriteKey),
+		Doc: Doc{Summary: "Returns the approximated cardinality of the set(s) observed by the HyperLogLog key(s).", Since: "2.8.9", Group: "hyperloglog", Complexity: "O(1) with a very small average constant time when called with a single key. O(N) with N being the number of keys, and much bigger constant times, when called with multiple keys."}},
+	"PFMERGE": {Arity: -2, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"hyperloglog"},
+		KeySpecs: []KeySpec{{Index: 1, Step: 1, Flags: []string{KeyRW, KeyAccess, KeyInsert}}, {Index: 2, LastKey: -1, Step: 1, Flags: readKey}},
+		Doc:      Doc{Summary: "Merges one or more HyperLogLog values into a single key.", Since: "2.8.9", Group: "hyperloglog", Complexity: "O(N) to merge N HyperLogLogs, but with high constant times."}},
+
+	"GEOADD": {Arity: -5, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"geo"}, KeySpecs: firstKey([]string{KeyRW, KeyUpdate}),
+		Doc: Doc{Summary: "Adds one or more members to a geospatial index. The key is created if it doesn't exist.", Since: "3.2.0", Group: "geo", Complexity: "O(log(N)) for each item added, where N is the number of elements in the sorted set."}},
+	"GEODIST": {Arity: -4, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns the distance between two members of a geospatial index.", Since: "3.2.0", Group: "geo", Complexity: "O(1)"}},
+	"GEOPOS": {Arity: -2, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns the longitude and latitude of members from a geospatial index.", Since: "3.2.0", Group: "geo", Complexity: "O(1) for each member requested."}},
+	"GEOHASH": {Arity: -2, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns members from a geospatial index as geohash strings.", Since: "3.2.0", Group: "geo", Complexity: "O(1) for each member requested."}},
+	"GEOSEARCH": {Arity: -7, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Queries a geospatial index for members inside an area of a box or a circle.", Since: "6.2.0", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area around the shape provided as the filter and M is the number of items inside the shape"}},
+	"GEOSEARCHSTORE": {Arity: -8, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"geo"},
+		KeySpecs: []KeySpec{{Index: 1, Step: 1, Flags: overwriteKey}, {Index: 2, Step: 1, Flags: readKey}},
+		Doc:      Doc{Summary: "Queries a geospatial index for members inside an area of a box or a circle, optionally stores the result.", Since: "6.2.0", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area around the shape provided as the filter and M is the number of items inside the shape"}},
+	"GEORADIUS": {Arity: -6, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"geo"},
+		KeySpecs: []KeySpec{
+			{Index: 1, Step: 1, Flags: readKey},
+			{Index: 6, Keyword: "STORE", Step: 1, Flags: overwriteKey},
+			{Index: 6, Keyword: "STOREDIST", Step: 1, Flags: overwriteKey},
+		},
+		Doc: Doc{Summary: "Queries a geospatial index for members within a distance from a coordinate, optionally stores the result.", Since: "3.2.0", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements inside the bounding box of the circular area delimited by center and radius and M is the number of items inside the index.",
+			DeprecatedSince: "6.2.0", ReplacedBy: "`GEOSEARCH` and `GEOSEARCHSTORE` with the `BYRADIUS` argument"}},
+	"GEORADIUS_RO": {Arity: -6, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns members from a geospatial index that are within a distance from a coordinate.", Since: "3.2.10", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements inside the bounding box of the circular area delimited by center and radius and M is the number of items inside the index.",
+			DeprecatedSince: "6.2.0", ReplacedBy: "`GEOSEARCH` with the `BYRADIUS` argument"}},
+	"GEORADIUSBYMEMBER": {Arity: -5, Flags: FlagWrite | FlagDenyOOM, Categories: []string{"geo"},
+		KeySpecs: []KeySpec{
+			{Index: 1, Step: 1, Flags: readKey},
+			{Index: 5, Keyword: "STORE", Step: 1, Flags: overwriteKey},
+			{Index: 5, Keyword: "STOREDIST", Step: 1, Flags: overwriteKey},
+		},
+		Doc: Doc{Summary: "Queries a geospatial index for members within a distance from a member, optionally stores the result.", Since: "3.2.0", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements inside the bounding box of the circular area delimited by center and radius and M is the number of items inside the index.",
+			DeprecatedSince: "6.2.0", ReplacedBy: "`GEOSEARCH` and `GEOSEARCHSTORE` with the `BYRADIUS` and `FROMMEMBER` arguments"}},
+	"GEORADIUSBYMEMBER_RO": {Arity: -5, Flags: FlagReadOnly, Categories: []string{"geo"}, KeySpecs: firstKey(readKey),
+		Doc: Doc{Summary: "Returns members from a geospatial index that are within a distance from a member.", Since: "3.2.10", Group: "geo", Complexity: "O(N+log(M)) where N is the number of elements inside the bounding box of the circular area delimited by center and radius and M is the number of items inside the index.",
+			DeprecatedSince: "6.2.0", ReplacedBy: "`GEOSEARCH` with the `BYRADIUS` and `FROMMEMBER` arguments"}},
+}
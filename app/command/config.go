@@ -1,6 +1,12 @@
 package command
 
 import (
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
 	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/errors"
 	"github.com/dotslash21/redis-clone/app/resp"
@@ -21,48 +27,56 @@ func (c *ConfigCommand) Name() string {
 }
 
 // Execute handles the CONFIG command
-func (c *ConfigCommand) Execute(args []string) (string, error) {
-	if len(args) < 1 {
-		return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'config' command")
-	}
-
-	subcommand := args[0]
+func (c *ConfigCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	subcommand := strings.ToUpper(args[0])
 	if subcommand == "GET" {
-		if len(args) != 2 {
-			return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'config get' command")
-		}
-
-		searchpattern := args[1]
-		results, err := config.GetConfig(searchpattern)
-		if err != nil {
-			return "", errors.New(errors.ErrorTypeCommand, "error getting config: "+err.Error())
+		// Parameters matched by several patterns are only listed once
+		results := make(map[string]string)
+		for _, pattern := range args[1:] {
+			maps.Copy(results, config.GetConfig(pattern))
 		}
 
 		if len(results) == 0 {
-			return resp.FormatArray([]string{}), nil
+			return resp.Array(nil), nil
 		}
 
-		// Convert the map to a slice of strings for RESP format
-		respArray := make([]string, 0, len(results)*2)
-		for key, value := range results {
-			respArray = append(respArray, resp.FormatBulkString(key, false), resp.FormatBulkString(value, false))
+		// Reply with the parameters in name order, so the output is stable
+		pairs := make([]resp.Reply, 0, len(results)*2)
+		for _, key := range slices.Sorted(maps.Keys(results)) {
+			pairs = append(pairs, resp.BulkString(key), resp.BulkString(results[key]))
 		}
 
-		return resp.FormatArray(respArray), nil
+		return resp.Map(pairs), nil
 	} else if subcommand == "SET" {
 		if (len(args)-1)%2 != 0 {
-			return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'config set' command")
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'config|set' command")
 		}
 
+		settings := make([]config.Setting, 0, (len(args)-1)/2)
 		for i := 1; i < len(args); i += 2 {
-			key := args[i]
-			value := args[i+1]
+			settings = append(settings, config.Setting{Name: args[i], Value: args[i+1]})
+		}
 
-			config.SetConfig(key, value)
+		// Either every parameter is set or none is
+		if err := config.Update(settings); err != nil {
+			return resp.Reply{}, configSetError(err)
 		}
 
-		return resp.FormatSimpleString("OK"), nil
+		return resp.SimpleString("OK"), nil
 	} else {
-		return "", errors.New(errors.ErrorTypeCommand, "unknown subcommand 'config "+subcommand+"'")
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "unknown subcommand 'config "+args[0]+"'")
+	}
+}
+
+// configSetError returns the error CONFIG SET replies with when a parameter
+// is refused, in Redis's wording
+func configSetError(err error) error {
+	var paramErr *config.ParameterError
+	if !stderrors.As(err, &paramErr) {
+		return err
+	}
+	if stderrors.Is(err, config.ErrUnknownParameter) {
+		return errors.New(errors.ErrorTypeCommand, fmt.Sprintf("Unknown option or number of arguments for CONFIG SET - '%s'", paramErr.Name))
 	}
+	return errors.New(errors.ErrorTypeCommand, fmt.Sprintf("CONFIG SET failed (possibly related to argument '%s') - %v", paramErr.Name, paramErr.Err))
 }
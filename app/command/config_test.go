@@ -1,11 +1,13 @@
 package command
 
 import (
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
 )
 
 func TestConfigCommand_Name(t *testing.T) {
@@ -17,10 +19,8 @@ func TestConfigCommand_Name(t *testing.T) {
 
 func TestConfigCommand_Execute(t *testing.T) {
 	cmd := NewConfigCommand()
-
-	// Setup some initial config for testing GET
-	config.SetConfig("test-key", "test-value")
-	config.SetConfig("another-key", "another-value")
+	defer config.SetConfig("hll-sparse-max-bytes", "3000")
+	defer config.SetConfig("acl-log-max-len", "128")
 
 	tests := []struct {
 		name     string
@@ -44,59 +44,94 @@ func TestConfigCommand_Execute(t *testing.T) {
 			name:     "get with too few arguments",
 			args:     []string{"GET"},
 			expected: "",
-			errMsg:   "wrong number of arguments for 'config get' command",
+			errMsg:   "wrong number of arguments for 'config|get' command",
 		},
 		{
-			name:     "get with too many arguments",
-			args:     []string{"GET", "test-key", "extra"},
-			expected: "",
-			errMsg:   "wrong number of arguments for 'config get' command",
+			name:     "get with several patterns",
+			args:     []string{"GET", "acl-log-max-len", "acl*", "nosuch"},
+			expected: "*4\r\n$15\r\nacl-log-max-len\r\n$3\r\n128\r\n$7\r\naclfile\r\n$0\r\n\r\n",
 		},
 		{
 			name:     "set with too few arguments",
-			args:     []string{"SET", "test-key"},
+			args:     []string{"SET", "acl-log-max-len"},
 			expected: "",
-			errMsg:   "wrong number of arguments for 'config set' command",
+			errMsg:   "wrong number of arguments for 'config|set' command",
 		},
 		{
 			name:     "set with odd number of arguments",
 			args:     []string{"SET", "key1", "value1", "key2"},
 			expected: "",
-			errMsg:   "wrong number of arguments for 'config set' command",
+			errMsg:   "wrong number of arguments for 'config|set' command",
 		},
 		{
-			name:     "get existing key",
-			args:     []string{"GET", "test-key"},
-			expected: "*2\r\n$8\r\ntest-key\r\n$10\r\ntest-value\r\n",
+			name:     "get parameter",
+			args:     []string{"GET", "acl-log-max-len"},
+			expected: "*2\r\n$15\r\nacl-log-max-len\r\n$3\r\n128\r\n",
 			errMsg:   "",
 		},
 		{
-			name:     "get non-existing key",
+			name:     "get undeclared parameter",
 			args:     []string{"GET", "non-existing-key"},
 			expected: "*0\r\n",
 			errMsg:   "",
 		},
 		{
 			name:     "get with wildcard",
-			args:     []string{"GET", "*key"},
-			expected: "*4\r\n$8\r\ntest-key\r\n$10\r\ntest-value\r\n$11\r\nanother-key\r\n$13\r\nanother-value\r\n",
+			args:     []string{"GET", "acl*"},
+			expected: "*4\r\n$15\r\nacl-log-max-len\r\n$3\r\n128\r\n$7\r\naclfile\r\n$0\r\n\r\n",
 			errMsg:   "",
 		},
 		{
-			name:     "set new key",
-			args:     []string{"SET", "new-key", "new-value"},
-			expected: "+OK\r\n",
+			name:     "lowercase subcommand",
+			args:     []string{"get", "acl-log-max-len"},
+			expected: "*2\r\n$15\r\nacl-log-max-len\r\n$3\r\n128\r\n",
 			errMsg:   "",
 		},
 		{
-			name:     "set existing key",
-			args:     []string{"SET", "test-key", "updated-value"},
+			name:     "set unknown parameter",
+			args:     []string{"SET", "acl-log-max-len", "10", "new-key", "new-value"},
+			expected: "",
+			errMsg:   "Unknown option or number of arguments for CONFIG SET - 'new-key'",
+		},
+		{
+			name:     "set immutable parameter",
+			args:     []string{"SET", "databases", "32"},
+			expected: "",
+			errMsg:   "CONFIG SET failed (possibly related to argument 'databases') - can't set immutable config",
+		},
+		{
+			name:     "set invalid integer",
+			args:     []string{"SET", "acl-log-max-len", "many"},
+			expected: "",
+			errMsg:   "CONFIG SET failed (possibly related to argument 'acl-log-max-len') - argument couldn't be parsed into an integer",
+		},
+		{
+			name:     "set out of bounds",
+			args:     []string{"SET", "acl-log-max-len", "-1"},
+			expected: "",
+			errMsg:   "CONFIG SET failed (possibly related to argument 'acl-log-max-len') - argument must be between 0 and 2147483647 inclusive",
+		},
+		{
+			name:     "set invalid memory",
+			args:     []string{"SET", "hll-sparse-max-bytes", "lots"},
+			expected: "",
+			errMsg:   "CONFIG SET failed (possibly related to argument 'hll-sparse-max-bytes') - argument must be a memory value",
+		},
+		{
+			name:     "set duplicate parameter",
+			args:     []string{"SET", "acl-log-max-len", "10", "ACL-LOG-MAX-LEN", "20"},
+			expected: "",
+			errMsg:   "CONFIG SET failed (possibly related to argument 'ACL-LOG-MAX-LEN') - duplicate parameter",
+		},
+		{
+			name:     "set parameter",
+			args:     []string{"SET", "acl-log-max-len", "64"},
 			expected: "+OK\r\n",
 			errMsg:   "",
 		},
 		{
-			name:     "set multiple keys",
-			args:     []string{"SET", "multi-key1", "multi-value1", "multi-key2", "multi-value2"},
+			name:     "set multiple parameters",
+			args:     []string{"set", "ACL-LOG-MAX-LEN", "32", "hll-sparse-max-bytes", "1kb"},
 			expected: "+OK\r\n",
 			errMsg:   "",
 		},
@@ -104,7 +139,7 @@ func TestConfigCommand_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := cmd.Execute(tt.args)
+			result, err := execute(cmd, tt.args)
 
 			// Check error
 			if tt.errMsg != "" {
@@ -133,51 +168,71 @@ func TestConfigCommand_Execute(t *testing.T) {
 			}
 
 			// Verify SET operations for some cases
-			if tt.name == "set new key" {
-				result, _ := config.GetConfig("new-key")
-				if _, exists := result["new-key"]; !exists || result["new-key"] != "new-value" {
-					t.Errorf("Failed to set new key: %v", result)
+			if tt.name == "set parameter" {
+				if value, _ := config.Get("acl-log-max-len"); value != "64" {
+					t.Errorf("Failed to set acl-log-max-len: %q", value)
 				}
-			} else if tt.name == "set existing key" {
-				result, _ := config.GetConfig("test-key")
-				if _, exists := result["test-key"]; !exists || result["test-key"] != "updated-value" {
-					t.Errorf("Failed to update existing key: %v", result)
+			} else if tt.name == "set multiple parameters" {
+				if value, _ := config.Get("acl-log-max-len"); value != "32" {
+					t.Errorf("Failed to set acl-log-max-len: %q", value)
 				}
-			} else if tt.name == "set multiple keys" {
-				// Verify multiple keys were set correctly
-				result, _ := config.GetConfig("multi-key*")
-				if _, exists := result["multi-key1"]; !exists || result["multi-key1"] != "multi-value1" {
-					t.Errorf("Failed to set multi-key1: %v", result)
+				if value, _ := config.Get("hll-sparse-max-bytes"); value != "1024" {
+					t.Errorf("Failed to set hll-sparse-max-bytes: %q", value)
 				}
-				if _, exists := result["multi-key2"]; !exists || result["multi-key2"] != "multi-value2" {
-					t.Errorf("Failed to set multi-key2: %v", result)
+			} else if tt.errMsg != "" {
+				// Refused settings change no parameter
+				if value, _ := config.Get("acl-log-max-len"); value != "128" {
+					t.Errorf("Expected acl-log-max-len to be unchanged, got %q", value)
 				}
 			}
 		})
 	}
 }
 
-func TestConfigCommand_Execute_GetWithPattern(t *testing.T) {
+func TestConfigCommand_Execute_SetIsAtomic(t *testing.T) {
 	cmd := NewConfigCommand()
+	defer config.SetConfig("client-output-buffer-limit", DefaultOutputBufferLimits)
+
+	// The second value is refused after the first one is checked
+	_, err := execute(cmd, []string{"SET", "acl-log-max-len", "10", "client-output-buffer-limit", "normal 1kb"})
+	if err == nil || !strings.HasPrefix(err.Error(), "CONFIG SET failed (possibly related to argument 'client-output-buffer-limit')") {
+		t.Fatalf("Expected client-output-buffer-limit to be refused, got %v", err)
+	}
+	if value, _ := config.Get("acl-log-max-len"); value != "128" {
+		t.Errorf("Expected acl-log-max-len to be unchanged, got %q", value)
+	}
+	if value, _ := config.Get("client-output-buffer-limit"); value != DefaultOutputBufferLimits {
+		t.Errorf("Expected client-output-buffer-limit to be unchanged, got %q", value)
+	}
+}
 
-	// Setup test data
-	config.SetConfig("prefix:key1", "value1")
-	config.SetConfig("prefix:key2", "value2")
-	config.SetConfig("other:key", "value3")
+func TestConfigCommand_Execute_GetWithPattern(t *testing.T) {
+	cmd := NewConfigCommand()
 
 	// Test with pattern matching
-	result, err := cmd.Execute([]string{"GET", "prefix:*"})
+	result, err := execute(cmd, []string{"GET", "*-max-*"})
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
 
-	// Since map iteration order is not guaranteed, we need to check if the result contains expected key-value pairs
-	if !strings.Contains(result, "prefix:key1") || !strings.Contains(result, "value1") ||
-		!strings.Contains(result, "prefix:key2") || !strings.Contains(result, "value2") {
-		t.Errorf("Expected result to contain prefix keys and values, got: %s", result)
+	if !strings.Contains(result, "acl-log-max-len") || !strings.Contains(result, "hll-sparse-max-bytes") {
+		t.Errorf("Expected result to contain the matching parameters, got: %s", result)
 	}
 
-	if strings.Contains(result, "other:key") || strings.Contains(result, "value3") {
-		t.Errorf("Result should not contain non-matching keys, got: %s", result)
+	if strings.Contains(result, "requirepass") {
+		t.Errorf("Result should not contain non-matching parameters, got: %s", result)
+	}
+}
+
+func TestConfigCommand_Execute_GetRESP3(t *testing.T) {
+	cmd := NewConfigCommand()
+
+	reply, err := cmd.Execute(NewClient(io.Discard), []string{"GET", "hll-sparse-max-bytes"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "%1\r\n$20\r\nhll-sparse-max-bytes\r\n$4\r\n3000\r\n"
+	if result := encodeReply(reply, resp.RESP3); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
 	}
 }
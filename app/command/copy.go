@@ -0,0 +1,56 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// CopyCommand implements the COPY command
+type CopyCommand struct {
+	dbs *store.Databases
+}
+
+// NewCopyCommand creates a new COPY command
+func NewCopyCommand(dbs *store.Databases) *CopyCommand {
+	return &CopyCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *CopyCommand) Name() string {
+	return "COPY"
+}
+
+// Execute handles the COPY command
+func (c *CopyCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	replace := false
+	dstDB := client.DB
+	for i := 2; i < len(args); i++ {
+		switch {
+		case strings.EqualFold(args[i], "REPLACE"):
+			replace = true
+		case strings.EqualFold(args[i], "DB") && i+1 < len(args):
+			index, err := parseInteger(args[i+1])
+			if err != nil {
+				return resp.Reply{}, err
+			}
+			if dstDB, err = c.dbs.DB(index); err != nil {
+				return resp.Reply{}, err
+			}
+			i++
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	}
+
+	if args[0] == args[1] && dstDB == client.DB {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "source and destination objects are the same")
+	}
+
+	if client.DB.Copy(args[0], dstDB, args[1], replace) {
+		return resp.Integer(1), nil
+	}
+	return resp.Integer(0), nil
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestCopyCommand_Name(t *testing.T) {
+	cmd := NewCopyCommand(store.GetDatabases())
+	if cmd.Name() != "COPY" {
+		t.Errorf("Expected command name to be 'COPY', got %s", cmd.Name())
+	}
+}
+
+func TestCopyCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("copy-src", "value", 0)
+	storeInstance.Delete("copy-dst")
+	storeInstance.Delete("copy-missing")
+	db1, _ := store.GetDatabases().DB(1)
+	db1.Delete("copy-src")
+	cmd := NewCopyCommand(store.GetDatabases())
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "copy to new key", args: []string{"copy-src", "copy-dst"}, expected: ":1\r\n"},
+		{name: "copy onto existing key", args: []string{"copy-src", "copy-dst"}, expected: ":0\r\n"},
+		{name: "copy with replace", args: []string{"copy-src", "copy-dst", "REPLACE"}, expected: ":1\r\n"},
+		{name: "copy to db 0", args: []string{"copy-src", "copy-dst", "DB", "0", "REPLACE"}, expected: ":1\r\n"},
+		{name: "missing source", args: []string{"copy-missing", "copy-dst"}, expected: ":0\r\n"},
+		{name: "same key", args: []string{"copy-src", "copy-src"}, errMsg: "source and destination objects are the same"},
+		{name: "copy to another db", args: []string{"copy-src", "copy-src", "DB", "1"}, expected: ":1\r\n"},
+		{name: "db out of range", args: []string{"copy-src", "copy-dst", "DB", "16"}, errMsg: "DB index is out of range"},
+		{name: "db not an integer", args: []string{"copy-src", "copy-dst", "DB", "x"}, errMsg: "value is not an integer or out of range"},
+		{name: "db without index", args: []string{"copy-src", "copy-dst", "DB"}, errMsg: "syntax error"},
+		{name: "unknown option", args: []string{"copy-src", "copy-dst", "FOO"}, errMsg: "syntax error"},
+		{name: "too few arguments", args: []string{"copy-src"}, errMsg: "wrong number of arguments for 'copy' command"},
+	})
+
+	if !db1.Exists("copy-src") {
+		t.Error("Expected copy-src to be copied to database 1")
+	}
+}
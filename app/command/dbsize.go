@@ -0,0 +1,24 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// DBSizeCommand implements the DBSIZE command
+type DBSizeCommand struct {
+}
+
+// NewDBSizeCommand creates a new DBSIZE command
+func NewDBSizeCommand() *DBSizeCommand {
+	return &DBSizeCommand{}
+}
+
+// Name returns the command name
+func (c *DBSizeCommand) Name() string {
+	return "DBSIZE"
+}
+
+// Execute handles the DBSIZE command
+func (c *DBSizeCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	return resp.Integer(int64(client.DB.DBSize())), nil
+}
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestDBSizeCommand_Name(t *testing.T) {
+	cmd := NewDBSizeCommand()
+	if cmd.Name() != "DBSIZE" {
+		t.Errorf("Expected command name to be 'DBSIZE', got %s", cmd.Name())
+	}
+}
+
+func TestDBSizeCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Flush(false)
+	storeInstance.Set("dbsize-key1", "value", 0)
+	storeInstance.Set("dbsize-key2", "value", 0)
+	cmd := NewDBSizeCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "two keys", args: []string{}, expected: ":2\r\n"},
+		{name: "extra arguments", args: []string{"x"}, errMsg: "wrong number of arguments for 'dbsize' command"},
+	})
+}
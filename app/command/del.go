@@ -0,0 +1,46 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// DelCommand implements the DEL and UNLINK commands
+type DelCommand struct {
+	lazy bool
+}
+
+// NewDelCommand creates a new DEL command
+func NewDelCommand() *DelCommand {
+	return &DelCommand{}
+}
+
+// NewUnlinkCommand creates a new UNLINK command, which releases large values in the background
+func NewUnlinkCommand() *DelCommand {
+	return &DelCommand{lazy: true}
+}
+
+// Name returns the command name
+func (c *DelCommand) Name() string {
+	if c.lazy {
+		return "UNLINK"
+	}
+	return "DEL"
+}
+
+// Execute handles the DEL and UNLINK commands
+func (c *DelCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	deleted := 0
+	for _, key := range args {
+		var removed bool
+		if c.lazy {
+			removed = client.DB.Unlink(key)
+		} else {
+			removed = client.DB.Delete(key)
+		}
+		if removed {
+			deleted++
+		}
+	}
+
+	return resp.Integer(int64(deleted)), nil
+}
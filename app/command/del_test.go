@@ -0,0 +1,37 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestDelCommand_Name(t *testing.T) {
+	if name := NewDelCommand().Name(); name != "DEL" {
+		t.Errorf("Expected command name to be 'DEL', got %s", name)
+	}
+	if name := NewUnlinkCommand().Name(); name != "UNLINK" {
+		t.Errorf("Expected command name to be 'UNLINK', got %s", name)
+	}
+}
+
+func TestDelCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("del-key1", "value", 0)
+	storeInstance.Set("del-key2", "value", 0)
+	storeInstance.Set("unlink-key", "value", 0)
+
+	runCommandTests(t, NewDelCommand(), []commandTestCase{
+		{name: "delete existing and missing keys", args: []string{"del-key1", "del-key2", "del-missing"}, expected: ":2\r\n"},
+		{name: "delete again", args: []string{"del-key1"}, expected: ":0\r\n"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'del' command"},
+	})
+	if storeInstance.Exists("del-key1") || storeInstance.Exists("del-key2") {
+		t.Error("Expected deleted keys to be gone")
+	}
+
+	runCommandTests(t, NewUnlinkCommand(), []commandTestCase{
+		{name: "unlink", args: []string{"unlink-key", "unlink-key"}, expected: ":1\r\n"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'unlink' command"},
+	})
+}
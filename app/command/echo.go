@@ -1,7 +1,6 @@
 package command
 
 import (
-	"github.com/dotslash21/redis-clone/app/errors"
 	"github.com/dotslash21/redis-clone/app/resp"
 )
 
@@ -19,9 +18,6 @@ func (c *EchoCommand) Name() string {
 }
 
 // Execute handles the ECHO command
-func (c *EchoCommand) Execute(args []string) (string, error) {
-	if len(args) != 1 {
-		return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'echo' command")
-	}
-	return resp.FormatBulkString(args[0], false), nil
+func (c *EchoCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	return resp.BulkString(args[0]), nil
 }
@@ -44,7 +44,7 @@ func TestEchoCommand_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := cmd.Execute(tt.args)
+			result, err := execute(cmd, tt.args)
 
 			// Check error
 			if tt.errMsg != "" {
@@ -0,0 +1,31 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// ExistsCommand implements the EXISTS command
+type ExistsCommand struct {
+}
+
+// NewExistsCommand creates a new EXISTS command
+func NewExistsCommand() *ExistsCommand {
+	return &ExistsCommand{}
+}
+
+// Name returns the command name
+func (c *ExistsCommand) Name() string {
+	return "EXISTS"
+}
+
+// Execute handles the EXISTS command. A key given several times is counted each time.
+func (c *ExistsCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	count := 0
+	for _, key := range args {
+		if client.DB.Exists(key) {
+			count++
+		}
+	}
+
+	return resp.Integer(int64(count)), nil
+}
@@ -0,0 +1,28 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestExistsCommand_Name(t *testing.T) {
+	cmd := NewExistsCommand()
+	if cmd.Name() != "EXISTS" {
+		t.Errorf("Expected command name to be 'EXISTS', got %s", cmd.Name())
+	}
+}
+
+func TestExistsCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("exists-key", "value", 0)
+	storeInstance.Delete("exists-missing")
+	cmd := NewExistsCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "existing key", args: []string{"exists-key"}, expected: ":1\r\n"},
+		{name: "missing key", args: []string{"exists-missing"}, expected: ":0\r\n"},
+		{name: "repeated keys are counted each time", args: []string{"exists-key", "exists-key", "exists-missing"}, expected: ":2\r\n"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'exists' command"},
+	})
+}
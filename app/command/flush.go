@@ -0,0 +1,57 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// FlushCommand implements the FLUSHDB and FLUSHALL commands
+type FlushCommand struct {
+	// dbs is set for FLUSHALL, which flushes every database
+	dbs *store.Databases
+}
+
+// NewFlushDBCommand creates a new FLUSHDB command
+func NewFlushDBCommand() *FlushCommand {
+	return &FlushCommand{}
+}
+
+// NewFlushAllCommand creates a new FLUSHALL command
+func NewFlushAllCommand(dbs *store.Databases) *FlushCommand {
+	return &FlushCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *FlushCommand) Name() string {
+	if c.dbs != nil {
+		return "FLUSHALL"
+	}
+	return "FLUSHDB"
+}
+
+// Execute handles the FLUSHDB and FLUSHALL commands
+func (c *FlushCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	async := false
+	if len(args) > 1 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+	if len(args) == 1 {
+		switch strings.ToUpper(args[0]) {
+		case "ASYNC":
+			async = true
+		case "SYNC":
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	}
+
+	if c.dbs != nil {
+		c.dbs.FlushAll(async)
+	} else {
+		client.DB.Flush(async)
+	}
+	return resp.SimpleString("OK"), nil
+}
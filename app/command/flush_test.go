@@ -0,0 +1,38 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestFlushCommand_Name(t *testing.T) {
+	if name := NewFlushDBCommand().Name(); name != "FLUSHDB" {
+		t.Errorf("Expected command name to be 'FLUSHDB', got %s", name)
+	}
+	if name := NewFlushAllCommand(store.GetDatabases()).Name(); name != "FLUSHALL" {
+		t.Errorf("Expected command name to be 'FLUSHALL', got %s", name)
+	}
+}
+
+func TestFlushCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+
+	for _, cmd := range []*FlushCommand{NewFlushDBCommand(), NewFlushAllCommand(store.GetDatabases())} {
+		for _, mode := range [][]string{{}, {"SYNC"}, {"async"}} {
+			storeInstance.Set("flush-key", "value", 0)
+			runCommandTests(t, cmd, []commandTestCase{
+				{name: strings.TrimSpace(cmd.Name() + " " + strings.Join(mode, " ")), args: mode, expected: "+OK\r\n"},
+			})
+			if storeInstance.Exists("flush-key") {
+				t.Errorf("Expected %s %v to remove every key", cmd.Name(), mode)
+			}
+		}
+
+		runCommandTests(t, cmd, []commandTestCase{
+			{name: "invalid mode", args: []string{"LATER"}, errMsg: "syntax error"},
+			{name: "too many arguments", args: []string{"ASYNC", "SYNC"}, errMsg: "syntax error"},
+		})
+	}
+}
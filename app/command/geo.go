@@ -0,0 +1,84 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/geo"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// parseFloat parses a floating point argument, returning an error with the
+// given message if it is not a valid number. NaN is never accepted.
+func parseFloat(arg, msg string) (float64, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(value) {
+		return 0, errors.New(errors.ErrorTypeCommand, msg)
+	}
+	return value, nil
+}
+
+// parseLonLat parses a longitude and latitude pair, checking that it can be geohashed
+func parseLonLat(lonArg, latArg string) (float64, float64, error) {
+	longitude, err := parseFloat(lonArg, "value is not a valid float")
+	if err != nil {
+		return 0, 0, err
+	}
+	latitude, err := parseFloat(latArg, "value is not a valid float")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !geo.ValidCoordinates(longitude, latitude) {
+		return 0, 0, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("invalid longitude,latitude pair %f,%f", longitude, latitude))
+	}
+	return longitude, latitude, nil
+}
+
+// parseGeoUnit returns the number of meters in the given distance unit
+func parseGeoUnit(arg string) (float64, error) {
+	switch strings.ToLower(arg) {
+	case "m":
+		return 1, nil
+	case "km":
+		return 1000, nil
+	case "ft":
+		return 0.3048, nil
+	case "mi":
+		return 1609.34, nil
+	default:
+		return 0, errors.New(errors.ErrorTypeCommand, "unsupported unit provided. please use M, KM, FT, MI")
+	}
+}
+
+// geoPosition returns the position of a member of a geo sorted set
+func geoPosition(zset *types.SortedSet, member string) (float64, float64, bool) {
+	score, ok := zset.Score(member)
+	if !ok {
+		return 0, 0, false
+	}
+	longitude, latitude := geo.Decode(uint64(score))
+	return longitude, latitude, true
+}
+
+// formatDistance formats a distance with the four decimals used by GEO
+// replies. Like Redis, distances are bulk strings in RESP3 too.
+func formatDistance(distance float64) resp.Reply {
+	return resp.BulkString(strconv.FormatFloat(distance, 'f', 4, 64))
+}
+
+// formatCoordinates formats a position as a two element array of doubles,
+// printing each coordinate with 17 decimals and trailing zeroes removed
+func formatCoordinates(longitude, latitude float64) resp.Reply {
+	format := func(v float64) resp.Reply {
+		s := strconv.FormatFloat(v, 'f', 17, 64)
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+		return resp.FormattedDouble(v, s)
+	}
+	return resp.Array([]resp.Reply{format(longitude), format(latitude)})
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/geo"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// GeoAddCommand implements the GEOADD command
+type GeoAddCommand struct {
+}
+
+// NewGeoAddCommand creates a new GEOADD command
+func NewGeoAddCommand() *GeoAddCommand {
+	return &GeoAddCommand{}
+}
+
+// Name returns the command name
+func (c *GeoAddCommand) Name() string {
+	return "GEOADD"
+}
+
+// geoItem is a member and its geohash score
+type geoItem struct {
+	member string
+	score  float64
+}
+
+// Execute handles the GEOADD command
+func (c *GeoAddCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	var nx, xx, ch bool
+	i := 1
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "CH":
+			ch = true
+		default:
+			break options
+		}
+	}
+	if (len(args)-i)%3 != 0 || len(args) == i || (nx && xx) {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	// Validate every position before modifying the set
+	items := make([]geoItem, 0, (len(args)-i)/3)
+	for ; i < len(args); i += 3 {
+		longitude, latitude, err := parseLonLat(args[i], args[i+1])
+		if err != nil {
+			return resp.Reply{}, err
+		}
+		items = append(items, geoItem{member: args[i+2], score: float64(geo.Encode(longitude, latitude))})
+	}
+
+	count := 0
+	err := client.DB.UpdateZSet(args[0], func(zset *types.SortedSet, exists bool) error {
+		for _, item := range items {
+			current, ok := zset.Score(item.member)
+			if (ok && nx) || (!ok && xx) {
+				continue
+			}
+			zset.Add(item.member, item.score)
+			if !ok || (ch && current != item.score) {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(int64(count)), nil
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestGeoAddCommand_Name(t *testing.T) {
+	cmd := NewGeoAddCommand()
+	if cmd.Name() != "GEOADD" {
+		t.Errorf("Expected command name to be 'GEOADD', got %s", cmd.Name())
+	}
+}
+
+func TestGeoAddCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geoadd-key")
+	storeInstance.Set("geoadd-string", "value", 0)
+	cmd := NewGeoAddCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "add new members", args: []string{"geoadd-key", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania"}, expected: ":2\r\n"},
+		{name: "add existing member", args: []string{"geoadd-key", "13.361389", "38.115556", "Palermo"}, expected: ":0\r\n"},
+		{name: "update without CH", args: []string{"geoadd-key", "13", "38", "Palermo"}, expected: ":0\r\n"},
+		{name: "update with CH", args: []string{"geoadd-key", "CH", "13.361389", "38.115556", "Palermo"}, expected: ":1\r\n"},
+		{name: "NX skips existing members", args: []string{"geoadd-key", "NX", "CH", "13", "38", "Palermo", "12.758489", "38.788135", "edge1"}, expected: ":1\r\n"},
+		{name: "XX skips new members", args: []string{"geoadd-key", "XX", "17.241510", "38.788135", "edge2"}, expected: ":0\r\n"},
+		{name: "NX and XX", args: []string{"geoadd-key", "NX", "XX", "13", "38", "Palermo"}, errMsg: "syntax error"},
+		{name: "incomplete triplet", args: []string{"geoadd-key", "13", "38", "Palermo", "14"}, errMsg: "syntax error"},
+		{name: "options without members", args: []string{"geoadd-key", "NX", "CH", "XX"}, errMsg: "syntax error"},
+		{name: "invalid longitude", args: []string{"geoadd-key", "181", "38", "Palermo"}, errMsg: "invalid longitude,latitude pair 181.000000,38.000000"},
+		{name: "invalid latitude", args: []string{"geoadd-key", "13", "86", "Palermo"}, errMsg: "invalid longitude,latitude pair 13.000000,86.000000"},
+		{name: "not a float", args: []string{"geoadd-key", "abc", "38", "Palermo"}, errMsg: "value is not a valid float"},
+		{name: "wrong type", args: []string{"geoadd-string", "13", "38", "Palermo"}, errMsg: "Operation against a key holding the wrong kind of value"},
+		{name: "too few arguments", args: []string{"geoadd-key", "13", "38"}, errMsg: "wrong number of arguments for 'geoadd' command"},
+	})
+
+	result, err := execute(NewGeoPosCommand(), []string{"geoadd-key", "edge2"})
+	if err != nil || result != "*1\r\n*-1\r\n" {
+		t.Errorf("Expected XX not to add edge2, got (%q, %v)", result, err)
+	}
+}
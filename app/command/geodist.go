@@ -0,0 +1,59 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/geo"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// GeoDistCommand implements the GEODIST command
+type GeoDistCommand struct {
+}
+
+// NewGeoDistCommand creates a new GEODIST command
+func NewGeoDistCommand() *GeoDistCommand {
+	return &GeoDistCommand{}
+}
+
+// Name returns the command name
+func (c *GeoDistCommand) Name() string {
+	return "GEODIST"
+}
+
+// Execute handles the GEODIST command
+func (c *GeoDistCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	if len(args) > 4 {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+	}
+
+	unit := 1.0
+	if len(args) == 4 {
+		var err error
+		if unit, err = parseGeoUnit(args[3]); err != nil {
+			return resp.Reply{}, err
+		}
+	}
+
+	var distance float64
+	found := false
+	err := client.DB.ReadZSet(args[0], func(zset *types.SortedSet, exists bool) {
+		if !exists {
+			return
+		}
+		lon1, lat1, ok1 := geoPosition(zset, args[1])
+		lon2, lat2, ok2 := geoPosition(zset, args[2])
+		if ok1 && ok2 {
+			distance = geo.Distance(lon1, lat1, lon2, lat2)
+			found = true
+		}
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	if !found {
+		return resp.Null(), nil
+	}
+	return formatDistance(distance / unit), nil
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestGeoDistCommand_Name(t *testing.T) {
+	cmd := NewGeoDistCommand()
+	if cmd.Name() != "GEODIST" {
+		t.Errorf("Expected command name to be 'GEODIST', got %s", cmd.Name())
+	}
+}
+
+func TestGeoDistCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geodist-key")
+	storeInstance.Delete("geodist-missing")
+	storeInstance.Set("geodist-string", "value", 0)
+	execute(NewGeoAddCommand(), []string{"geodist-key", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania"})
+	cmd := NewGeoDistCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "meters by default", args: []string{"geodist-key", "Palermo", "Catania"}, expected: "$11\r\n166274.1516\r\n"},
+		{name: "kilometers", args: []string{"geodist-key", "Palermo", "Catania", "km"}, expected: "$8\r\n166.2742\r\n"},
+		{name: "miles", args: []string{"geodist-key", "Palermo", "Catania", "MI"}, expected: "$8\r\n103.3182\r\n"},
+		{name: "feet", args: []string{"geodist-key", "Palermo", "Catania", "ft"}, expected: "$11\r\n545518.8700\r\n"},
+		{name: "same member", args: []string{"geodist-key", "Palermo", "Palermo"}, expected: "$6\r\n0.0000\r\n"},
+		{name: "missing member", args: []string{"geodist-key", "Palermo", "Foo"}, expected: "$-1\r\n"},
+		{name: "missing key", args: []string{"geodist-missing", "Palermo", "Catania"}, expected: "$-1\r\n"},
+		{name: "invalid unit", args: []string{"geodist-key", "Palermo", "Catania", "yd"}, errMsg: "unsupported unit provided. please use M, KM, FT, MI"},
+		{name: "too many arguments", args: []string{"geodist-key", "Palermo", "Catania", "km", "extra"}, errMsg: "syntax error"},
+		{name: "wrong type", args: []string{"geodist-string", "Palermo", "Catania"}, errMsg: "Operation against a key holding the wrong kind of value"},
+		{name: "too few arguments", args: []string{"geodist-key", "Palermo"}, errMsg: "wrong number of arguments for 'geodist' command"},
+	})
+}
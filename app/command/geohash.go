@@ -0,0 +1,42 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/geo"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// GeoHashCommand implements the GEOHASH command
+type GeoHashCommand struct {
+}
+
+// NewGeoHashCommand creates a new GEOHASH command
+func NewGeoHashCommand() *GeoHashCommand {
+	return &GeoHashCommand{}
+}
+
+// Name returns the command name
+func (c *GeoHashCommand) Name() string {
+	return "GEOHASH"
+}
+
+// Execute handles the GEOHASH command
+func (c *GeoHashCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	hashes := make([]resp.Reply, len(args)-1)
+	err := client.DB.ReadZSet(args[0], func(zset *types.SortedSet, exists bool) {
+		for i, member := range args[1:] {
+			hashes[i] = resp.Null()
+			if !exists {
+				continue
+			}
+			if score, ok := zset.Score(member); ok {
+				hashes[i] = resp.BulkString(geo.String(uint64(score)))
+			}
+		}
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Array(hashes), nil
+}
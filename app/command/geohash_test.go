@@ -0,0 +1,31 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestGeoHashCommand_Name(t *testing.T) {
+	cmd := NewGeoHashCommand()
+	if cmd.Name() != "GEOHASH" {
+		t.Errorf("Expected command name to be 'GEOHASH', got %s", cmd.Name())
+	}
+}
+
+func TestGeoHashCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geohash-key")
+	storeInstance.Delete("geohash-missing")
+	storeInstance.Set("geohash-string", "value", 0)
+	execute(NewGeoAddCommand(), []string{"geohash-key", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania"})
+	cmd := NewGeoHashCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "existing members", args: []string{"geohash-key", "Palermo", "Catania"}, expected: "*2\r\n$11\r\nsqc8b49rny0\r\n$11\r\nsqdtr74hyu0\r\n"},
+		{name: "missing member", args: []string{"geohash-key", "Palermo", "Foo"}, expected: "*2\r\n$11\r\nsqc8b49rny0\r\n$-1\r\n"},
+		{name: "missing key", args: []string{"geohash-missing", "Palermo"}, expected: "*1\r\n$-1\r\n"},
+		{name: "wrong type", args: []string{"geohash-string", "Palermo"}, errMsg: "Operation against a key holding the wrong kind of value"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'geohash' command"},
+	})
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// GeoPosCommand implements the GEOPOS command
+type GeoPosCommand struct {
+}
+
+// NewGeoPosCommand creates a new GEOPOS command
+func NewGeoPosCommand() *GeoPosCommand {
+	return &GeoPosCommand{}
+}
+
+// Name returns the command name
+func (c *GeoPosCommand) Name() string {
+	return "GEOPOS"
+}
+
+// Execute handles the GEOPOS command
+func (c *GeoPosCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	positions := make([]resp.Reply, len(args)-1)
+	err := client.DB.ReadZSet(args[0], func(zset *types.SortedSet, exists bool) {
+		for i, member := range args[1:] {
+			positions[i] = resp.NullArray()
+			if !exists {
+				continue
+			}
+			if longitude, latitude, ok := geoPosition(zset, member); ok {
+				positions[i] = formatCoordinates(longitude, latitude)
+			}
+		}
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Array(positions), nil
+}
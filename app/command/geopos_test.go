@@ -0,0 +1,38 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestGeoPosCommand_Name(t *testing.T) {
+	cmd := NewGeoPosCommand()
+	if cmd.Name() != "GEOPOS" {
+		t.Errorf("Expected command name to be 'GEOPOS', got %s", cmd.Name())
+	}
+}
+
+func TestGeoPosCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geopos-key")
+	storeInstance.Delete("geopos-missing")
+	storeInstance.Set("geopos-string", "value", 0)
+	execute(NewGeoAddCommand(), []string{"geopos-key", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania"})
+	cmd := NewGeoPosCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{
+			name: "existing and missing members",
+			args: []string{"geopos-key", "Palermo", "Catania", "NonExisting"},
+			expected: "*3\r\n" +
+				"*2\r\n$20\r\n13.36138933897018433\r\n$20\r\n38.11555639549629859\r\n" +
+				"*2\r\n$20\r\n15.08726745843887329\r\n$20\r\n37.50266842333162032\r\n" +
+				"*-1\r\n",
+		},
+		{name: "missing key", args: []string{"geopos-missing", "Palermo"}, expected: "*1\r\n*-1\r\n"},
+		{name: "no members", args: []string{"geopos-key"}, expected: "*0\r\n"},
+		{name: "wrong type", args: []string{"geopos-string", "Palermo"}, errMsg: "Operation against a key holding the wrong kind of value"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'geopos' command"},
+	})
+}
@@ -0,0 +1,431 @@
+package command
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/geo"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// geoSearchKind identifies which of the GEO search commands is being run
+type geoSearchKind int
+
+const (
+	// geoRadius is GEORADIUS, searching around a longitude and latitude
+	geoRadius geoSearchKind = iota
+	// geoRadiusByMember is GEORADIUSBYMEMBER, searching around a member
+	geoRadiusByMember
+	// geoSearch is GEOSEARCH, taking the centre and shape as options
+	geoSearch
+	// geoSearchStore is GEOSEARCHSTORE, which stores the GEOSEARCH results
+	geoSearchStore
+)
+
+// geoSortOrder is the order in which search results are returned
+type geoSortOrder int
+
+const (
+	geoSortNone geoSortOrder = iota
+	geoSortAsc
+	geoSortDesc
+)
+
+// geoSearchOptions holds the parsed arguments of a GEO search
+type geoSearchOptions struct {
+	srcKey string
+	// fromMember is the member to search around if byMember is set
+	fromMember string
+	byMember   bool
+	shape      geo.Shape
+	// unit is the number of meters in the unit used for the shape and distances
+	unit      float64
+	withDist  bool
+	withHash  bool
+	withCoord bool
+	sort      geoSortOrder
+	count     int64
+	any       bool
+	storeKey  string
+	storeDist bool
+}
+
+// geoPoint is a member found by a GEO search
+type geoPoint struct {
+	member    string
+	distance  float64
+	score     float64
+	longitude float64
+	latitude  float64
+}
+
+// GeoSearchCommand implements GEOSEARCH, GEOSEARCHSTORE and the legacy
+// GEORADIUS and GEORADIUSBYMEMBER commands, including their _RO variants
+type GeoSearchCommand struct {
+	kind     geoSearchKind
+	readOnly bool
+}
+
+// NewGeoSearchCommand creates a new GEOSEARCH command
+func NewGeoSearchCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoSearch, readOnly: true}
+}
+
+// NewGeoSearchStoreCommand creates a new GEOSEARCHSTORE command
+func NewGeoSearchStoreCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoSearchStore}
+}
+
+// NewGeoRadiusCommand creates a new GEORADIUS command
+func NewGeoRadiusCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoRadius}
+}
+
+// NewGeoRadiusRoCommand creates a new GEORADIUS_RO command, which does not accept STORE
+func NewGeoRadiusRoCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoRadius, readOnly: true}
+}
+
+// NewGeoRadiusByMemberCommand creates a new GEORADIUSBYMEMBER command
+func NewGeoRadiusByMemberCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoRadiusByMember}
+}
+
+// NewGeoRadiusByMemberRoCommand creates a new GEORADIUSBYMEMBER_RO command, which does not accept STORE
+func NewGeoRadiusByMemberRoCommand() *GeoSearchCommand {
+	return &GeoSearchCommand{kind: geoRadiusByMember, readOnly: true}
+}
+
+// Name returns the command name
+func (c *GeoSearchCommand) Name() string {
+	var name string
+	switch c.kind {
+	case geoRadius:
+		name = "GEORADIUS"
+	case geoRadiusByMember:
+		name = "GEORADIUSBYMEMBER"
+	case geoSearch:
+		return "GEOSEARCH"
+	case geoSearchStore:
+		return "GEOSEARCHSTORE"
+	}
+	if c.readOnly {
+		name += "_RO"
+	}
+	return name
+}
+
+// minArgs returns the smallest valid number of arguments
+func (c *GeoSearchCommand) minArgs() int {
+	switch c.kind {
+	case geoRadius:
+		return 5
+	case geoRadiusByMember:
+		return 4
+	case geoSearch:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// Execute handles the GEO search commands
+func (c *GeoSearchCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	opts, err := c.parse(args)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	var points []geoPoint
+	found := false
+	var searchErr error
+	err = client.DB.ReadZSet(opts.srcKey, func(zset *types.SortedSet, exists bool) {
+		if !exists {
+			return
+		}
+		found = true
+
+		if opts.byMember {
+			longitude, latitude, ok := geoPosition(zset, opts.fromMember)
+			if !ok {
+				searchErr = errors.New(errors.ErrorTypeCommand, "could not decode requested zset member")
+				return
+			}
+			opts.shape.Longitude, opts.shape.Latitude = longitude, latitude
+		}
+		points = searchGeoPoints(zset, &opts)
+	})
+	if err == nil {
+		err = searchErr
+	}
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	if !found {
+		if opts.storeKey != "" {
+			client.DB.Delete(opts.storeKey)
+			return resp.Integer(0), nil
+		}
+		return resp.Array(nil), nil
+	}
+
+	// COUNT without ANY returns the closest matches
+	if opts.count > 0 && opts.sort == geoSortNone && !opts.any {
+		opts.sort = geoSortAsc
+	}
+	switch opts.sort {
+	case geoSortAsc:
+		slices.SortStableFunc(points, func(a, b geoPoint) int { return cmp.Compare(a.distance, b.distance) })
+	case geoSortDesc:
+		slices.SortStableFunc(points, func(a, b geoPoint) int { return cmp.Compare(b.distance, a.distance) })
+	}
+	if opts.count > 0 && int64(len(points)) > opts.count {
+		points = points[:opts.count]
+	}
+
+	if opts.storeKey != "" {
+		return c.storeResults(client.DB, &opts, points), nil
+	}
+	return formatGeoPoints(&opts, points), nil
+}
+
+// parse parses the arguments of the search, except for FROMMEMBER and
+// GEORADIUSBYMEMBER members, whose positions are looked up during the search
+func (c *GeoSearchCommand) parse(args []string) (geoSearchOptions, error) {
+	opts := geoSearchOptions{unit: 1}
+	var baseArgs int
+
+	switch c.kind {
+	case geoRadius:
+		baseArgs = 5
+		opts.srcKey = args[0]
+		longitude, latitude, err := parseLonLat(args[1], args[2])
+		if err != nil {
+			return opts, err
+		}
+		opts.shape.Longitude, opts.shape.Latitude = longitude, latitude
+		if err := parseGeoRadius(args[3:5], &opts); err != nil {
+			return opts, err
+		}
+	case geoRadiusByMember:
+		baseArgs = 4
+		opts.srcKey = args[0]
+		opts.fromMember = args[1]
+		opts.byMember = true
+		if err := parseGeoRadius(args[2:4], &opts); err != nil {
+			return opts, err
+		}
+	case geoSearch:
+		baseArgs = 1
+		opts.srcKey = args[0]
+	case geoSearchStore:
+		baseArgs = 2
+		opts.storeKey = args[0]
+		opts.srcKey = args[1]
+	}
+
+	isSearch := c.kind == geoSearch || c.kind == geoSearchStore
+	var fromLonLat, byRadius, byBox bool
+	remaining := args[baseArgs:]
+	for i := 0; i < len(remaining); i++ {
+		left := len(remaining) - i - 1
+		switch arg := strings.ToUpper(remaining[i]); {
+		case arg == "WITHDIST":
+			opts.withDist = true
+		case arg == "WITHHASH":
+			opts.withHash = true
+		case arg == "WITHCOORD":
+			opts.withCoord = true
+		case arg == "ANY":
+			opts.any = true
+		case arg == "ASC":
+			opts.sort = geoSortAsc
+		case arg == "DESC":
+			opts.sort = geoSortDesc
+		case arg == "COUNT" && left >= 1:
+			count, err := parseInteger(remaining[i+1])
+			if err != nil {
+				return opts, err
+			}
+			if count <= 0 {
+				return opts, errors.New(errors.ErrorTypeCommand, "COUNT must be > 0")
+			}
+			opts.count = count
+			i++
+		case (arg == "STORE" || arg == "STOREDIST") && left >= 1 && !c.readOnly && !isSearch:
+			opts.storeKey = remaining[i+1]
+			opts.storeDist = arg == "STOREDIST"
+			i++
+		case arg == "STOREDIST" && c.kind == geoSearchStore:
+			opts.storeDist = true
+		case arg == "FROMMEMBER" && left >= 1 && isSearch && !fromLonLat:
+			opts.fromMember = remaining[i+1]
+			opts.byMember = true
+			i++
+		case arg == "FROMLONLAT" && left >= 2 && isSearch && !opts.byMember:
+			longitude, latitude, err := parseLonLat(remaining[i+1], remaining[i+2])
+			if err != nil {
+				return opts, err
+			}
+			opts.shape.Longitude, opts.shape.Latitude = longitude, latitude
+			fromLonLat = true
+			i += 2
+		case arg == "BYRADIUS" && left >= 2 && isSearch && !byBox:
+			if err := parseGeoRadius(remaining[i+1:i+3], &opts); err != nil {
+				return opts, err
+			}
+			byRadius = true
+			i += 2
+		case arg == "BYBOX" && left >= 3 && isSearch && !byRadius:
+			if err := parseGeoBox(remaining[i+1:i+4], &opts); err != nil {
+				return opts, err
+			}
+			byBox = true
+			i += 3
+		default:
+			return opts, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	}
+
+	if opts.storeKey != "" && (opts.withDist || opts.withHash || opts.withCoord) {
+		option := "STORE option in GEORADIUS"
+		if c.kind == geoSearchStore {
+			option = "GEOSEARCHSTORE"
+		}
+		return opts, errors.New(errors.ErrorTypeCommand, option+" is not compatible with WITHDIST, WITHHASH and WITHCOORD options")
+	}
+	if isSearch && !opts.byMember && !fromLonLat {
+		return opts, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("exactly one of FROMMEMBER or FROMLONLAT can be specified for %s", strings.ToLower(c.Name())))
+	}
+	if isSearch && !byRadius && !byBox {
+		return opts, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("exactly one of BYRADIUS and BYBOX can be specified for %s", strings.ToLower(c.Name())))
+	}
+	if opts.any && opts.count == 0 {
+		return opts, errors.New(errors.ErrorTypeCommand, "the ANY argument requires COUNT argument")
+	}
+
+	return opts, nil
+}
+
+// parseGeoRadius parses a radius and unit into a circular search shape
+func parseGeoRadius(args []string, opts *geoSearchOptions) error {
+	radius, err := parseFloat(args[0], "need numeric radius")
+	if err != nil {
+		return err
+	}
+	if radius < 0 {
+		return errors.New(errors.ErrorTypeCommand, "radius cannot be negative")
+	}
+	if opts.unit, err = parseGeoUnit(args[1]); err != nil {
+		return err
+	}
+
+	opts.shape.Radius = radius * opts.unit
+	opts.shape.IsBox = false
+	return nil
+}
+
+// parseGeoBox parses a width, height and unit into a box search shape
+func parseGeoBox(args []string, opts *geoSearchOptions) error {
+	width, err := parseFloat(args[0], "need numeric width")
+	if err != nil {
+		return err
+	}
+	height, err := parseFloat(args[1], "need numeric height")
+	if err != nil {
+		return err
+	}
+	if width < 0 || height < 0 {
+		return errors.New(errors.ErrorTypeCommand, "height or width cannot be negative")
+	}
+	if opts.unit, err = parseGeoUnit(args[2]); err != nil {
+		return err
+	}
+
+	opts.shape.Width, opts.shape.Height = width*opts.unit, height*opts.unit
+	opts.shape.IsBox = true
+	return nil
+}
+
+// searchGeoPoints returns the members of zset within the search shape. When
+// ANY is given, the search stops as soon as COUNT members have been found.
+func searchGeoPoints(zset *types.SortedSet, opts *geoSearchOptions) []geoPoint {
+	var points []geoPoint
+	for _, r := range opts.shape.ScoreRanges() {
+		scores := types.ScoreRange{Min: float64(r[0]), Max: float64(r[1]), MaxExclusive: true}
+		zset.RangeByScore(scores, func(member string, score float64) bool {
+			longitude, latitude := geo.Decode(uint64(score))
+			if distance, ok := opts.shape.Contains(longitude, latitude); ok {
+				points = append(points, geoPoint{
+					member:    member,
+					distance:  distance,
+					score:     score,
+					longitude: longitude,
+					latitude:  latitude,
+				})
+			}
+			return !opts.any || int64(len(points)) < opts.count
+		})
+		if opts.any && int64(len(points)) >= opts.count {
+			break
+		}
+	}
+	return points
+}
+
+// formatGeoPoints formats the search results, as plain members or, if any
+// WITH option was given, as arrays of the member followed by its distance,
+// hash and coordinates
+func formatGeoPoints(opts *geoSearchOptions, points []geoPoint) resp.Reply {
+	withAny := opts.withDist || opts.withHash || opts.withCoord
+
+	results := make([]resp.Reply, len(points))
+	for i, point := range points {
+		member := resp.BulkString(point.member)
+		if !withAny {
+			results[i] = member
+			continue
+		}
+
+		fields := []resp.Reply{member}
+		if opts.withDist {
+			fields = append(fields, formatDistance(point.distance/opts.unit))
+		}
+		if opts.withHash {
+			fields = append(fields, resp.Integer(int64(point.score)))
+		}
+		if opts.withCoord {
+			fields = append(fields, formatCoordinates(point.longitude, point.latitude))
+		}
+		results[i] = resp.Array(fields)
+	}
+	return resp.Array(results)
+}
+
+// storeResults stores the search results in the destination sorted set,
+// scored by geohash or, with STOREDIST, by distance. The destination is
+// deleted if there are no results.
+func (c *GeoSearchCommand) storeResults(db *store.Store, opts *geoSearchOptions, points []geoPoint) resp.Reply {
+	if len(points) == 0 {
+		db.Delete(opts.storeKey)
+		return resp.Integer(0)
+	}
+
+	zset := types.NewSortedSet()
+	for _, point := range points {
+		score := point.score
+		if opts.storeDist {
+			score = point.distance / opts.unit
+		}
+		zset.Add(point.member, score)
+	}
+	db.SetZSet(opts.storeKey, zset)
+
+	return resp.Integer(int64(len(points)))
+}
@@ -0,0 +1,192 @@
+package command
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// setupGeoSearchKey stores the cities used by the GEO search examples at key
+func setupGeoSearchKey(t *testing.T, key string) {
+	t.Helper()
+
+	storeInstance := store.GetStore()
+	storeInstance.Delete(key)
+	_, err := execute(NewGeoAddCommand(), []string{key,
+		"13.361389", "38.115556", "Palermo",
+		"15.087269", "37.502669", "Catania",
+		"12.758489", "38.788135", "edge1",
+		"17.241510", "38.788135", "edge2",
+	})
+	if err != nil {
+		t.Fatalf("Failed to set up %s: %v", key, err)
+	}
+}
+
+func TestGeoSearchCommand_Name(t *testing.T) {
+	tests := []struct {
+		cmd      *GeoSearchCommand
+		expected string
+	}{
+		{NewGeoSearchCommand(), "GEOSEARCH"},
+		{NewGeoSearchStoreCommand(), "GEOSEARCHSTORE"},
+		{NewGeoRadiusCommand(), "GEORADIUS"},
+		{NewGeoRadiusRoCommand(), "GEORADIUS_RO"},
+		{NewGeoRadiusByMemberCommand(), "GEORADIUSBYMEMBER"},
+		{NewGeoRadiusByMemberRoCommand(), "GEORADIUSBYMEMBER_RO"},
+	}
+
+	for _, tt := range tests {
+		if tt.cmd.Name() != tt.expected {
+			t.Errorf("Expected command name to be '%s', got %s", tt.expected, tt.cmd.Name())
+		}
+	}
+}
+
+func TestGeoSearchCommand_Execute(t *testing.T) {
+	setupGeoSearchKey(t, "geosearch-key")
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geosearch-missing")
+	storeInstance.Set("geosearch-string", "value", 0)
+	cmd := NewGeoSearchCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{
+			name:     "by radius ascending",
+			args:     []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "ASC"},
+			expected: "*2\r\n$7\r\nCatania\r\n$7\r\nPalermo\r\n",
+		},
+		{
+			name:     "by radius descending",
+			args:     []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "DESC"},
+			expected: "*2\r\n$7\r\nPalermo\r\n$7\r\nCatania\r\n",
+		},
+		{
+			name: "by box with coordinates and distances",
+			args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "ASC", "WITHCOORD", "WITHDIST"},
+			expected: "*4\r\n" +
+				"*3\r\n$7\r\nCatania\r\n$7\r\n56.4413\r\n*2\r\n$20\r\n15.08726745843887329\r\n$20\r\n37.50266842333162032\r\n" +
+				"*3\r\n$7\r\nPalermo\r\n$8\r\n190.4424\r\n*2\r\n$20\r\n13.36138933897018433\r\n$20\r\n38.11555639549629859\r\n" +
+				"*3\r\n$5\r\nedge2\r\n$8\r\n279.7403\r\n*2\r\n$20\r\n17.24151045083999634\r\n$20\r\n38.78813451624225195\r\n" +
+				"*3\r\n$5\r\nedge1\r\n$8\r\n279.7405\r\n*2\r\n$19\r\n12.7584877610206604\r\n$20\r\n38.78813451624225195\r\n",
+		},
+		{
+			name:     "from member with hash",
+			args:     []string{"geosearch-key", "FROMMEMBER", "Palermo", "BYRADIUS", "50", "km", "WITHHASH"},
+			expected: "*1\r\n*2\r\n$7\r\nPalermo\r\n:3479099956230698\r\n",
+		},
+		{
+			name:     "count returns the closest",
+			args:     []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "COUNT", "1"},
+			expected: "*1\r\n$7\r\nCatania\r\n",
+		},
+		{
+			name:     "count any",
+			args:     []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "COUNT", "2", "ANY"},
+			expected: "*2\r\n$7\r\nPalermo\r\n$5\r\nedge1\r\n",
+		},
+		{name: "no matches", args: []string{"geosearch-key", "FROMLONLAT", "0", "0", "BYRADIUS", "1", "km"}, expected: "*0\r\n"},
+		{name: "missing key", args: []string{"geosearch-missing", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km"}, expected: "*0\r\n"},
+		{name: "missing member", args: []string{"geosearch-key", "FROMMEMBER", "Foo", "BYRADIUS", "200", "km"}, errMsg: "could not decode requested zset member"},
+		{name: "without center", args: []string{"geosearch-key", "BYRADIUS", "200", "km", "ASC", "WITHDIST"}, errMsg: "exactly one of FROMMEMBER or FROMLONLAT can be specified for geosearch"},
+		{name: "without shape", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "ASC", "WITHDIST"}, errMsg: "exactly one of BYRADIUS and BYBOX can be specified for geosearch"},
+		{name: "both centers", args: []string{"geosearch-key", "FROMMEMBER", "Palermo", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km"}, errMsg: "syntax error"},
+		{name: "both shapes", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "BYBOX", "1", "1", "km"}, errMsg: "syntax error"},
+		{name: "store not accepted", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "STORE", "dest"}, errMsg: "syntax error"},
+		{name: "negative radius", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "-1", "km"}, errMsg: "radius cannot be negative"},
+		{name: "negative width", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYBOX", "-1", "1", "km"}, errMsg: "height or width cannot be negative"},
+		{name: "non numeric radius", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "abc", "km"}, errMsg: "need numeric radius"},
+		{name: "invalid unit", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "yd"}, errMsg: "unsupported unit provided. please use M, KM, FT, MI"},
+		{name: "zero count", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "km", "COUNT", "0"}, errMsg: "COUNT must be > 0"},
+		{name: "any without count", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "km", "ANY"}, errMsg: "the ANY argument requires COUNT argument"},
+		{name: "wrong type", args: []string{"geosearch-string", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "km"}, errMsg: "Operation against a key holding the wrong kind of value"},
+		{name: "too few arguments", args: []string{"geosearch-key", "FROMLONLAT", "15", "37", "BYRADIUS"}, errMsg: "wrong number of arguments for 'geosearch' command"},
+	})
+}
+
+func TestGeoSearchStoreCommand_Execute(t *testing.T) {
+	setupGeoSearchKey(t, "geosearchstore-src")
+	storeInstance := store.GetStore()
+	storeInstance.Delete("geosearchstore-missing")
+	storeInstance.Set("geosearchstore-dest", "value", 0)
+	cmd := NewGeoSearchStoreCommand()
+	search := []string{"geosearchstore-src", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "ASC", "COUNT", "3"}
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "store hashes", args: append([]string{"geosearchstore-dest"}, search...), expected: ":3\r\n"},
+		{name: "store distances", args: append([]string{"geosearchstore-dist"}, append(search, "STOREDIST")...), expected: ":3\r\n"},
+		{name: "with options", args: append([]string{"geosearchstore-dest"}, append(search, "WITHDIST")...), errMsg: "GEOSEARCHSTORE is not compatible with WITHDIST, WITHHASH and WITHCOORD options"},
+	})
+
+	result, err := execute(NewGeoPosCommand(), []string{"geosearchstore-dest", "Catania", "edge1"})
+	if err != nil || result != "*2\r\n*2\r\n$20\r\n15.08726745843887329\r\n$20\r\n37.50266842333162032\r\n*-1\r\n" {
+		t.Errorf("Expected the three closest members to be stored, got (%q, %v)", result, err)
+	}
+
+	var score float64
+	err = storeInstance.ReadZSet("geosearchstore-dist", func(zset *types.SortedSet, exists bool) {
+		if exists {
+			score, _ = zset.Score("Catania")
+		}
+	})
+	if err != nil || math.Abs(score-56.4413) > 0.0001 {
+		t.Errorf("Expected distances to be stored as scores, got (%v, %v)", score, err)
+	}
+
+	// Searching a missing key deletes the destination
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "missing source", args: []string{"geosearchstore-dest", "geosearchstore-missing", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km"}, expected: ":0\r\n"},
+	})
+	if _, err := storeInstance.Get("geosearchstore-dest"); err == nil {
+		t.Errorf("Expected the destination to be deleted")
+	}
+}
+
+func TestGeoRadiusCommand_Execute(t *testing.T) {
+	setupGeoSearchKey(t, "georadius-key")
+	storeInstance := store.GetStore()
+	storeInstance.Delete("georadius-dest")
+	cmd := NewGeoRadiusCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{
+			name:     "with distances",
+			args:     []string{"georadius-key", "15", "37", "200", "km", "WITHDIST"},
+			expected: "*2\r\n*2\r\n$7\r\nPalermo\r\n$8\r\n190.4424\r\n*2\r\n$7\r\nCatania\r\n$7\r\n56.4413\r\n",
+		},
+		{
+			name:     "count and sort",
+			args:     []string{"georadius-key", "15", "37", "300", "km", "COUNT", "2", "DESC"},
+			expected: "*2\r\n$5\r\nedge1\r\n$5\r\nedge2\r\n",
+		},
+		{name: "store", args: []string{"georadius-key", "15", "37", "200", "km", "STORE", "georadius-dest"}, expected: ":2\r\n"},
+		{name: "store with options", args: []string{"georadius-key", "15", "37", "200", "km", "WITHDIST", "STORE", "georadius-dest"}, errMsg: "STORE option in GEORADIUS is not compatible with WITHDIST, WITHHASH and WITHCOORD options"},
+		{name: "search options", args: []string{"georadius-key", "15", "37", "200", "km", "BYBOX", "1", "1", "km"}, errMsg: "syntax error"},
+		{name: "invalid center", args: []string{"georadius-key", "200", "37", "200", "km"}, errMsg: "invalid longitude,latitude pair 200.000000,37.000000"},
+		{name: "too few arguments", args: []string{"georadius-key", "15", "37", "200"}, errMsg: "wrong number of arguments for 'georadius' command"},
+	})
+
+	runCommandTests(t, NewGeoRadiusRoCommand(), []commandTestCase{
+		{name: "read only", args: []string{"georadius-key", "15", "37", "200", "km", "ASC"}, expected: "*2\r\n$7\r\nCatania\r\n$7\r\nPalermo\r\n"},
+		{name: "read only store", args: []string{"georadius-key", "15", "37", "200", "km", "STORE", "georadius-dest"}, errMsg: "syntax error"},
+	})
+}
+
+func TestGeoRadiusByMemberCommand_Execute(t *testing.T) {
+	setupGeoSearchKey(t, "georadiusbymember-key")
+	execute(NewGeoAddCommand(), []string{"georadiusbymember-key", "13.583333", "37.316667", "Agrigento"})
+	cmd := NewGeoRadiusByMemberCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "around member", args: []string{"georadiusbymember-key", "Agrigento", "100", "km"}, expected: "*2\r\n$9\r\nAgrigento\r\n$7\r\nPalermo\r\n"},
+		{name: "missing member", args: []string{"georadiusbymember-key", "Foo", "100", "km"}, errMsg: "could not decode requested zset member"},
+		{name: "too few arguments", args: []string{"georadiusbymember-key", "Agrigento", "100"}, errMsg: "wrong number of arguments for 'georadiusbymember' command"},
+	})
+
+	runCommandTests(t, NewGeoRadiusByMemberRoCommand(), []commandTestCase{
+		{name: "read only", args: []string{"georadiusbymember-key", "Agrigento", "100", "km", "COUNT", "1"}, expected: "*1\r\n$9\r\nAgrigento\r\n"},
+		{name: "read only store", args: []string{"georadiusbymember-key", "Agrigento", "100", "km", "STOREDIST", "dest"}, errMsg: "syntax error"},
+	})
+}
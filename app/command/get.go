@@ -8,12 +8,11 @@ import (
 
 // GetCommand implements the GET command
 type GetCommand struct {
-	store *store.Store
 }
 
 // NewGetCommand creates a new GET command
-func NewGetCommand(s *store.Store) *GetCommand {
-	return &GetCommand{store: s}
+func NewGetCommand() *GetCommand {
+	return &GetCommand{}
 }
 
 // Name returns the command name
@@ -22,16 +21,15 @@ func (c *GetCommand) Name() string {
 }
 
 // Execute handles the GET command
-func (c *GetCommand) Execute(args []string) (string, error) {
-	if len(args) != 1 {
-		return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'get' command")
+func (c *GetCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	value, err := client.DB.Get(args[0])
+	if errors.Is(err, store.ErrWrongType) {
+		return resp.Reply{}, err
 	}
-
-	value, err := c.store.Get(args[0])
 	if err != nil {
 		// Return nil bulk string for non-existent keys
-		return resp.FormatBulkString("", true), nil
+		return resp.Null(), nil
 	}
 
-	return resp.FormatBulkString(value, false), nil
+	return resp.BulkString(value), nil
 }
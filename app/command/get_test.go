@@ -9,7 +9,7 @@ import (
 )
 
 func TestGetCommand_Name(t *testing.T) {
-	cmd := NewGetCommand(store.GetStore())
+	cmd := NewGetCommand()
 	if cmd.Name() != "GET" {
 		t.Errorf("Expected command name to be 'GET', got %s", cmd.Name())
 	}
@@ -17,7 +17,7 @@ func TestGetCommand_Name(t *testing.T) {
 
 func TestGetCommand_Execute(t *testing.T) {
 	storeInstance := store.GetStore()
-	cmd := NewGetCommand(storeInstance)
+	cmd := NewGetCommand()
 
 	// Set up some test data
 	storeInstance.Set("testkey", "testvalue", 0)
@@ -73,7 +73,7 @@ func TestGetCommand_Execute(t *testing.T) {
 				tt.setup()
 			}
 
-			result, err := cmd.Execute(tt.args)
+			result, err := execute(cmd, tt.args)
 
 			// Check error
 			if tt.errMsg != "" {
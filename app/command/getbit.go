@@ -0,0 +1,37 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// GetBitCommand implements the GETBIT command
+type GetBitCommand struct {
+}
+
+// NewGetBitCommand creates a new GETBIT command
+func NewGetBitCommand() *GetBitCommand {
+	return &GetBitCommand{}
+}
+
+// Name returns the command name
+func (c *GetBitCommand) Name() string {
+	return "GETBIT"
+}
+
+// Execute handles the GETBIT command
+func (c *GetBitCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	offset, err := parseBitOffset(args[1], false, 1)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	var bit byte
+	err = client.DB.ReadString(args[0], func(value []byte, exists bool) {
+		bit = getBit(value, offset)
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(int64(bit)), nil
+}
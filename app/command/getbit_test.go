@@ -0,0 +1,32 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestGetBitCommand_Name(t *testing.T) {
+	cmd := NewGetBitCommand()
+	if cmd.Name() != "GETBIT" {
+		t.Errorf("Expected command name to be 'GETBIT', got %s", cmd.Name())
+	}
+}
+
+func TestGetBitCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	cmd := NewGetBitCommand()
+
+	// "a" is 0x61 = 01100001
+	storeInstance.Set("getbit-key", "a", 0)
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "set bit", args: []string{"getbit-key", "1"}, expected: ":1\r\n"},
+		{name: "clear bit", args: []string{"getbit-key", "0"}, expected: ":0\r\n"},
+		{name: "last bit of byte", args: []string{"getbit-key", "7"}, expected: ":1\r\n"},
+		{name: "offset past end", args: []string{"getbit-key", "1000"}, expected: ":0\r\n"},
+		{name: "missing key", args: []string{"getbit-missing", "3"}, expected: ":0\r\n"},
+		{name: "invalid offset", args: []string{"getbit-key", "abc"}, errMsg: "bit offset is not an integer or out of range"},
+		{name: "wrong number of arguments", args: []string{"getbit-key"}, errMsg: "wrong number of arguments for 'getbit' command"},
+	})
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// RedisVersion is the version of Redis the server reports to clients
+const RedisVersion = "7.4.0"
+
+// HelloCommand implements the HELLO command
+type HelloCommand struct {
+}
+
+// NewHelloCommand creates a new HELLO command
+func NewHelloCommand() *HelloCommand {
+	return &HelloCommand{}
+}
+
+// Name returns the command name
+func (c *HelloCommand) Name() string {
+	return "HELLO"
+}
+
+// Execute handles the HELLO command. It switches the connection to the
+// requested protocol version and replies with the server's properties,
+// encoded with the new version.
+func (c *HelloCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	protocol := client.Protocol
+	name, setName := "", false
+	auth, user, password := false, "", ""
+
+	if len(args) > 0 {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "Protocol version is not an integer or out of range")
+		}
+		if version < resp.RESP2 || version > resp.RESP3 {
+			return resp.Reply{}, errors.NewWithCode("NOPROTO", "unsupported protocol version")
+		}
+		protocol = int(version)
+
+		for i := 1; i < len(args); i++ {
+			remaining := len(args) - i - 1
+			switch {
+			case strings.EqualFold(args[i], "AUTH") && remaining >= 2:
+				auth, user, password = true, args[i+1], args[i+2]
+				i += 2
+			case strings.EqualFold(args[i], "SETNAME") && remaining >= 1:
+				if err := validateClientName(args[i+1]); err != nil {
+					return resp.Reply{}, err
+				}
+				name, setName = args[i+1], true
+				i++
+			default:
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("Syntax error in HELLO option '%s'", args[i]))
+			}
+		}
+	}
+
+	if auth {
+		if err := authenticate(client, user, password); err != nil {
+			return resp.Reply{}, err
+		}
+	} else if !client.authenticated {
+		return resp.Reply{}, errors.NewWithCode("NOAUTH", "HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+	}
+
+	client.SetProtocol(protocol)
+	if setName {
+		client.SetName(name)
+	}
+
+	return resp.Map([]resp.Reply{
+		resp.BulkString("server"), resp.BulkString("redis"),
+		resp.BulkString("version"), resp.BulkString(RedisVersion),
+		resp.BulkString("proto"), resp.Integer(int64(protocol)),
+		resp.BulkString("id"), resp.Integer(client.ID),
+		resp.BulkString("mode"), resp.BulkString("standalone"),
+		resp.BulkString("role"), resp.BulkString("master"),
+		resp.BulkString("modules"), resp.Array(nil),
+	}), nil
+}
+
+// validateClientName checks that a connection name only contains printable
+// characters other than spaces
+func validateClientName(name string) error {
+	for i := 0; i < len(name); i++ {
+		if name[i] < '!' || name[i] > '~' {
+			return errors.New(errors.ErrorTypeCommand, "Client names cannot contain spaces, newlines or special characters.")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+func TestHelloCommand_Name(t *testing.T) {
+	cmd := NewHelloCommand()
+	if cmd.Name() != "HELLO" {
+		t.Errorf("Expected command name to be 'HELLO', got %s", cmd.Name())
+	}
+}
+
+func TestHelloCommand_Execute(t *testing.T) {
+	cmd := NewHelloCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "not an integer", args: []string{"x"}, errMsg: "Protocol version is not an integer or out of range"},
+		{name: "unsupported version", args: []string{"4"}, errMsg: "unsupported protocol version"},
+		{name: "unknown user", args: []string{"3", "AUTH", "alice", "secret"}, errMsg: "invalid username-password pair or user is disabled."},
+		{name: "invalid name", args: []string{"3", "SETNAME", "my name"}, errMsg: "Client names cannot contain spaces, newlines or special characters."},
+		{name: "auth without password", args: []string{"3", "AUTH", "default"}, errMsg: "Syntax error in HELLO option 'AUTH'"},
+		{name: "unknown option", args: []string{"3", "FOO"}, errMsg: "Syntax error in HELLO option 'FOO'"},
+	})
+}
+
+func TestHelloCommand_SwitchesProtocol(t *testing.T) {
+	cmd := NewHelloCommand()
+	client := NewClient(io.Discard)
+
+	reply, err := cmd.Execute(client, []string{"3", "AUTH", "default", "any", "SETNAME", "my-app"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.Protocol != resp.RESP3 || client.Name != "my-app" || client.User != DefaultUser {
+		t.Errorf("Expected RESP3, name 'my-app' and user 'default', got %d, %q and %q", client.Protocol, client.Name, client.User)
+	}
+
+	id := fmt.Sprint(client.ID)
+	expected := "%7\r\n$6\r\nserver\r\n$5\r\nredis\r\n$7\r\nversion\r\n$5\r\n" + RedisVersion + "\r\n$5\r\nproto\r\n:3\r\n" +
+		"$2\r\nid\r\n:" + id + "\r\n$4\r\nmode\r\n$10\r\nstandalone\r\n$4\r\nrole\r\n$6\r\nmaster\r\n$7\r\nmodules\r\n*0\r\n"
+	if result := encodeReply(reply, client.Protocol); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Without arguments HELLO keeps the current protocol
+	if _, err := cmd.Execute(client, nil); err != nil || client.Protocol != resp.RESP3 {
+		t.Errorf("Expected HELLO without arguments to keep RESP3, got %d (err: %v)", client.Protocol, err)
+	}
+	if _, err := cmd.Execute(client, []string{"2"}); err != nil || client.Protocol != resp.RESP2 {
+		t.Errorf("Expected HELLO 2 to switch back to RESP2, got %d (err: %v)", client.Protocol, err)
+	}
+
+	// A failed HELLO leaves the connection untouched
+	if _, err := cmd.Execute(client, []string{"3", "SETNAME", "bad name"}); err == nil || client.Protocol != resp.RESP2 || client.Name != "my-app" {
+		t.Errorf("Expected a failed HELLO not to change the client, got %d and %q", client.Protocol, client.Name)
+	}
+}
+
+func TestHelloCommand_RequirePass(t *testing.T) {
+	config.SetConfig("requirepass", "secret")
+	defer config.SetConfig("requirepass", "")
+
+	cmd := NewHelloCommand()
+	client := NewClient(io.Discard)
+
+	if _, err := cmd.Execute(client, []string{"3"}); err == nil || !strings.HasPrefix(err.Error(), "HELLO must be called with the client already authenticated") {
+		t.Errorf("Expected HELLO without AUTH to be refused, got %v", err)
+	}
+	if _, err := cmd.Execute(client, []string{"3", "AUTH", "default", "wrong"}); err == nil || client.Authenticated() {
+		t.Errorf("Expected HELLO with the wrong password to fail, got %v", err)
+	}
+	if _, err := cmd.Execute(client, []string{"3", "AUTH", "default", "secret"}); err != nil || !client.Authenticated() || client.Protocol != resp.RESP3 {
+		t.Errorf("Expected HELLO AUTH to authenticate and switch to RESP3, got %v", err)
+	}
+}
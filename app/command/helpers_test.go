@@ -0,0 +1,97 @@
+package command
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// commandTestCase describes a single command invocation and its expected outcome
+type commandTestCase struct {
+	name     string
+	args     []string
+	expected string
+	errMsg   string
+}
+
+// runCommandTests executes the test cases against cmd in order, so later cases
+// may depend on state written by earlier ones
+func runCommandTests(t *testing.T, cmd Command, tests []commandTestCase) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := execute(cmd, tt.args)
+
+			// Check error
+			if tt.errMsg != "" {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+					return
+				}
+				cmdErr, ok := err.(*errors.Error)
+				if !ok {
+					t.Errorf("Expected errors.Error type, got %T", err)
+					return
+				}
+				if cmdErr.Error() != tt.errMsg {
+					t.Errorf("Expected error message %q, got %q", tt.errMsg, cmdErr.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+
+			// Check result
+			if result != tt.expected {
+				t.Errorf("Expected result %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// execute runs cmd for a new client and returns its reply encoded as RESP2.
+// The number of arguments is checked against the command table first, as the
+// registry does.
+func execute(cmd Command, args []string) (string, error) {
+	entry := &registeredCommand{cmd: cmd, meta: defaultMetadata}
+	if meta, ok := commandTable[cmd.Name()]; ok {
+		entry.meta = meta
+	}
+	subcommand, meta := entry.meta.subcommand(args)
+	reply, err := chain([]Middleware{checkArity}, runCommand)(&Invocation{
+		Client:     NewClient(io.Discard),
+		Name:       cmd.Name(),
+		Subcommand: subcommand,
+		Args:       args,
+		Metadata:   meta,
+		command:    entry,
+	})
+	if err != nil {
+		return "", err
+	}
+	return encodeRESP2(reply), nil
+}
+
+// encodeRESP2 encodes a reply the way it is sent to RESP2 clients
+func encodeRESP2(reply resp.Reply) string {
+	return encodeReply(reply, resp.RESP2)
+}
+
+// encodeReply encodes a reply with the given protocol version
+func encodeReply(reply resp.Reply, protocol int) string {
+	var b strings.Builder
+	w := bufio.NewWriter(&b)
+	e := resp.NewEncoder(w)
+	e.SetProtocol(protocol)
+	e.Encode(reply)
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// InfoCommand implements the INFO command. Only the keyspace section is
+// reported so far.
+type InfoCommand struct {
+	dbs *store.Databases
+}
+
+// NewInfoCommand creates a new INFO command
+func NewInfoCommand(dbs *store.Databases) *InfoCommand {
+	return &InfoCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *InfoCommand) Name() string {
+	return "INFO"
+}
+
+// Execute handles the INFO command
+func (c *InfoCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	sections := make([]string, len(args))
+	for i, arg := range args {
+		sections[i] = strings.ToLower(arg)
+	}
+
+	var b strings.Builder
+	if len(sections) == 0 || slices.ContainsFunc(sections, func(section string) bool {
+		return section == "keyspace" || section == "default" || section == "all" || section == "everything"
+	}) {
+		c.writeKeyspace(&b)
+	}
+
+	return resp.Verbatim("txt", b.String()), nil
+}
+
+// writeKeyspace writes the keyspace section, with a line for every database
+// holding keys
+func (c *InfoCommand) writeKeyspace(b *strings.Builder) {
+	b.WriteString("# Keyspace\r\n")
+	for i := range c.dbs.Count() {
+		db, _ := c.dbs.DB(int64(i))
+		keys, expires, avgTTL := db.KeyspaceStats()
+		if keys == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "db%d:keys=%d,expires=%d,avg_ttl=%d\r\n", i, keys, expires, avgTTL.Milliseconds())
+	}
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestInfoCommand_Name(t *testing.T) {
+	cmd := NewInfoCommand(store.GetDatabases())
+	if cmd.Name() != "INFO" {
+		t.Errorf("Expected command name to be 'INFO', got %s", cmd.Name())
+	}
+}
+
+func TestInfoCommand_Execute(t *testing.T) {
+	dbs := store.GetDatabases()
+	dbs.FlushAll(false)
+	store.GetStore().Set("info-key", "value", 0)
+	db2, _ := dbs.DB(2)
+	db2.Set("info-key1", "value", 0)
+	db2.Set("info-key2", "value", time.Hour)
+	cmd := NewInfoCommand(dbs)
+
+	keyspace := "# Keyspace\r\ndb0:keys=1,expires=0,avg_ttl=0\r\n"
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "unknown section", args: []string{"foo"}, expected: "$0\r\n\r\n"},
+	})
+
+	for _, args := range [][]string{{}, {"KEYSPACE"}, {"all"}} {
+		result, err := execute(cmd, args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// The average TTL of database 2 varies, so only the start of its line is compared
+		body := result[strings.Index(result, "\r\n")+2:]
+		prefix := keyspace + "db2:keys=2,expires=1,avg_ttl="
+		if !strings.HasPrefix(body, prefix) {
+			t.Errorf("INFO %v: expected the keyspace section to start with %q, got %q", args, prefix, body)
+		}
+	}
+
+	// RESP3 clients receive the report as a verbatim text string
+	reply, err := cmd.Execute(NewClient(io.Discard), []string{"keyspace"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := encodeReply(reply, resp.RESP3); !strings.HasPrefix(result, "=") || !strings.Contains(result, "txt:# Keyspace") {
+		t.Errorf("Expected a verbatim string, got %q", result)
+	}
+	dbs.FlushAll(false)
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// KeysCommand implements the KEYS command
+type KeysCommand struct {
+}
+
+// NewKeysCommand creates a new KEYS command
+func NewKeysCommand() *KeysCommand {
+	return &KeysCommand{}
+}
+
+// Name returns the command name
+func (c *KeysCommand) Name() string {
+	return "KEYS"
+}
+
+// Execute handles the KEYS command
+func (c *KeysCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	keys := client.DB.Keys(args[0])
+	return resp.BulkStrings(keys), nil
+}
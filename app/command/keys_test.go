@@ -0,0 +1,30 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestKeysCommand_Name(t *testing.T) {
+	cmd := NewKeysCommand()
+	if cmd.Name() != "KEYS" {
+		t.Errorf("Expected command name to be 'KEYS', got %s", cmd.Name())
+	}
+}
+
+func TestKeysCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Flush(false)
+	storeInstance.Set("keys-hello", "value", 0)
+	storeInstance.Set("keys-world", "value", 0)
+	cmd := NewKeysCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "single match", args: []string{"keys-h?llo"}, expected: "*1\r\n$10\r\nkeys-hello\r\n"},
+		{name: "character class", args: []string{"keys-[w]*"}, expected: "*1\r\n$10\r\nkeys-world\r\n"},
+		{name: "negated class", args: []string{"keys-[^h]orld"}, expected: "*1\r\n$10\r\nkeys-world\r\n"},
+		{name: "no matches", args: []string{"missing*"}, expected: "*0\r\n"},
+		{name: "wrong number of arguments", args: []string{}, errMsg: "wrong number of arguments for 'keys' command"},
+	})
+}
@@ -0,0 +1,276 @@
+package command
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+)
+
+// CommandFlags is a set of flags describing how a command behaves, as shown by
+// COMMAND INFO
+type CommandFlags uint64
+
+const (
+	// FlagWrite marks commands that may modify the dataset
+	FlagWrite CommandFlags = 1 << iota
+	// FlagReadOnly marks commands that only read the dataset
+	FlagReadOnly
+	// FlagDenyOOM marks commands that may use more memory
+	FlagDenyOOM
+	// FlagAdmin marks administrative commands
+	FlagAdmin
+	// FlagPubSub marks publish/subscribe commands
+	FlagPubSub
+	// FlagNoScript marks commands that can't be run from scripts
+	FlagNoScript
+	// FlagLoading marks commands allowed while the dataset is loading
+	FlagLoading
+	// FlagStale marks commands allowed while a replica has stale data
+	FlagStale
+	// FlagFast marks commands that run in constant or logarithmic time
+	FlagFast
+	// FlagNoAuth marks commands that may be run before authenticating
+	FlagNoAuth
+	// FlagMayReplicate marks commands that don't write but may propagate a
+	// write, such as PFCOUNT, so are held back by CLIENT PAUSE WRITE
+	FlagMayReplicate
+)
+
+// flagNames are the names of the flags, in the order COMMAND INFO shows them
+var flagNames = []struct {
+	flag CommandFlags
+	name string
+}{
+	{FlagWrite, "write"},
+	{FlagReadOnly, "readonly"},
+	{FlagDenyOOM, "denyoom"},
+	{FlagAdmin, "admin"},
+	{FlagPubSub, "pubsub"},
+	{FlagNoScript, "noscript"},
+	{FlagLoading, "loading"},
+	{FlagStale, "stale"},
+	{FlagFast, "fast"},
+	{FlagNoAuth, "no_auth"},
+	{FlagMayReplicate, "may_replicate"},
+}
+
+// Names returns the names of the flags that are set
+func (f CommandFlags) Names() []string {
+	var names []string
+	for _, fn := range flagNames {
+		if f&fn.flag != 0 {
+			names = append(names, fn.name)
+		}
+	}
+	return names
+}
+
+// Key spec flags, as in Redis. RW, RO, OW and RM say how the key is used;
+// ACCESS means the value is returned or used, and INSERT, UPDATE and DELETE
+// mean the key is written to. Only the latter four decide the ACL
+// permissions needed to the key.
+const (
+	KeyRW     = "RW"
+	KeyRO     = "RO"
+	KeyOW     = "OW"
+	KeyRM     = "RM"
+	KeyAccess = "access"
+	KeyInsert = "insert"
+	KeyUpdate = "update"
+	KeyDelete = "delete"
+)
+
+// KeySpec locates keys among the arguments of a command, like a Redis key
+// spec. The first key is at Index, or right after Keyword when one is set,
+// in which case the keyword is searched for from Index on.
+type KeySpec struct {
+	// Index is the position of the first key, or of where to start searching
+	// for Keyword, the command name being at position 0
+	Index int
+	// Keyword, if set, is the argument the first key follows
+	Keyword string
+	// LastKey is the position of the last key relative to the first one, or,
+	// if negative, relative to the end of the arguments (-1 being the last)
+	LastKey int
+	// Step is the distance between keys
+	Step int
+	// Flags describe how the keys are used, from the Key* constants
+	Flags []string
+}
+
+// find returns the keys described by the spec in argv, the command name
+// followed by its arguments
+func (s KeySpec) find(argv []string) []string {
+	first := s.Index
+	if s.Keyword != "" {
+		first = -1
+		for i := s.Index; i < len(argv); i++ {
+			if strings.EqualFold(argv[i], s.Keyword) {
+				first = i + 1
+				break
+			}
+		}
+		if first < 0 {
+			return nil
+		}
+	}
+
+	last := first + s.LastKey
+	if s.LastKey < 0 {
+		last = len(argv) + s.LastKey
+	}
+	var keys []string
+	for i := first; i <= last && i < len(argv); i += max(s.Step, 1) {
+		keys = append(keys, argv[i])
+	}
+	return keys
+}
+
+// permissions returns the ACL permissions the keys need. Keys that are
+// neither accessed nor written to, such as those of EXISTS, need none.
+func (s KeySpec) permissions() acl.Permission {
+	var perms acl.Permission
+	for _, flag := range s.Flags {
+		switch flag {
+		case KeyAccess:
+			perms |= acl.PermissionRead
+		case KeyInsert, KeyUpdate, KeyDelete:
+			perms |= acl.PermissionWrite
+		}
+	}
+	return perms
+}
+
+// Doc documents a command, as shown by COMMAND DOCS
+type Doc struct {
+	Summary    string
+	Since      string
+	Group      string
+	Complexity string
+	// DeprecatedSince and ReplacedBy are set for deprecated commands
+	DeprecatedSince string
+	ReplacedBy      string
+}
+
+// Metadata describes a command or subcommand: how many arguments it takes,
+// how it behaves, who may run it and which of its arguments are keys.
+type Metadata struct {
+	// Arity is the number of arguments including the command name, and the
+	// subcommand name for subcommands, or if negative, minus the minimum
+	Arity int
+	// Flags describe how the command behaves
+	Flags CommandFlags
+	// Categories are the ACL categories of the command besides those its
+	// flags imply
+	Categories []string
+	// KeySpecs locate the keys the command accesses
+	KeySpecs []KeySpec
+	// Tips are hints for clients, such as how cluster clients should route
+	// the command
+	Tips []string
+	// Doc documents the command
+	Doc Doc
+	// Subcommands maps the lowercase names of the subcommands of a container
+	// command such as CLIENT to their metadata
+	Subcommands map[string]*Metadata
+}
+
+// defaultMetadata describes commands registered without metadata: they take
+// any number of arguments and have no flags or keys
+var defaultMetadata = &Metadata{Arity: -1}
+
+// checkArity reports whether argc arguments, including the command name,
+// satisfy the command's arity
+func (m *Metadata) checkArity(argc int) bool {
+	if m.Arity >= 0 {
+		return argc == m.Arity
+	}
+	return argc >= -m.Arity
+}
+
+// subcommand returns the lowercase name and metadata of the subcommand args
+// start with, if m describes a container command and args[0] is one of its
+// subcommands, or "" and m otherwise
+func (m *Metadata) subcommand(args []string) (string, *Metadata) {
+	if m.Subcommands == nil || len(args) == 0 {
+		return "", m
+	}
+	name := strings.ToLower(args[0])
+	if sub, ok := m.Subcommands[name]; ok {
+		return name, sub
+	}
+	return "", m
+}
+
+// AllCategories returns the ACL categories of the command, including those
+// its flags imply, in the order of acl.Categories. As in Redis, commands
+// that aren't fast are slow.
+func (m *Metadata) AllCategories() []string {
+	implied := slices.Clone(m.Categories)
+	if m.Flags&FlagWrite != 0 {
+		implied = append(implied, "write")
+	}
+	if m.Flags&FlagReadOnly != 0 {
+		implied = append(implied, "read")
+	}
+	if m.Flags&FlagAdmin != 0 {
+		implied = append(implied, "admin", "dangerous")
+	}
+	if m.Flags&FlagPubSub != 0 {
+		implied = append(implied, "pubsub")
+	}
+	if m.Flags&FlagFast != 0 {
+		implied = append(implied, "fast")
+	} else if !slices.Contains(implied, "fast") {
+		implied = append(implied, "slow")
+	}
+
+	var categories []string
+	for _, category := range acl.Categories {
+		if slices.Contains(implied, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// keyRange returns the first key, last key and step COMMAND INFO shows for
+// clients that don't understand key specs. It covers the keys at fixed
+// positions; movable is set if some keys can only be found with the key
+// specs, such as those following a keyword.
+func (m *Metadata) keyRange() (first, last, step int, movable bool) {
+	found := false
+	for _, spec := range m.KeySpecs {
+		if spec.Keyword != "" {
+			movable = true
+			continue
+		}
+		specLast := spec.Index + spec.LastKey
+		if spec.LastKey < 0 {
+			specLast = spec.LastKey
+		}
+		switch {
+		case !found:
+			first, last, step, found = spec.Index, specLast, max(spec.Step, 1), true
+		case last >= 0 && spec.Index == last+1 && step == 1 && spec.Step <= 1:
+			// Contiguous with the keys so far
+			last = specLast
+		default:
+			movable = true
+		}
+	}
+	return first, last, step, movable
+}
+
+// keys returns the keys among argv, the command name followed by its
+// arguments, along with the flags of the spec that found each of them
+func (m *Metadata) keys(argv []string) (keys []string, flags [][]string) {
+	for _, spec := range m.KeySpecs {
+		for _, key := range spec.find(argv) {
+			keys = append(keys, key)
+			flags = append(flags, spec.Flags)
+		}
+	}
+	return keys, flags
+}
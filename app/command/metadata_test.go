@@ -0,0 +1,140 @@
+package command
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+)
+
+func TestKeySpec_Find(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		argv     []string
+		expected []string
+	}{
+		{name: "single key", command: "GET", argv: []string{"GET", "foo"}, expected: []string{"foo"}},
+		{name: "all arguments", command: "DEL", argv: []string{"DEL", "a", "b", "c"}, expected: []string{"a", "b", "c"}},
+		{name: "no keys", command: "DBSIZE", argv: []string{"DBSIZE"}, expected: nil},
+		{name: "destination then sources", command: "BITOP", argv: []string{"BITOP", "AND", "dest", "a", "b"}, expected: []string{"dest", "a", "b"}},
+		{name: "keyword", command: "GEORADIUS", argv: []string{"GEORADIUS", "src", "0", "0", "1", "km", "STORE", "dest"}, expected: []string{"src", "dest"}},
+		{name: "missing keyword", command: "GEORADIUS", argv: []string{"GEORADIUS", "src", "0", "0", "1", "km"}, expected: []string{"src"}},
+		{name: "member named like the keyword", command: "GEORADIUSBYMEMBER", argv: []string{"GEORADIUSBYMEMBER", "src", "STORE", "1", "km"}, expected: []string{"src"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, spec := range commandTable[tt.command].KeySpecs {
+				keys = append(keys, spec.find(tt.argv)...)
+			}
+			if !slices.Equal(keys, tt.expected) {
+				t.Errorf("Expected keys %q, got %q", tt.expected, keys)
+			}
+		})
+	}
+}
+
+func TestKeySpec_Permissions(t *testing.T) {
+	tests := []struct {
+		flags    []string
+		expected acl.Permission
+	}{
+		{readKey, acl.PermissionRead},
+		{overwriteKey, acl.PermissionWrite},
+		{updateKey, acl.PermissionRead | acl.PermissionWrite},
+		{removeKey, acl.PermissionWrite},
+		{existenceKey, 0},
+		{accessWriteKey, acl.PermissionRead},
+	}
+
+	for _, tt := range tests {
+		if got := (KeySpec{Flags: tt.flags}).permissions(); got != tt.expected {
+			t.Errorf("Expected %v to need permissions %d, got %d", tt.flags, tt.expected, got)
+		}
+	}
+}
+
+func TestMetadata_CheckArity(t *testing.T) {
+	tests := []struct {
+		arity int
+		argc  int
+		valid bool
+	}{
+		{2, 2, true},
+		{2, 1, false},
+		{2, 3, false},
+		{-2, 2, true},
+		{-2, 5, true},
+		{-2, 1, false},
+		{-1, 1, true},
+	}
+
+	for _, tt := range tests {
+		if got := (&Metadata{Arity: tt.arity}).checkArity(tt.argc); got != tt.valid {
+			t.Errorf("Expected arity %d with %d arguments to be valid = %v", tt.arity, tt.argc, tt.valid)
+		}
+	}
+}
+
+func TestMetadata_Subcommand(t *testing.T) {
+	client := commandTable["CLIENT"]
+	if name, meta := client.subcommand([]string{"Id"}); name != "id" || meta != client.Subcommands["id"] {
+		t.Errorf("Expected the id subcommand, got %q", name)
+	}
+	if name, meta := client.subcommand([]string{"nosuch"}); name != "" || meta != client {
+		t.Errorf("Expected unknown subcommands to resolve to the container, got %q", name)
+	}
+	if name, meta := commandTable["GET"].subcommand([]string{"key"}); name != "" || meta != commandTable["GET"] {
+		t.Errorf("Expected commands without subcommands to resolve to themselves, got %q", name)
+	}
+}
+
+func TestMetadata_AllCategories(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected []string
+	}{
+		{"GET", []string{"read", "string", "fast"}},
+		{"SET", []string{"write", "string", "slow"}},
+		{"KEYS", []string{"keyspace", "read", "slow", "dangerous"}},
+		{"SWAPDB", []string{"keyspace", "write", "fast", "dangerous"}},
+		{"PING", []string{"fast", "connection"}},
+		{"INFO", []string{"slow", "dangerous"}},
+	}
+
+	for _, tt := range tests {
+		if got := commandTable[tt.command].AllCategories(); !slices.Equal(got, tt.expected) {
+			t.Errorf("Expected %s categories %q, got %q", tt.command, tt.expected, got)
+		}
+	}
+
+	kill := commandTable["CLIENT"].Subcommands["kill"].AllCategories()
+	if expected := []string{"admin", "slow", "dangerous", "connection"}; !slices.Equal(kill, expected) {
+		t.Errorf("Expected client|kill categories %q, got %q", expected, kill)
+	}
+}
+
+func TestMetadata_KeyRange(t *testing.T) {
+	tests := []struct {
+		command           string
+		first, last, step int
+		movable           bool
+	}{
+		{"PING", 0, 0, 0, false},
+		{"GET", 1, 1, 1, false},
+		{"DEL", 1, -1, 1, false},
+		{"RENAME", 1, 2, 1, false},
+		{"BITOP", 2, -1, 1, false},
+		{"GEORADIUS", 1, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		first, last, step, movable := commandTable[tt.command].keyRange()
+		if first != tt.first || last != tt.last || step != tt.step || movable != tt.movable {
+			t.Errorf("Expected %s key range %d %d %d (movable %v), got %d %d %d (movable %v)",
+				tt.command, tt.first, tt.last, tt.step, tt.movable, first, last, step, movable)
+		}
+	}
+}
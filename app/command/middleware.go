@@ -0,0 +1,110 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// Invocation is a command being executed on behalf of a client, as seen by
+// middleware
+type Invocation struct {
+	// Client is the client running the command
+	Client *Client
+	// Name is the name of the command in uppercase
+	Name string
+	// Subcommand is the name of the subcommand in lowercase, for container
+	// commands such as CLIENT, or empty
+	Subcommand string
+	// Args are the arguments of the command, starting with the subcommand
+	// name for container commands
+	Args []string
+	// Metadata describes the command, or its subcommand if it has one. It is
+	// nil for unknown commands, which the built-in checks reject.
+	Metadata *Metadata
+
+	// command is the command and its own metadata, or nil for unknown
+	// commands
+	command *registeredCommand
+}
+
+// FullName returns the lowercase name of the command as Redis reports it,
+// such as "get" or "client|id"
+func (inv *Invocation) FullName() string {
+	name := strings.ToLower(inv.Name)
+	if inv.Subcommand != "" {
+		name += "|" + inv.Subcommand
+	}
+	return name
+}
+
+// Handler executes an invocation, returning the reply or the error to send
+// to the client
+type Handler func(inv *Invocation) (resp.Reply, error)
+
+// Middleware wraps the execution of commands, such as to check, record or
+// trace them. It returns a handler that may inspect the invocation, call next
+// to carry on executing it and inspect the result, or reply without calling
+// next to reject it.
+type Middleware func(next Handler) Handler
+
+// chain builds the handler running the middleware in order around run
+func chain(middleware []Middleware, run Handler) Handler {
+	handler := run
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// runCommand executes the command of an invocation, at the end of the chain
+func runCommand(inv *Invocation) (resp.Reply, error) {
+	return inv.command.cmd.Execute(inv.Client, inv.Args)
+}
+
+// checkExists rejects invocations of commands that aren't registered
+func checkExists(next Handler) Handler {
+	return func(inv *Invocation) (resp.Reply, error) {
+		if inv.command == nil {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "command not found")
+		}
+		return next(inv)
+	}
+}
+
+// checkArity rejects invocations whose number of arguments doesn't match the
+// arity of the command
+func checkArity(next Handler) Handler {
+	return func(inv *Invocation) (resp.Reply, error) {
+		if !inv.Metadata.checkArity(len(inv.Args) + 1) {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("wrong number of arguments for '%s' command", inv.FullName()))
+		}
+		return next(inv)
+	}
+}
+
+// requireAuth rejects the commands of clients that haven't authenticated,
+// except those flagged no_auth
+func requireAuth(next Handler) Handler {
+	return func(inv *Invocation) (resp.Reply, error) {
+		if inv.Metadata.Flags&FlagNoAuth == 0 && !inv.Client.Authenticated() {
+			return resp.Reply{}, errors.NewWithCode("NOAUTH", "Authentication required.")
+		}
+		return next(inv)
+	}
+}
+
+// checkACL rejects the commands and keys the ACL user of the client isn't
+// allowed, logging the denials. Commands flagged no_auth are always allowed.
+func checkACL(next Handler) Handler {
+	return func(inv *Invocation) (resp.Reply, error) {
+		if inv.Metadata.Flags&FlagNoAuth == 0 {
+			if err := checkClientPermissions(inv.Client, inv.command.meta, inv.Name, inv.Args); err != nil {
+				return resp.Reply{}, err
+			}
+		}
+		return next(inv)
+	}
+}
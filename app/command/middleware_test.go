@@ -0,0 +1,132 @@
+package command
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+func TestInvocation_FullName(t *testing.T) {
+	if name := (&Invocation{Name: "GET"}).FullName(); name != "get" {
+		t.Errorf("Expected get, got %s", name)
+	}
+	if name := (&Invocation{Name: "CLIENT", Subcommand: "id"}).FullName(); name != "client|id" {
+		t.Errorf("Expected client|id, got %s", name)
+	}
+}
+
+func TestRegistry_Use(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	registry.Register(NewClientCommand(NewClients()))
+
+	var calls []string
+	record := func(label string) Middleware {
+		return func(next Handler) Handler {
+			return func(inv *Invocation) (resp.Reply, error) {
+				calls = append(calls, label+" "+inv.FullName())
+				reply, err := next(inv)
+				calls = append(calls, label+" done")
+				return reply, err
+			}
+		}
+	}
+	registry.Use(record("first"), record("second"))
+
+	if _, err := registry.Execute(NewClient(io.Discard), "CLIENT", []string{"ID"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"first client|id", "second client|id", "second done", "first done"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("Expected middleware to run in order, got %q", calls)
+	}
+
+	t.Run("sees the result", func(t *testing.T) {
+		var result resp.Reply
+		var meta *Metadata
+		registry.Use(func(next Handler) Handler {
+			return func(inv *Invocation) (resp.Reply, error) {
+				reply, err := next(inv)
+				result, meta = reply, inv.Metadata
+				return reply, err
+			}
+		})
+		if _, err := registry.Execute(NewClient(io.Discard), "GET", []string{"missing"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if encodeRESP2(result) != "$-1\r\n" || meta != commandTable["GET"] {
+			t.Errorf("Expected middleware to see the reply and metadata of GET, got %q", encodeRESP2(result))
+		}
+	})
+
+	t.Run("sees commands the built-in checks reject", func(t *testing.T) {
+		calls = nil
+		if _, err := registry.Execute(NewClient(io.Discard), "GET", nil); err == nil {
+			t.Fatalf("Expected an arity error")
+		}
+		unauthenticated := NewClient(io.Discard)
+		unauthenticated.authenticated = false
+		if _, err := registry.Execute(unauthenticated, "GET", []string{"key"}); errors.ReplyCode(err) != "NOAUTH" {
+			t.Fatalf("Expected a NOAUTH error, got %v", err)
+		}
+		if _, err := registry.Execute(NewClient(io.Discard), "NOSUCH", nil); err == nil || err.Error() != "command not found" {
+			t.Fatalf("Expected an unknown command error, got %v", err)
+		}
+		expected := []string{"first get", "first get", "first nosuch"}
+		var seen []string
+		for _, call := range calls {
+			if strings.HasPrefix(call, "first ") && call != "first done" {
+				seen = append(seen, call)
+			}
+		}
+		if !slices.Equal(seen, expected) {
+			t.Errorf("Expected middleware to see the rejected commands, got %q", calls)
+		}
+	})
+}
+
+func TestRegistry_UseRejects(t *testing.T) {
+	registry := NewRegistry()
+	getCmd := NewMockCommand("GET", resp.SimpleString("ok"), nil)
+	registry.RegisterWithMetadata(getCmd, commandTable["GET"])
+	registry.Use(func(next Handler) Handler {
+		return func(inv *Invocation) (resp.Reply, error) {
+			if inv.Args[0] == "secret" {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "audit: access refused")
+			}
+			return next(inv)
+		}
+	})
+
+	if _, err := registry.Execute(NewClient(io.Discard), "GET", []string{"secret"}); err == nil || err.Error() != "audit: access refused" {
+		t.Errorf("Expected the middleware to refuse the command, got %v", err)
+	}
+	if getCmd.executeCount != 0 {
+		t.Errorf("Expected the refused command not to run")
+	}
+	if _, err := registry.Execute(NewClient(io.Discard), "GET", []string{"other"}); err != nil || getCmd.executeCount != 1 {
+		t.Errorf("Expected other commands to run, got %v", err)
+	}
+}
+
+func TestCheckACL(t *testing.T) {
+	acl.GetACL().SetUser("middleware-user", []string{"on", "nopass", "+get", "~allowed"}, func(string, string) bool { return true })
+	t.Cleanup(func() { acl.GetACL().DeleteUsers([]string{"middleware-user"}) })
+
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	client := NewClient(io.Discard)
+	client.User = "middleware-user"
+
+	if _, err := registry.Execute(client, "GET", []string{"allowed"}); err != nil {
+		t.Errorf("Expected GET of an allowed key to run, got %v", err)
+	}
+	if _, err := registry.Execute(client, "GET", []string{"other"}); err == nil || errors.ReplyCode(err) != "NOPERM" {
+		t.Errorf("Expected GET of another key to be denied, got %v", err)
+	}
+}
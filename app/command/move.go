@@ -0,0 +1,42 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// MoveCommand implements the MOVE command
+type MoveCommand struct {
+	dbs *store.Databases
+}
+
+// NewMoveCommand creates a new MOVE command
+func NewMoveCommand(dbs *store.Databases) *MoveCommand {
+	return &MoveCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *MoveCommand) Name() string {
+	return "MOVE"
+}
+
+// Execute handles the MOVE command
+func (c *MoveCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	index, err := parseInteger(args[1])
+	if err != nil {
+		return resp.Reply{}, err
+	}
+	dstDB, err := c.dbs.DB(index)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+	if dstDB == client.DB {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "source and destination objects are the same")
+	}
+
+	if client.DB.Move(args[0], dstDB) {
+		return resp.Integer(1), nil
+	}
+	return resp.Integer(0), nil
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestMoveCommand_Name(t *testing.T) {
+	cmd := NewMoveCommand(store.GetDatabases())
+	if cmd.Name() != "MOVE" {
+		t.Errorf("Expected command name to be 'MOVE', got %s", cmd.Name())
+	}
+}
+
+func TestMoveCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	db1, _ := store.GetDatabases().DB(1)
+	storeInstance.Set("move-key", "value", time.Hour)
+	storeInstance.Set("move-taken", "db0", 0)
+	storeInstance.Delete("move-missing")
+	db1.Delete("move-key")
+	db1.Set("move-taken", "db1", 0)
+	cmd := NewMoveCommand(store.GetDatabases())
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "move", args: []string{"move-key", "1"}, expected: ":1\r\n"},
+		{name: "move again", args: []string{"move-key", "1"}, expected: ":0\r\n"},
+		{name: "existing destination key", args: []string{"move-taken", "1"}, expected: ":0\r\n"},
+		{name: "missing key", args: []string{"move-missing", "1"}, expected: ":0\r\n"},
+		{name: "same db", args: []string{"move-taken", "0"}, errMsg: "source and destination objects are the same"},
+		{name: "out of range", args: []string{"move-taken", "16"}, errMsg: "DB index is out of range"},
+		{name: "not an integer", args: []string{"move-taken", "x"}, errMsg: "value is not an integer or out of range"},
+		{name: "wrong number of arguments", args: []string{"move-key"}, errMsg: "wrong number of arguments for 'move' command"},
+	})
+
+	if value, err := db1.Get("move-key"); err != nil || value != "value" {
+		t.Errorf("Expected move-key in database 1, got %q (err: %v)", value, err)
+	}
+	if value, _ := db1.Get("move-taken"); value != "db1" {
+		t.Errorf("Expected move-taken in database 1 to be untouched, got %q", value)
+	}
+	db1.Delete("move-key")
+	db1.Delete("move-taken")
+}
@@ -0,0 +1,208 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+// ClientClass groups connections that share an output buffer limit
+type ClientClass int
+
+const (
+	// ClassNormal is the class of ordinary client connections
+	ClassNormal ClientClass = iota
+	// ClassReplica is the class of replica connections. The server has no
+	// replication, so no connection is in it and its limit can't be changed.
+	ClassReplica
+	// ClassPubSub is the class of connections subscribed to pub/sub channels.
+	// The server has no pub/sub, so no connection is in it and its limit
+	// can't be changed.
+	ClassPubSub
+)
+
+// String returns the name of the class as used by client-output-buffer-limit
+func (c ClientClass) String() string {
+	switch c {
+	case ClassReplica:
+		return "replica"
+	case ClassPubSub:
+		return "pubsub"
+	default:
+		return "normal"
+	}
+}
+
+// OutputBufferLimit limits how many bytes of replies may wait to be written
+// to a connection. A client is disconnected as soon as it reaches the hard
+// limit, or once it has stayed at or above the soft limit for longer than
+// SoftSeconds. A limit of 0 is disabled.
+type OutputBufferLimit struct {
+	Hard        int64
+	Soft        int64
+	SoftSeconds int64
+}
+
+// DefaultOutputBufferLimits is the default client-output-buffer-limit
+const DefaultOutputBufferLimits = "normal 0 0 0 replica 268435456 67108864 60 pubsub 33554432 8388608 60"
+
+// defaultOutputBufferLimits are the limits of each class until they are set
+var defaultOutputBufferLimits = [3]OutputBufferLimit{
+	ClassNormal:  {},
+	ClassReplica: {Hard: 256 * 1024 * 1024, Soft: 64 * 1024 * 1024, SoftSeconds: 60},
+	ClassPubSub:  {Hard: 32 * 1024 * 1024, Soft: 8 * 1024 * 1024, SoftSeconds: 60},
+}
+
+// ParseOutputBufferLimits parses a client-output-buffer-limit setting, a list
+// of "<class> <hard> <soft> <soft seconds>" groups in which the sizes may use
+// memory units. Classes that are not listed keep their default limits.
+func ParseOutputBufferLimits(value string) ([3]OutputBufferLimit, error) {
+	limits := defaultOutputBufferLimits
+
+	fields := strings.Fields(value)
+	if len(fields)%4 != 0 {
+		return limits, fmt.Errorf("Wrong number of arguments in buffer limit configuration.")
+	}
+	for i := 0; i < len(fields); i += 4 {
+		var class ClientClass
+		switch strings.ToLower(fields[i]) {
+		case "normal":
+			class = ClassNormal
+		case "replica", "slave":
+			class = ClassReplica
+		case "pubsub":
+			class = ClassPubSub
+		default:
+			return limits, fmt.Errorf("Invalid client class specified in buffer limit configuration.")
+		}
+
+		hard, hardErr := config.ParseMemory(fields[i+1])
+		soft, softErr := config.ParseMemory(fields[i+2])
+		seconds, secondsErr := strconv.ParseInt(fields[i+3], 10, 64)
+		if hardErr != nil || softErr != nil || secondsErr != nil || seconds < 0 {
+			return limits, fmt.Errorf("Error in hard, soft or soft_seconds setting in buffer limit configuration.")
+		}
+		limits[class] = OutputBufferLimit{Hard: hard, Soft: soft, SoftSeconds: seconds}
+	}
+	return limits, nil
+}
+
+// FormatOutputBufferLimits formats limits as a client-output-buffer-limit
+// setting listing every class, with sizes in bytes
+func FormatOutputBufferLimits(limits [3]OutputBufferLimit) string {
+	groups := make([]string, len(limits))
+	for class, limit := range limits {
+		groups[class] = fmt.Sprintf("%s %d %d %d", ClientClass(class), limit.Hard, limit.Soft, limit.SoftSeconds)
+	}
+	return strings.Join(groups, " ")
+}
+
+// parsedOutputLimits caches the limits parsed from the current setting
+type parsedOutputLimits struct {
+	value  string
+	limits [3]OutputBufferLimit
+}
+
+var outputLimitsCache atomic.Pointer[parsedOutputLimits]
+
+// outputBufferLimit returns the configured limit for class. A setting that
+// can't be parsed leaves the defaults in place.
+func outputBufferLimit(class ClientClass) OutputBufferLimit {
+	value, ok := config.Get("client-output-buffer-limit")
+	if !ok {
+		value = DefaultOutputBufferLimits
+	}
+
+	cached := outputLimitsCache.Load()
+	if cached == nil || cached.value != value {
+		limits, _ := ParseOutputBufferLimits(value)
+		cached = &parsedOutputLimits{value: value, limits: limits}
+		outputLimitsCache.Store(cached)
+	}
+	return cached.limits[class]
+}
+
+// outputBuffer holds the replies to a connection until they are flushed.
+// Replies accumulate while pipelined commands are executed, and the size of
+// what is still waiting to be written, including the part of a flush the
+// client hasn't read yet, is what output buffer limits apply to.
+type outputBuffer struct {
+	w   io.Writer
+	buf []byte
+	// pending is the number of bytes not yet written to w
+	pending atomic.Int64
+
+	mu sync.Mutex
+	// softLimitSince is when pending reached the soft limit, or zero
+	softLimitSince time.Time
+}
+
+// maxRetainedOutput is the largest buffer kept for reuse after a flush
+const maxRetainedOutput = 64 * 1024
+
+// flushChunkSize is how much of the buffer is written at once, so pending
+// shrinks as a slow client reads a large reply
+const flushChunkSize = 64 * 1024
+
+// Write appends p to the buffer.
+func (o *outputBuffer) Write(p []byte) (int, error) {
+	o.buf = append(o.buf, p...)
+	o.pending.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// flush writes the buffer to the underlying writer.
+func (o *outputBuffer) flush() error {
+	var err error
+	for len(o.buf) > 0 && err == nil {
+		var n int
+		n, err = o.w.Write(o.buf[:min(len(o.buf), flushChunkSize)])
+		o.buf = o.buf[n:]
+		o.pending.Add(-int64(n))
+	}
+
+	if cap(o.buf) > maxRetainedOutput {
+		o.buf = nil
+	} else {
+		o.buf = o.buf[:0]
+	}
+	return err
+}
+
+// discard drops the buffered output of a killed client.
+func (o *outputBuffer) discard() {
+	o.buf = nil
+	o.pending.Store(0)
+}
+
+// exceeds reports which part of limit the pending output breaks, or "" if
+// none. It tracks how long the soft limit has been reached, so it is called
+// whenever pending output grows and periodically while a flush is blocked.
+func (o *outputBuffer) exceeds(limit OutputBufferLimit) string {
+	pending := o.pending.Load()
+	if limit.Hard > 0 && pending >= limit.Hard {
+		return fmt.Sprintf("hard limit of %d bytes reached with %d bytes pending", limit.Hard, pending)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if limit.Soft == 0 || pending < limit.Soft {
+		o.softLimitSince = time.Time{}
+		return ""
+	}
+	if o.softLimitSince.IsZero() {
+		o.softLimitSince = time.Now()
+		return ""
+	}
+	if elapsed := time.Since(o.softLimitSince); elapsed > time.Duration(limit.SoftSeconds)*time.Second {
+		return fmt.Sprintf("soft limit of %d bytes exceeded for more than %d seconds with %d bytes pending", limit.Soft, limit.SoftSeconds, pending)
+	}
+	return ""
+}
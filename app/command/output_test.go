@@ -0,0 +1,131 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+func TestParseOutputBufferLimits(t *testing.T) {
+	limits, err := ParseOutputBufferLimits("normal 1mb 512kb 10 slave 0 0 0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [3]OutputBufferLimit{
+		ClassNormal:  {Hard: 1024 * 1024, Soft: 512 * 1024, SoftSeconds: 10},
+		ClassReplica: {},
+		ClassPubSub:  {Hard: 32 * 1024 * 1024, Soft: 8 * 1024 * 1024, SoftSeconds: 60},
+	}
+	if limits != expected {
+		t.Errorf("Expected %+v, got %+v", expected, limits)
+	}
+
+	defaults, err := ParseOutputBufferLimits(DefaultOutputBufferLimits)
+	if err != nil || defaults[ClassNormal] != (OutputBufferLimit{}) || defaults[ClassReplica].Hard != 256*1024*1024 {
+		t.Errorf("Unexpected default limits %+v (%v)", defaults, err)
+	}
+
+	for _, value := range []string{
+		"normal 0 0",
+		"master 0 0 0",
+		"normal 1xb 0 0",
+		"normal 0 0 -1",
+	} {
+		if _, err := ParseOutputBufferLimits(value); err == nil {
+			t.Errorf("Expected an error parsing %q", value)
+		}
+	}
+}
+
+func TestNormalizeOutputBufferLimits(t *testing.T) {
+	value, err := normalizeOutputBufferLimits("normal 1kb 0 0 pubsub 32mb 8mb 60")
+	expected := "normal 1024 0 0 replica 268435456 67108864 60 pubsub 33554432 8388608 60"
+	if err != nil || value != expected {
+		t.Errorf("Expected %q, got %q (%v)", expected, value, err)
+	}
+
+	// Only the defaults of the classes no connection belongs to are accepted
+	for _, value := range []string{"pubsub 1mb 0 0", "slave 0 0 0"} {
+		if _, err := normalizeOutputBufferLimits(value); err == nil {
+			t.Errorf("Expected %q to be refused", value)
+		}
+	}
+}
+
+func TestOutputBuffer_Flush(t *testing.T) {
+	var out bytes.Buffer
+	client := NewClient(&out)
+	large := strings.Repeat("x", 3*flushChunkSize)
+
+	client.WriteReply(resp.BulkString(large))
+	if out.Len() != 0 {
+		t.Errorf("Expected replies to be held until flushed, got %d bytes", out.Len())
+	}
+	if pending := client.output.pending.Load(); pending != int64(len(large)+11) {
+		t.Errorf("Expected %d bytes pending, got %d", len(large)+11, pending)
+	}
+
+	if err := client.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != "$196608\r\n"+large+"\r\n" {
+		t.Errorf("Unexpected output of %d bytes", out.Len())
+	}
+	if pending := client.output.pending.Load(); pending != 0 {
+		t.Errorf("Expected nothing pending after a flush, got %d", pending)
+	}
+}
+
+func TestOutputBuffer_Exceeds(t *testing.T) {
+	o := &outputBuffer{w: io.Discard}
+	o.Write(make([]byte, 100))
+
+	if reason := o.exceeds(OutputBufferLimit{Hard: 100}); !strings.HasPrefix(reason, "hard limit of 100 bytes") {
+		t.Errorf("Expected the hard limit to be reached, got %q", reason)
+	}
+	if reason := o.exceeds(OutputBufferLimit{Hard: 101}); reason != "" {
+		t.Errorf("Expected the hard limit not to be reached, got %q", reason)
+	}
+
+	// The soft limit only applies once it has been exceeded for long enough
+	soft := OutputBufferLimit{Soft: 50}
+	if reason := o.exceeds(soft); reason != "" {
+		t.Errorf("Expected the soft limit to be tolerated at first, got %q", reason)
+	}
+	time.Sleep(time.Millisecond)
+	if reason := o.exceeds(soft); !strings.HasPrefix(reason, "soft limit of 50 bytes exceeded for more than 0 seconds") {
+		t.Errorf("Expected the soft limit to be exceeded, got %q", reason)
+	}
+
+	// Going back under the soft limit resets it
+	o.flush()
+	if reason := o.exceeds(soft); reason != "" || !o.softLimitSince.IsZero() {
+		t.Errorf("Expected the soft limit to be reset, got %q", reason)
+	}
+}
+
+func TestClient_OutputBufferLimitExceeded(t *testing.T) {
+	defer config.SetConfig("client-output-buffer-limit", DefaultOutputBufferLimits)
+
+	client := NewClient(io.Discard)
+	client.WriteReply(resp.BulkString(strings.Repeat("x", 2048)))
+	if reason := client.OutputBufferLimitExceeded(); reason != "" {
+		t.Errorf("Expected normal clients to be unlimited by default, got %q", reason)
+	}
+
+	config.SetConfig("client-output-buffer-limit", "normal 1kb 0 0")
+	if reason := client.OutputBufferLimitExceeded(); !strings.HasPrefix(reason, "normal client hard limit of 1024 bytes") {
+		t.Errorf("Expected the normal hard limit to be reached, got %q", reason)
+	}
+
+	// Replies to a killed client are dropped
+	client.Kill()
+	if err := client.Flush(); err != nil || client.output.pending.Load() != 0 {
+		t.Errorf("Expected a killed client's replies to be discarded, got %d pending (%v)", client.output.pending.Load(), err)
+	}
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/hll"
+)
+
+// init declares the parameters of the commands
+func init() {
+	for _, p := range []config.Parameter{
+		{Name: "client-output-buffer-limit", Type: config.TypeString, Default: DefaultOutputBufferLimits, Normalize: normalizeOutputBufferLimits},
+		{Name: "hll-sparse-max-bytes", Type: config.TypeMemory, Default: strconv.Itoa(hll.DefaultSparseMaxBytes), Min: 0, Max: math.MaxInt32},
+	} {
+		config.Register(p)
+	}
+}
+
+// normalizeOutputBufferLimits checks a client-output-buffer-limit setting and
+// returns it with the limits of every class, as CONFIG GET shows it. Only
+// the normal class can be changed, as no connection belongs to the others
+// yet; they may still be set to their defaults, as stock redis.conf files do.
+func normalizeOutputBufferLimits(value string) (string, error) {
+	limits, err := ParseOutputBufferLimits(value)
+	if err != nil {
+		return "", err
+	}
+	for _, class := range []ClientClass{ClassReplica, ClassPubSub} {
+		if limits[class] != defaultOutputBufferLimits[class] {
+			return "", fmt.Errorf("The %s class isn't supported, so its buffer limit can't be changed.", class)
+		}
+	}
+	return FormatOutputBufferLimits(limits), nil
+}
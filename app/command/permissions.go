@@ -0,0 +1,42 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/errors"
+)
+
+// aclName returns the name ACL rules refer to the command name with args,
+// described by meta, as: the command in lowercase, and its subcommand if it
+// has subcommands, along with the metadata that applies
+func aclName(meta *Metadata, name string, args []string) (command, subcommand string, applied *Metadata) {
+	subcommand, applied = meta.subcommand(args)
+	return strings.ToLower(name), subcommand, applied
+}
+
+// checkPermissions checks that user may run the command name with args,
+// described by meta, on the keys it accesses. It returns the reason for a
+// denial, as used by the ACL log, the command or key denied and the error to
+// reply with.
+func checkPermissions(user *acl.User, meta *Metadata, name string, args []string) (reason, object string, err error) {
+	command, subcommand, applied := aclName(meta, name, args)
+	object = command
+	if subcommand != "" {
+		object += "|" + subcommand
+	}
+	if !user.CanRun(command, subcommand, applied.AllCategories()) {
+		return acl.ReasonCommand, object, errors.NewWithCode("NOPERM", fmt.Sprintf("User %s has no permissions to run the '%s' command", user.Name, object))
+	}
+
+	argv := append([]string{name}, args...)
+	for _, spec := range applied.KeySpecs {
+		for _, key := range spec.find(argv) {
+			if !user.CanAccessKey(key, spec.permissions()) {
+				return acl.ReasonKey, key, errors.NewWithCode("NOPERM", fmt.Sprintf("User %s has no permissions to access the '%s' key", user.Name, key))
+			}
+		}
+	}
+	return "", "", nil
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/acl"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	a := acl.NewACL()
+	a.SetUser("alice", []string{"on", "nopass", "+@read", "+set", "+client|id", "~cache:*", "%R~logs:*"}, func(command, subcommand string) bool { return true })
+	user, _ := a.User("alice")
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		reason  string
+		object  string
+		errMsg  string
+	}{
+		{name: "allowed", command: "GET", args: []string{"cache:1"}},
+		{name: "read-only pattern", command: "GET", args: []string{"logs:1"}},
+		{name: "command denied", command: "DEL", args: []string{"cache:1"}, reason: acl.ReasonCommand, object: "del", errMsg: "User alice has no permissions to run the 'del' command"},
+		{name: "key denied", command: "GET", args: []string{"other"}, reason: acl.ReasonKey, object: "other", errMsg: "User alice has no permissions to access the 'other' key"},
+		{name: "write to read-only pattern", command: "SET", args: []string{"logs:1", "x"}, reason: acl.ReasonKey, object: "logs:1", errMsg: "User alice has no permissions to access the 'logs:1' key"},
+		{name: "second key denied", command: "COPY", args: []string{"cache:1", "logs:1"}, reason: acl.ReasonCommand, object: "copy", errMsg: "User alice has no permissions to run the 'copy' command"},
+		{name: "subcommand allowed", command: "CLIENT", args: []string{"ID"}},
+		{name: "subcommand denied", command: "CLIENT", args: []string{"KILL", "ID", "1"}, reason: acl.ReasonCommand, object: "client|kill", errMsg: "User alice has no permissions to run the 'client|kill' command"},
+		{name: "existence checks need no access", command: "EXISTS", args: []string{"cache:1", "logs:1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, object, err := checkPermissions(user, commandTable[tt.command], tt.command, tt.args)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected the command to be allowed, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errMsg || reason != tt.reason || object != tt.object {
+				t.Errorf("Expected %s denial of %q (%q), got %s denial of %q (%v)", tt.reason, tt.object, tt.errMsg, reason, object, err)
+			}
+		})
+	}
+}
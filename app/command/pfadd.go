@@ -0,0 +1,54 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/hll"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// PfAddCommand implements the PFADD command
+type PfAddCommand struct {
+}
+
+// NewPfAddCommand creates a new PFADD command
+func NewPfAddCommand() *PfAddCommand {
+	return &PfAddCommand{}
+}
+
+// Name returns the command name
+func (c *PfAddCommand) Name() string {
+	return "PFADD"
+}
+
+// Execute handles the PFADD command
+func (c *PfAddCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	sparseMaxBytes := config.GetInt("hll-sparse-max-bytes", hll.DefaultSparseMaxBytes)
+	updated := false
+
+	err := client.DB.UpdateString(args[0], func(value []byte, exists bool) ([]byte, error) {
+		if !exists {
+			value = hll.New()
+			updated = true
+		} else if !hll.IsValid(value) {
+			return nil, hll.ErrInvalid
+		}
+
+		for _, element := range args[1:] {
+			var changed bool
+			var err error
+			if value, changed, err = hll.Add(value, []byte(element), sparseMaxBytes); err != nil {
+				return nil, err
+			}
+			updated = updated || changed
+		}
+		return value, nil
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	if updated {
+		return resp.Integer(1), nil
+	}
+	return resp.Integer(0), nil
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestPfAddCommand_Name(t *testing.T) {
+	cmd := NewPfAddCommand()
+	if cmd.Name() != "PFADD" {
+		t.Errorf("Expected command name to be 'PFADD', got %s", cmd.Name())
+	}
+}
+
+func TestPfAddCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("pfadd-key")
+	storeInstance.Delete("pfadd-empty")
+	storeInstance.Set("pfadd-string", "not a hyperloglog", 0)
+	cmd := NewPfAddCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "add to new key", args: []string{"pfadd-key", "a", "b", "c"}, expected: ":1\r\n"},
+		{name: "add existing elements", args: []string{"pfadd-key", "a", "b"}, expected: ":0\r\n"},
+		{name: "add new element", args: []string{"pfadd-key", "d"}, expected: ":1\r\n"},
+		{name: "create empty", args: []string{"pfadd-empty"}, expected: ":1\r\n"},
+		{name: "create empty again", args: []string{"pfadd-empty"}, expected: ":0\r\n"},
+		{name: "wrong type", args: []string{"pfadd-string", "a"}, errMsg: "Key is not a valid HyperLogLog string value."},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'pfadd' command"},
+	})
+
+	value, err := storeInstance.Get("pfadd-empty")
+	if err != nil || value[:4] != "HYLL" {
+		t.Errorf("Expected an empty HyperLogLog to be created, got (%q, %v)", value, err)
+	}
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/hll"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// PfCountCommand implements the PFCOUNT command
+type PfCountCommand struct {
+}
+
+// NewPfCountCommand creates a new PFCOUNT command
+func NewPfCountCommand() *PfCountCommand {
+	return &PfCountCommand{}
+}
+
+// Name returns the command name
+func (c *PfCountCommand) Name() string {
+	return "PFCOUNT"
+}
+
+// Execute handles the PFCOUNT command
+func (c *PfCountCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	if len(args) > 1 {
+		return c.countUnion(client.DB, args)
+	}
+
+	// With a single key the cardinality is cached in the value's header
+	var cardinality uint64
+	err := client.DB.UpdateString(args[0], func(value []byte, exists bool) ([]byte, error) {
+		if !exists {
+			return nil, nil
+		}
+		if !hll.IsValid(value) {
+			return nil, hll.ErrInvalid
+		}
+
+		var err error
+		cardinality, _, err = hll.Count(value)
+		return value, err
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(int64(cardinality)), nil
+}
+
+// countUnion estimates the cardinality of the union of several HyperLogLogs
+// without modifying them
+func (c *PfCountCommand) countUnion(db *store.Store, keys []string) (resp.Reply, error) {
+	var registers hll.Registers
+
+	for _, key := range keys {
+		var err error
+		readErr := db.ReadString(key, func(value []byte, exists bool) {
+			if !exists {
+				return
+			}
+			if !hll.IsValid(value) {
+				err = hll.ErrInvalid
+				return
+			}
+			err = registers.Merge(value)
+		})
+		if readErr != nil {
+			return resp.Reply{}, readErr
+		}
+		if err != nil {
+			return resp.Reply{}, err
+		}
+	}
+
+	return resp.Integer(int64(registers.Count())), nil
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestPfCountCommand_Name(t *testing.T) {
+	cmd := NewPfCountCommand()
+	if cmd.Name() != "PFCOUNT" {
+		t.Errorf("Expected command name to be 'PFCOUNT', got %s", cmd.Name())
+	}
+}
+
+func TestPfCountCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Delete("pfcount-a")
+	storeInstance.Delete("pfcount-b")
+	storeInstance.Set("pfcount-string", "not a hyperloglog", 0)
+
+	add := NewPfAddCommand()
+	execute(add, []string{"pfcount-a", "a", "b", "c", "d", "e", "f", "g"})
+	execute(add, []string{"pfcount-b", "f", "g", "h", "i"})
+
+	cmd := NewPfCountCommand()
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "single key", args: []string{"pfcount-a"}, expected: ":7\r\n"},
+		{name: "single key from cache", args: []string{"pfcount-a"}, expected: ":7\r\n"},
+		{name: "union of keys", args: []string{"pfcount-a", "pfcount-b"}, expected: ":9\r\n"},
+		{name: "missing key", args: []string{"pfcount-missing"}, expected: ":0\r\n"},
+		{name: "union with missing key", args: []string{"pfcount-b", "pfcount-missing"}, expected: ":4\r\n"},
+		{name: "wrong type", args: []string{"pfcount-string"}, errMsg: "Key is not a valid HyperLogLog string value."},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'pfcount' command"},
+	})
+
+	if _, err := storeInstance.Get("pfcount-missing"); err == nil {
+		t.Error("Expected PFCOUNT not to create missing keys")
+	}
+}
+
+func TestPfCountCommand_WrongTypeCode(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("pfcount-string", "not a hyperloglog", 0)
+
+	_, err := execute(NewPfCountCommand(), []string{"pfcount-string"})
+	if code := errors.ReplyCode(err); code != "WRONGTYPE" {
+		t.Errorf("Expected WRONGTYPE error code, got %q", code)
+	}
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/hll"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// PfMergeCommand implements the PFMERGE command
+type PfMergeCommand struct {
+}
+
+// NewPfMergeCommand creates a new PFMERGE command
+func NewPfMergeCommand() *PfMergeCommand {
+	return &PfMergeCommand{}
+}
+
+// Name returns the command name
+func (c *PfMergeCommand) Name() string {
+	return "PFMERGE"
+}
+
+// Execute handles the PFMERGE command
+func (c *PfMergeCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	// The destination takes part in the union along with the sources
+	var registers hll.Registers
+	useDense := false
+	for _, key := range args {
+		var err error
+		readErr := client.DB.ReadString(key, func(value []byte, exists bool) {
+			if !exists {
+				return
+			}
+			if !hll.IsValid(value) {
+				err = hll.ErrInvalid
+				return
+			}
+			useDense = useDense || hll.IsDense(value)
+			err = registers.Merge(value)
+		})
+		if readErr != nil {
+			return resp.Reply{}, readErr
+		}
+		if err != nil {
+			return resp.Reply{}, err
+		}
+	}
+
+	sparseMaxBytes := config.GetInt("hll-sparse-max-bytes", hll.DefaultSparseMaxBytes)
+	err := client.DB.UpdateString(args[0], func(value []byte, exists bool) ([]byte, error) {
+		if !exists {
+			value = hll.New()
+		} else if !hll.IsValid(value) {
+			return nil, hll.ErrInvalid
+		}
+		return registers.Store(value, useDense, sparseMaxBytes)
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.SimpleString("OK"), nil
+}
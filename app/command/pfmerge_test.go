@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestPfMergeCommand_Name(t *testing.T) {
+	cmd := NewPfMergeCommand()
+	if cmd.Name() != "PFMERGE" {
+		t.Errorf("Expected command name to be 'PFMERGE', got %s", cmd.Name())
+	}
+}
+
+func TestPfMergeCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	for _, key := range []string{"pfmerge-1", "pfmerge-2", "pfmerge-dest"} {
+		storeInstance.Delete(key)
+	}
+	storeInstance.Set("pfmerge-string", "not a hyperloglog", 0)
+
+	add := NewPfAddCommand()
+	execute(add, []string{"pfmerge-1", "foo", "bar", "zap", "a"})
+	execute(add, []string{"pfmerge-2", "a", "b", "c", "foo"})
+
+	cmd := NewPfMergeCommand()
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "merge into new key", args: []string{"pfmerge-dest", "pfmerge-1", "pfmerge-2"}, expected: "+OK\r\n"},
+		{name: "wrong type source", args: []string{"pfmerge-dest", "pfmerge-string"}, errMsg: "Key is not a valid HyperLogLog string value."},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'pfmerge' command"},
+	})
+
+	count := NewPfCountCommand()
+	runCommandTests(t, count, []commandTestCase{
+		{name: "merged cardinality", args: []string{"pfmerge-dest"}, expected: ":6\r\n"},
+	})
+
+	// Merging into an existing key includes its own registers
+	execute(add, []string{"pfmerge-dest", "extra"})
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "merge into existing key", args: []string{"pfmerge-dest", "pfmerge-1"}, expected: "+OK\r\n"},
+	})
+	runCommandTests(t, count, []commandTestCase{
+		{name: "cache invalidated after merge", args: []string{"pfmerge-dest"}, expected: ":7\r\n"},
+	})
+}
@@ -18,6 +18,6 @@ func (c *PingCommand) Name() string {
 }
 
 // Execute handles the PING command
-func (c *PingCommand) Execute(args []string) (string, error) {
-	return resp.FormatSimpleString("PONG"), nil
+func (c *PingCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	return resp.SimpleString("PONG"), nil
 }
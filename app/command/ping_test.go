@@ -33,7 +33,7 @@ func TestPingCommand_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := cmd.Execute(tt.args)
+			result, err := execute(cmd, tt.args)
 
 			// Check error
 			if err != nil {
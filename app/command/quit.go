@@ -0,0 +1,25 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// QuitCommand implements the QUIT command
+type QuitCommand struct{}
+
+// NewQuitCommand creates a new QUIT command
+func NewQuitCommand() *QuitCommand {
+	return &QuitCommand{}
+}
+
+// Name returns the command name
+func (c *QuitCommand) Name() string {
+	return "QUIT"
+}
+
+// Execute handles the QUIT command. The connection is closed once the reply
+// and any replies before it have been written.
+func (c *QuitCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	client.SetFlags(ClientCloseAfterReply)
+	return resp.SimpleString("OK"), nil
+}
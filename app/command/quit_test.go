@@ -0,0 +1,29 @@
+package command
+
+import (
+	"io"
+	"testing"
+)
+
+func TestQuitCommand_Name(t *testing.T) {
+	cmd := NewQuitCommand()
+	if cmd.Name() != "QUIT" {
+		t.Errorf("Expected command name to be 'QUIT', got %s", cmd.Name())
+	}
+}
+
+func TestQuitCommand_Execute(t *testing.T) {
+	cmd := NewQuitCommand()
+	client := NewClient(io.Discard)
+
+	reply, err := cmd.Execute(client, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := encodeRESP2(reply); result != "+OK\r\n" {
+		t.Errorf("Expected result %q, got %q", "+OK\r\n", result)
+	}
+	if client.Flags&ClientCloseAfterReply == 0 {
+		t.Errorf("Expected QUIT to mark the client to be closed after the reply")
+	}
+}
@@ -0,0 +1,28 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// RandomKeyCommand implements the RANDOMKEY command
+type RandomKeyCommand struct {
+}
+
+// NewRandomKeyCommand creates a new RANDOMKEY command
+func NewRandomKeyCommand() *RandomKeyCommand {
+	return &RandomKeyCommand{}
+}
+
+// Name returns the command name
+func (c *RandomKeyCommand) Name() string {
+	return "RANDOMKEY"
+}
+
+// Execute handles the RANDOMKEY command
+func (c *RandomKeyCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	key, ok := client.DB.RandomKey()
+	if !ok {
+		return resp.Null(), nil
+	}
+	return resp.BulkString(key), nil
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestRandomKeyCommand_Name(t *testing.T) {
+	cmd := NewRandomKeyCommand()
+	if cmd.Name() != "RANDOMKEY" {
+		t.Errorf("Expected command name to be 'RANDOMKEY', got %s", cmd.Name())
+	}
+}
+
+func TestRandomKeyCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Flush(false)
+	cmd := NewRandomKeyCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "empty store", args: []string{}, expected: "$-1\r\n"},
+	})
+
+	storeInstance.Set("randomkey-key", "value", 0)
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "single key", args: []string{}, expected: "$13\r\nrandomkey-key\r\n"},
+		{name: "extra arguments", args: []string{"x"}, errMsg: "wrong number of arguments for 'randomkey' command"},
+	})
+}
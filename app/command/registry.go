@@ -2,59 +2,222 @@ package command
 
 import (
 	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/dotslash21/redis-clone/app/acl"
+	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
 )
 
 // Command represents a Redis command
 type Command interface {
 	// Name returns the command name
 	Name() string
-	// Execute executes the command with given arguments
-	Execute(args []string) (string, error)
+	// Execute executes the command with given arguments on behalf of client
+	Execute(client *Client, args []string) (resp.Reply, error)
 }
 
-// Registry is a thread-safe registry of commands
+// Registry is a thread-safe registry of commands and their metadata, which
+// executes commands through a chain of middleware
 type Registry struct {
 	commands sync.Map
+	// renames maps the names of the commands renamed by rename-command to
+	// their new names, or to "" for disabled commands
+	renames map[string]string
+
+	mu         sync.RWMutex
+	middleware []Middleware
+	handler    Handler
+	// renamed holds the names of the commands renamed or disabled so far
+	renamed map[string]bool
+}
+
+// registeredCommand is a command along with its metadata
+type registeredCommand struct {
+	cmd  Command
+	meta *Metadata
 }
 
-// NewRegistry creates a new command registry
+// builtinMiddleware checks every command before it runs: that it exists,
+// the number of arguments, then authentication, then the ACL permissions
+var builtinMiddleware = []Middleware{checkExists, checkArity, requireAuth, checkACL}
+
+// NewRegistry creates a new command registry. The commands renamed or
+// disabled by rename-command at this point are registered under their new
+// name, or not at all, so they can't be found or run under their old one.
 func NewRegistry() *Registry {
-	return &Registry{}
+	r := &Registry{renames: config.RenamedCommands(), renamed: make(map[string]bool)}
+	r.Use()
+	return r
+}
+
+// Use appends middleware to the chain commands are executed through. The
+// middleware runs in the order it is added, before the built-in checks of the
+// command's existence, arguments, authentication and ACL permissions, so it
+// sees every command, including those the checks reject.
+func (r *Registry) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+	r.handler = chain(append(slices.Clone(r.middleware), builtinMiddleware...), runCommand)
 }
 
-// Register registers a command in the registry
+// Register registers a command in the registry, with its metadata from the
+// command table, or with defaultMetadata if the command isn't in the table
 func (r *Registry) Register(cmd Command) error {
+	meta, ok := commandTable[cmd.Name()]
+	if !ok {
+		meta = defaultMetadata
+	}
+	return r.RegisterWithMetadata(cmd, meta)
+}
+
+// RegisterWithMetadata registers a command in the registry with the given
+// metadata, such as a command of an embedding application. A command renamed
+// by rename-command is registered under its new name, and a disabled command
+// isn't registered. It's an error to register a command under a name already
+// taken, including when either command was renamed to it.
+func (r *Registry) RegisterWithMetadata(cmd Command, meta *Metadata) error {
 	name := cmd.Name()
-	// Add logging
-	fmt.Printf("Registering command: %q\n", name)
+	if newName, ok := r.renames[name]; ok {
+		r.mu.Lock()
+		r.renamed[name] = true
+		r.mu.Unlock()
+		if newName == "" {
+			log.Printf("Disabling command %q", name)
+			return nil
+		}
+		log.Printf("Renaming command %q to %q", name, newName)
+		name = newName
+	}
+	if existing, exists := r.commands.Load(name); exists {
+		msg := fmt.Sprintf("command %s already registered", name)
+		if previous := existing.(*registeredCommand).cmd.Name(); previous != name {
+			msg = fmt.Sprintf("command %s conflicts with %s, renamed to %s", cmd.Name(), previous, name)
+		} else if name != cmd.Name() {
+			msg = fmt.Sprintf("can't rename command %s to %s, which is already registered", cmd.Name(), name)
+		}
+		return errors.New(errors.ErrorTypeCommand, msg)
+	}
+	r.commands.Store(name, &registeredCommand{cmd: cmd, meta: meta})
+	return nil
+}
 
-	if _, exists := r.commands.Load(name); exists {
-		return errors.New(errors.ErrorTypeCommand, "command already registered")
+// CheckRenames returns an error if rename-command names a command that
+// wasn't registered, so that a misspelt name doesn't leave the command it
+// was meant to disable enabled
+func (r *Registry) CheckRenames() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, name := range slices.Sorted(maps.Keys(r.renames)) {
+		if !r.renamed[name] {
+			return errors.New(errors.ErrorTypeCommand, fmt.Sprintf("No such command in rename-command: %s", strings.ToLower(name)))
+		}
 	}
-	r.commands.Store(name, cmd)
 	return nil
 }
 
+// lookup returns a registered command by name
+func (r *Registry) lookup(name string) (*registeredCommand, error) {
+	entry, exists := r.commands.Load(name)
+	if !exists {
+		return nil, errors.New(errors.ErrorTypeCommand, "command not found")
+	}
+	return entry.(*registeredCommand), nil
+}
+
 // Get returns a command by name
 func (r *Registry) Get(name string) (Command, error) {
-	// Add logging
-	fmt.Printf("Looking up command: %q\n", name)
+	entry, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.cmd, nil
+}
 
-	cmd, exists := r.commands.Load(name)
+// Metadata returns the metadata of a command by name.
+func (r *Registry) Metadata(name string) (*Metadata, bool) {
+	entry, exists := r.commands.Load(name)
 	if !exists {
-		return nil, errors.New(errors.ErrorTypeCommand, "command not found")
+		return nil, false
 	}
-	return cmd.(Command), nil
+	return entry.(*registeredCommand).meta, true
 }
 
-// Execute executes a command by name with the given arguments
-func (r *Registry) Execute(name string, args []string) (string, error) {
-	cmd, err := r.Get(name)
-	if err != nil {
-		return "", err
+// CommandExists reports whether a command, or its subcommand if subcommand
+// isn't empty, is registered. Names are case insensitive.
+func (r *Registry) CommandExists(command, subcommand string) bool {
+	meta, exists := r.Metadata(strings.ToUpper(command))
+	if !exists {
+		return false
+	}
+	if subcommand == "" {
+		return true
+	}
+	_, exists = meta.Subcommands[strings.ToLower(subcommand)]
+	return exists
+}
+
+// Names returns the names of the registered commands in alphabetical order
+func (r *Registry) Names() []string {
+	var names []string
+	r.commands.Range(func(name, _ any) bool {
+		names = append(names, name.(string))
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
+
+// Execute executes a command by name with the given arguments on behalf of
+// client, through the middleware chain. The command must be registered, and
+// the number of arguments must match the arity of the command, or of its
+// subcommand. Clients that haven't authenticated may only run the commands
+// flagged no_auth, and others only the commands and keys their ACL user is
+// allowed to. Denials are recorded in the ACL log.
+func (r *Registry) Execute(client *Client, name string, args []string) (resp.Reply, error) {
+	// Unknown commands go through the chain too, for middleware to see them
+	var subcommand string
+	var meta *Metadata
+	entry, _ := r.lookup(name)
+	if entry != nil {
+		subcommand, meta = entry.meta.subcommand(args)
+	}
+
+	r.mu.RLock()
+	handler := r.handler
+	r.mu.RUnlock()
+	return handler(&Invocation{
+		Client:     client,
+		Name:       name,
+		Subcommand: subcommand,
+		Args:       args,
+		Metadata:   meta,
+		command:    entry,
+	})
+}
+
+// checkClientPermissions checks that the user client is authenticated as may
+// run the command name with args, described by meta, logging denials
+func checkClientPermissions(client *Client, meta *Metadata, name string, args []string) error {
+	user, ok := acl.GetACL().User(client.User)
+	if !ok {
+		// The user was deleted and the connection is being closed
+		return errors.NewWithCode("NOPERM", fmt.Sprintf("User %s has no permissions to run the '%s' command", client.User, strings.ToLower(name)))
+	}
+
+	reason, object, err := checkPermissions(user, meta, name, args)
+	if err == nil {
+		return nil
+	}
+	acl.GetACL().Log().Add(reason, object, client.User, client.Info())
+	if reason == acl.ReasonKey {
+		return errors.NewWithCode("NOPERM", "No permissions to access a key")
 	}
-	return cmd.Execute(args)
+	return err
 }
@@ -1,22 +1,26 @@
 package command
 
 import (
+	"io"
+	"slices"
 	"testing"
 
+	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
 	"github.com/dotslash21/redis-clone/app/store"
 )
 
 // MockCommand implements the Command interface for testing
 type MockCommand struct {
 	name           string
-	executeResult  string
+	executeResult  resp.Reply
 	executeError   error
 	executeCount   int
 	executeArgSets [][]string
 }
 
-func NewMockCommand(name string, result string, err error) *MockCommand {
+func NewMockCommand(name string, result resp.Reply, err error) *MockCommand {
 	return &MockCommand{
 		name:           name,
 		executeResult:  result,
@@ -29,7 +33,7 @@ func (c *MockCommand) Name() string {
 	return c.name
 }
 
-func (c *MockCommand) Execute(args []string) (string, error) {
+func (c *MockCommand) Execute(client *Client, args []string) (resp.Reply, error) {
 	c.executeCount++
 	c.executeArgSets = append(c.executeArgSets, args)
 	return c.executeResult, c.executeError
@@ -39,21 +43,21 @@ func TestRegistry_Register(t *testing.T) {
 	registry := NewRegistry()
 
 	// Register a valid command
-	mockCmd := NewMockCommand("TEST", "result", nil)
+	mockCmd := NewMockCommand("TEST", resp.SimpleString("result"), nil)
 	err := registry.Register(mockCmd)
 	if err != nil {
 		t.Errorf("Expected no error when registering a new command, got %v", err)
 	}
 
 	// Try to register the same command again (should fail)
-	mockCmd2 := NewMockCommand("TEST", "another result", nil)
+	mockCmd2 := NewMockCommand("TEST", resp.SimpleString("another result"), nil)
 	err = registry.Register(mockCmd2)
 	if err == nil {
 		t.Errorf("Expected error when registering a duplicate command, got nil")
 	}
 
 	// Register a different command
-	mockCmd3 := NewMockCommand("ANOTHER", "result", nil)
+	mockCmd3 := NewMockCommand("ANOTHER", resp.SimpleString("result"), nil)
 	err = registry.Register(mockCmd3)
 	if err != nil {
 		t.Errorf("Expected no error when registering a different command, got %v", err)
@@ -62,7 +66,7 @@ func TestRegistry_Register(t *testing.T) {
 
 func TestRegistry_Get(t *testing.T) {
 	registry := NewRegistry()
-	mockCmd := NewMockCommand("TEST", "result", nil)
+	mockCmd := NewMockCommand("TEST", resp.SimpleString("result"), nil)
 	registry.Register(mockCmd)
 
 	tests := []struct {
@@ -128,17 +132,16 @@ func TestRegistry_Execute(t *testing.T) {
 	registry := NewRegistry()
 
 	// Register commands
-	successCmd := NewMockCommand("SUCCESS", "ok", nil)
+	successCmd := NewMockCommand("SUCCESS", resp.SimpleString("ok"), nil)
 	registry.Register(successCmd)
 
-	errorCmd := NewMockCommand("ERROR", "", errors.New(errors.ErrorTypeCommand, "test error"))
+	errorCmd := NewMockCommand("ERROR", resp.Reply{}, errors.New(errors.ErrorTypeCommand, "test error"))
 	registry.Register(errorCmd)
 
 	// Set up a real command for end-to-end testing
-	storeInstance := store.GetStore()
-	setCmd := NewSetCommand(storeInstance)
+	setCmd := NewSetCommand()
 	registry.Register(setCmd)
-	getCmd := NewGetCommand(storeInstance)
+	getCmd := NewGetCommand()
 	registry.Register(getCmd)
 
 	tests := []struct {
@@ -152,7 +155,7 @@ func TestRegistry_Execute(t *testing.T) {
 			name:        "execute successful command",
 			commandName: "SUCCESS",
 			args:        []string{"arg1", "arg2"},
-			expected:    "ok",
+			expected:    "+ok\r\n",
 			errMsg:      "",
 		},
 		{
@@ -180,7 +183,7 @@ func TestRegistry_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := registry.Execute(tt.commandName, tt.args)
+			reply, err := registry.Execute(NewClient(io.Discard), tt.commandName, tt.args)
 
 			// Check error
 			if tt.errMsg != "" {
@@ -204,20 +207,178 @@ func TestRegistry_Execute(t *testing.T) {
 			}
 
 			// Check result
-			if result != tt.expected {
+			if tt.errMsg != "" {
+				return
+			}
+			if result := encodeRESP2(reply); result != tt.expected {
 				t.Errorf("Expected result %q, got %q", tt.expected, result)
 			}
 		})
 	}
 
 	// Finally, verify the end-to-end test by getting the value that was set
-	result, err := registry.Execute("GET", []string{"testkey"})
+	reply, err := registry.Execute(NewClient(io.Discard), "GET", []string{"testkey"})
 	if err != nil {
 		t.Errorf("Expected no error from GET after SET, got %v", err)
 		return
 	}
 	expectedResult := "$9\r\ntestvalue\r\n"
-	if result != expectedResult {
+	if result := encodeRESP2(reply); result != expectedResult {
 		t.Errorf("Expected result %q from GET after SET, got %q", expectedResult, result)
 	}
 }
+
+func TestRegistry_ExecuteChecksArity(t *testing.T) {
+	registry := NewRegistry()
+	getCmd := NewMockCommand("GET", resp.SimpleString("ok"), nil)
+	if err := registry.RegisterWithMetadata(getCmd, commandTable["GET"]); err != nil {
+		t.Fatalf("Failed to register GET: %v", err)
+	}
+	registry.Register(NewClientCommand(NewClients()))
+	client := NewClient(io.Discard)
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		errMsg  string
+	}{
+		{name: "too few", command: "GET", args: nil, errMsg: "wrong number of arguments for 'get' command"},
+		{name: "too many", command: "GET", args: []string{"a", "b"}, errMsg: "wrong number of arguments for 'get' command"},
+		{name: "subcommand", command: "CLIENT", args: []string{"ID", "x"}, errMsg: "wrong number of arguments for 'client|id' command"},
+		{name: "container", command: "CLIENT", args: nil, errMsg: "wrong number of arguments for 'client' command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := registry.Execute(client, tt.command, tt.args); err == nil || err.Error() != tt.errMsg {
+				t.Errorf("Expected %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	if getCmd.executeCount != 0 {
+		t.Errorf("Expected GET not to run with the wrong number of arguments")
+	}
+	if _, err := registry.Execute(client, "GET", []string{"key"}); err != nil || getCmd.executeCount != 1 {
+		t.Errorf("Expected GET to run with one argument, got %v", err)
+	}
+}
+
+func TestRegistry_ExecuteRequiresAuth(t *testing.T) {
+	config.SetConfig("requirepass", "secret")
+	defer config.SetConfig("requirepass", "")
+
+	registry := NewRegistry()
+	registry.Register(NewPingCommand())
+	registry.Register(NewAuthCommand())
+	registry.Register(NewQuitCommand())
+	client := NewClient(io.Discard)
+
+	if _, err := registry.Execute(client, "PING", nil); err == nil || errors.ReplyCode(err) != "NOAUTH" || err.Error() != "Authentication required." {
+		t.Errorf("Expected NOAUTH before authenticating, got %v", err)
+	}
+	if _, err := registry.Execute(client, "MISSING", nil); err == nil || err.Error() != "command not found" {
+		t.Errorf("Expected unknown commands to be reported as such, got %v", err)
+	}
+	if _, err := registry.Execute(client, "AUTH", []string{"secret"}); err != nil {
+		t.Fatalf("Expected AUTH to be allowed, got %v", err)
+	}
+	if _, err := registry.Execute(client, "PING", nil); err != nil {
+		t.Errorf("Expected PING to be allowed once authenticated, got %v", err)
+	}
+
+	other := NewClient(io.Discard)
+	if _, err := registry.Execute(other, "QUIT", nil); err != nil {
+		t.Errorf("Expected QUIT to be allowed before authenticating, got %v", err)
+	}
+}
+
+func TestRegistry_RenameCommand(t *testing.T) {
+	config.RenameCommand("FLUSHALL", "")
+	config.RenameCommand("GET", "FETCH")
+	defer config.RenameCommand("FLUSHALL", "FLUSHALL")
+	defer config.RenameCommand("GET", "GET")
+
+	registry := NewRegistry()
+	if err := registry.CheckRenames(); err == nil || err.Error() != "No such command in rename-command: flushall" {
+		t.Errorf("Expected the renamed commands to be missing, got %v", err)
+	}
+	registry.Register(NewGetCommand())
+	registry.Register(NewFlushAllCommand(store.GetDatabases()))
+	registry.Register(NewSetCommand())
+	if err := registry.CheckRenames(); err != nil {
+		t.Errorf("Expected every renamed command to be registered, got %v", err)
+	}
+
+	for _, name := range []string{"GET", "FLUSHALL"} {
+		if _, err := registry.Get(name); err == nil {
+			t.Errorf("Expected %s not to be found under its old name", name)
+		}
+		if _, err := registry.Execute(NewClient(io.Discard), name, []string{"key"}); err == nil || err.Error() != "command not found" {
+			t.Errorf("Expected %s not to run under its old name, got %v", name, err)
+		}
+	}
+	if names := registry.Names(); !slices.Equal(names, []string{"FETCH", "SET"}) {
+		t.Errorf("Expected FETCH and SET, got %q", names)
+	}
+
+	registry.Execute(NewClient(io.Discard), "SET", []string{"renamed", "value"})
+	reply, err := registry.Execute(NewClient(io.Discard), "FETCH", []string{"renamed"})
+	if err != nil || encodeRESP2(reply) != "$5\r\nvalue\r\n" {
+		t.Errorf("Expected GET to run as FETCH, got %q (%v)", encodeRESP2(reply), err)
+	}
+
+	// Registries created before a rename aren't affected by it
+	config.RenameCommand("SET", "")
+	defer config.RenameCommand("SET", "SET")
+	if _, err := registry.Get("SET"); err != nil {
+		t.Errorf("Expected SET to still be registered, got %v", err)
+	}
+}
+
+func TestRegistry_RenameConflict(t *testing.T) {
+	config.RenameCommand("GET", "SET")
+	defer config.RenameCommand("GET", "GET")
+
+	registry := NewRegistry()
+	if err := registry.Register(NewGetCommand()); err != nil {
+		t.Fatalf("Expected GET to be registered as SET, got %v", err)
+	}
+	if err := registry.Register(NewSetCommand()); err == nil || err.Error() != "command SET conflicts with GET, renamed to SET" {
+		t.Errorf("Expected SET to conflict with the renamed GET, got %v", err)
+	}
+
+	registry = NewRegistry()
+	registry.Register(NewSetCommand())
+	if err := registry.Register(NewGetCommand()); err == nil || err.Error() != "can't rename command GET to SET, which is already registered" {
+		t.Errorf("Expected renaming GET to SET to fail, got %v", err)
+	}
+}
+
+func TestRegistry_CommandExists(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGetCommand())
+	registry.Register(NewClientCommand(NewClients()))
+
+	tests := []struct {
+		command    string
+		subcommand string
+		expected   bool
+	}{
+		{command: "get", expected: true},
+		{command: "GET", expected: true},
+		{command: "set", expected: false},
+		{command: "client", subcommand: "kill", expected: true},
+		{command: "client", subcommand: "nosuch", expected: false},
+		{command: "get", subcommand: "sub", expected: false},
+	}
+	for _, tt := range tests {
+		if result := registry.CommandExists(tt.command, tt.subcommand); result != tt.expected {
+			t.Errorf("Expected CommandExists(%q, %q) to be %v", tt.command, tt.subcommand, tt.expected)
+		}
+	}
+
+	if names := registry.Names(); len(names) != 2 || names[0] != "CLIENT" || names[1] != "GET" {
+		t.Errorf("Expected CLIENT and GET, got %q", names)
+	}
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// RenameCommand implements the RENAME and RENAMENX commands
+type RenameCommand struct {
+	nx bool
+}
+
+// NewRenameCommand creates a new RENAME command
+func NewRenameCommand() *RenameCommand {
+	return &RenameCommand{}
+}
+
+// NewRenameNxCommand creates a new RENAMENX command, which does not overwrite an existing key
+func NewRenameNxCommand() *RenameCommand {
+	return &RenameCommand{nx: true}
+}
+
+// Name returns the command name
+func (c *RenameCommand) Name() string {
+	if c.nx {
+		return "RENAMENX"
+	}
+	return "RENAME"
+}
+
+// Execute handles the RENAME and RENAMENX commands
+func (c *RenameCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	renamed, err := client.DB.Rename(args[0], args[1], c.nx)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	if !c.nx {
+		return resp.SimpleString("OK"), nil
+	}
+	if renamed {
+		return resp.Integer(1), nil
+	}
+	return resp.Integer(0), nil
+}
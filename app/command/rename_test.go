@@ -0,0 +1,49 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestRenameCommand_Name(t *testing.T) {
+	if name := NewRenameCommand().Name(); name != "RENAME" {
+		t.Errorf("Expected command name to be 'RENAME', got %s", name)
+	}
+	if name := NewRenameNxCommand().Name(); name != "RENAMENX" {
+		t.Errorf("Expected command name to be 'RENAMENX', got %s", name)
+	}
+}
+
+func TestRenameCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("rename-src", "value", time.Hour)
+	storeInstance.Delete("rename-dst")
+	storeInstance.Delete("rename-missing")
+
+	runCommandTests(t, NewRenameCommand(), []commandTestCase{
+		{name: "rename", args: []string{"rename-src", "rename-dst"}, expected: "+OK\r\n"},
+		{name: "missing source", args: []string{"rename-missing", "rename-dst"}, errMsg: "no such key"},
+		{name: "same key", args: []string{"rename-dst", "rename-dst"}, expected: "+OK\r\n"},
+		{name: "wrong number of arguments", args: []string{"rename-dst"}, errMsg: "wrong number of arguments for 'rename' command"},
+	})
+
+	if value, err := storeInstance.Get("rename-dst"); err != nil || value != "value" {
+		t.Errorf("Expected rename-dst to hold the value, got (%q, %v)", value, err)
+	}
+	if storeInstance.Exists("rename-src") {
+		t.Error("Expected rename-src to be removed")
+	}
+
+	storeInstance.Set("renamenx-src", "value", 0)
+	storeInstance.Set("renamenx-existing", "value", 0)
+	storeInstance.Delete("renamenx-dst")
+
+	runCommandTests(t, NewRenameNxCommand(), []commandTestCase{
+		{name: "onto existing key", args: []string{"renamenx-src", "renamenx-existing"}, expected: ":0\r\n"},
+		{name: "onto new key", args: []string{"renamenx-src", "renamenx-dst"}, expected: ":1\r\n"},
+		{name: "missing source", args: []string{"renamenx-src", "renamenx-dst"}, errMsg: "no such key"},
+		{name: "wrong number of arguments", args: []string{"a", "b", "c"}, errMsg: "wrong number of arguments for 'renamenx' command"},
+	})
+}
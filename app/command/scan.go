@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// scanDefaultCount is the number of keys SCAN visits per call without COUNT
+const scanDefaultCount = 10
+
+// scanTypeNames are the type names accepted by the TYPE option of SCAN
+var scanTypeNames = []string{"string", "list", "set", "zset", "hash", "stream"}
+
+// ScanCommand implements the SCAN command
+type ScanCommand struct {
+}
+
+// NewScanCommand creates a new SCAN command
+func NewScanCommand() *ScanCommand {
+	return &ScanCommand{}
+}
+
+// Name returns the command name
+func (c *ScanCommand) Name() string {
+	return "SCAN"
+}
+
+// Execute handles the SCAN command
+func (c *ScanCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	cursor, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "invalid cursor")
+	}
+
+	count := scanDefaultCount
+	pattern := "*"
+	typeName := ""
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+		switch {
+		case strings.EqualFold(args[i], "COUNT"):
+			n, err := parseInteger(args[i+1])
+			if err != nil {
+				return resp.Reply{}, err
+			}
+			if n < 1 {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+			}
+			count = int(min(n, int64(1<<31-1)))
+		case strings.EqualFold(args[i], "MATCH"):
+			pattern = args[i+1]
+		case strings.EqualFold(args[i], "TYPE"):
+			typeName = strings.ToLower(args[i+1])
+			if !slices.Contains(scanTypeNames, typeName) {
+				return resp.Reply{}, errors.New(errors.ErrorTypeCommand, fmt.Sprintf("unknown type name '%s'", args[i+1]))
+			}
+		default:
+			return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "syntax error")
+		}
+	}
+
+	keys, next := client.DB.Scan(cursor, count, pattern, typeName)
+	return resp.Array([]resp.Reply{
+		resp.BulkString(strconv.FormatUint(next, 10)),
+		resp.BulkStrings(keys),
+	}), nil
+}
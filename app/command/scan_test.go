@@ -0,0 +1,41 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+func TestScanCommand_Name(t *testing.T) {
+	cmd := NewScanCommand()
+	if cmd.Name() != "SCAN" {
+		t.Errorf("Expected command name to be 'SCAN', got %s", cmd.Name())
+	}
+}
+
+func TestScanCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Flush(false)
+	storeInstance.Set("scan-string", "value", 0)
+	zset := types.NewSortedSet()
+	zset.Add("member", 1)
+	storeInstance.SetZSet("scan-zset", zset)
+	cmd := NewScanCommand()
+
+	// A COUNT larger than the keyspace completes the scan in a single call
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "match", args: []string{"0", "MATCH", "*-string", "COUNT", "1000"}, expected: "*2\r\n$1\r\n0\r\n*1\r\n$11\r\nscan-string\r\n"},
+		{name: "type filter", args: []string{"0", "COUNT", "1000", "TYPE", "ZSET"}, expected: "*2\r\n$1\r\n0\r\n*1\r\n$9\r\nscan-zset\r\n"},
+		{name: "no matches", args: []string{"0", "match", "missing*", "count", "1000"}, expected: "*2\r\n$1\r\n0\r\n*0\r\n"},
+		{name: "type without keys", args: []string{"0", "COUNT", "1000", "TYPE", "hash"}, expected: "*2\r\n$1\r\n0\r\n*0\r\n"},
+		{name: "invalid cursor", args: []string{"abc"}, errMsg: "invalid cursor"},
+		{name: "negative cursor", args: []string{"-1"}, errMsg: "invalid cursor"},
+		{name: "count not an integer", args: []string{"0", "COUNT", "x"}, errMsg: "value is not an integer or out of range"},
+		{name: "count zero", args: []string{"0", "COUNT", "0"}, errMsg: "syntax error"},
+		{name: "unknown type", args: []string{"0", "TYPE", "foo"}, errMsg: "unknown type name 'foo'"},
+		{name: "option without value", args: []string{"0", "MATCH"}, errMsg: "syntax error"},
+		{name: "unknown option", args: []string{"0", "FOO", "bar"}, errMsg: "syntax error"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'scan' command"},
+	})
+}
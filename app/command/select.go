@@ -0,0 +1,36 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// SelectCommand implements the SELECT command
+type SelectCommand struct {
+	dbs *store.Databases
+}
+
+// NewSelectCommand creates a new SELECT command
+func NewSelectCommand(dbs *store.Databases) *SelectCommand {
+	return &SelectCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *SelectCommand) Name() string {
+	return "SELECT"
+}
+
+// Execute handles the SELECT command
+func (c *SelectCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	index, err := parseInteger(args[0])
+	if err != nil {
+		return resp.Reply{}, err
+	}
+	db, err := c.dbs.DB(index)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	client.SelectDB(db)
+	return resp.SimpleString("OK"), nil
+}
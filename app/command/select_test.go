@@ -0,0 +1,49 @@
+package command
+
+import (
+	"io"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestSelectCommand_Name(t *testing.T) {
+	cmd := NewSelectCommand(store.GetDatabases())
+	if cmd.Name() != "SELECT" {
+		t.Errorf("Expected command name to be 'SELECT', got %s", cmd.Name())
+	}
+}
+
+func TestSelectCommand_Execute(t *testing.T) {
+	cmd := NewSelectCommand(store.GetDatabases())
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "select db", args: []string{"3"}, expected: "+OK\r\n"},
+		{name: "out of range", args: []string{"16"}, errMsg: "DB index is out of range"},
+		{name: "negative", args: []string{"-1"}, errMsg: "DB index is out of range"},
+		{name: "not an integer", args: []string{"x"}, errMsg: "value is not an integer or out of range"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'select' command"},
+	})
+}
+
+func TestSelectCommand_ChangesClientDatabase(t *testing.T) {
+	client := NewClient(io.Discard)
+	if _, err := NewSelectCommand(store.GetDatabases()).Execute(client, []string{"5"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.DB.ID() != 5 {
+		t.Fatalf("Expected database 5 to be selected, got %d", client.DB.ID())
+	}
+
+	store.GetStore().Delete("select-key")
+	if _, err := NewSetCommand().Execute(client, []string{"select-key", "value"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.GetStore().Exists("select-key") {
+		t.Error("Expected the key to be set in the selected database, not database 0")
+	}
+	if !client.DB.Exists("select-key") {
+		t.Error("Expected the key to be set in database 5")
+	}
+	client.DB.Delete("select-key")
+}
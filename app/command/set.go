@@ -7,17 +7,15 @@ import (
 
 	"github.com/dotslash21/redis-clone/app/errors"
 	"github.com/dotslash21/redis-clone/app/resp"
-	"github.com/dotslash21/redis-clone/app/store"
 )
 
 // SetCommand implements the SET command
 type SetCommand struct {
-	store *store.Store
 }
 
 // NewSetCommand creates a new SET command
-func NewSetCommand(s *store.Store) *SetCommand {
-	return &SetCommand{store: s}
+func NewSetCommand() *SetCommand {
+	return &SetCommand{}
 }
 
 // Name returns the command name
@@ -26,11 +24,7 @@ func (c *SetCommand) Name() string {
 }
 
 // Execute handles the SET command
-func (c *SetCommand) Execute(args []string) (string, error) {
-	if len(args) < 2 {
-		return "", errors.New(errors.ErrorTypeCommand, "wrong number of arguments for 'set' command")
-	}
-
+func (c *SetCommand) Execute(client *Client, args []string) (resp.Reply, error) {
 	key := args[0]
 	value := args[1]
 	var ttl time.Duration
@@ -40,12 +34,12 @@ func (c *SetCommand) Execute(args []string) (string, error) {
 		var err error
 		ttl, err = parseExpiry(args[2:])
 		if err != nil {
-			return "", err
+			return resp.Reply{}, err
 		}
 	}
 
-	c.store.Set(key, value, ttl)
-	return resp.FormatSimpleString("OK"), nil
+	client.DB.Set(key, value, ttl)
+	return resp.SimpleString("OK"), nil
 }
 
 // parseExpiry parses expiry options (EX seconds or PX milliseconds)
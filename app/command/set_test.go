@@ -9,7 +9,7 @@ import (
 )
 
 func TestSetCommand_Name(t *testing.T) {
-	cmd := NewSetCommand(store.GetStore())
+	cmd := NewSetCommand()
 	if cmd.Name() != "SET" {
 		t.Errorf("Expected command name to be 'SET', got %s", cmd.Name())
 	}
@@ -17,7 +17,7 @@ func TestSetCommand_Name(t *testing.T) {
 
 func TestSetCommand_Execute(t *testing.T) {
 	storeInstance := store.GetStore()
-	cmd := NewSetCommand(storeInstance)
+	cmd := NewSetCommand()
 
 	tests := []struct {
 		name     string
@@ -116,7 +116,7 @@ func TestSetCommand_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := cmd.Execute(tt.args)
+			result, err := execute(cmd, tt.args)
 
 			// Check error
 			if tt.errMsg != "" {
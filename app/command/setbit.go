@@ -0,0 +1,52 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// SetBitCommand implements the SETBIT command
+type SetBitCommand struct {
+}
+
+// NewSetBitCommand creates a new SETBIT command
+func NewSetBitCommand() *SetBitCommand {
+	return &SetBitCommand{}
+}
+
+// Name returns the command name
+func (c *SetBitCommand) Name() string {
+	return "SETBIT"
+}
+
+// Execute handles the SETBIT command
+func (c *SetBitCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	offset, err := parseBitOffset(args[1], false, 1)
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	if args[2] != "0" && args[2] != "1" {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "bit is not an integer or out of range")
+	}
+	on := args[2] == "1"
+
+	var original byte
+	err = client.DB.UpdateString(args[0], func(value []byte, exists bool) ([]byte, error) {
+		value = growBytes(value, int(offset>>3)+1)
+		original = getBit(value, offset)
+
+		mask := byte(1 << (7 - offset&7))
+		if on {
+			value[offset>>3] |= mask
+		} else {
+			value[offset>>3] &^= mask
+		}
+		return value, nil
+	})
+	if err != nil {
+		return resp.Reply{}, err
+	}
+
+	return resp.Integer(int64(original)), nil
+}
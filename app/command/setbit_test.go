@@ -0,0 +1,77 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestSetBitCommand_Name(t *testing.T) {
+	cmd := NewSetBitCommand()
+	if cmd.Name() != "SETBIT" {
+		t.Errorf("Expected command name to be 'SETBIT', got %s", cmd.Name())
+	}
+}
+
+func TestSetBitCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	cmd := NewSetBitCommand()
+	storeInstance.Delete("setbit-key")
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "set bit on new key", args: []string{"setbit-key", "7", "1"}, expected: ":0\r\n"},
+		{name: "set same bit again", args: []string{"setbit-key", "7", "1"}, expected: ":1\r\n"},
+		{name: "clear bit", args: []string{"setbit-key", "7", "0"}, expected: ":1\r\n"},
+		{name: "offset past end grows value", args: []string{"setbit-key", "100", "1"}, expected: ":0\r\n"},
+		{name: "negative offset", args: []string{"setbit-key", "-1", "1"}, errMsg: "bit offset is not an integer or out of range"},
+		{name: "offset too large", args: []string{"setbit-key", "4294967296", "1"}, errMsg: "bit offset is not an integer or out of range"},
+		{name: "invalid bit value", args: []string{"setbit-key", "1", "2"}, errMsg: "bit is not an integer or out of range"},
+		{name: "wrong number of arguments", args: []string{"setbit-key", "1"}, errMsg: "wrong number of arguments for 'setbit' command"},
+	})
+
+	value, err := storeInstance.Get("setbit-key")
+	if err != nil {
+		t.Fatalf("Expected setbit-key to exist, got error: %v", err)
+	}
+	if len(value) != 13 {
+		t.Errorf("Expected value to grow to 13 bytes, got %d", len(value))
+	}
+	if value[12] != 0x08 {
+		t.Errorf("Expected last byte to be 0x08, got %#x", value[12])
+	}
+}
+
+func TestSetBitCommand_PreservesExistingValue(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("setbit-existing", "`", 0)
+	cmd := NewSetBitCommand()
+
+	// "`" is 0x60; setting bit 7 turns it into "a" (0x61)
+	if _, err := execute(cmd, []string{"setbit-existing", "7", "1"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	value, _ := storeInstance.Get("setbit-existing")
+	if value != "a" {
+		t.Errorf("Expected value %q, got %q", "a", value)
+	}
+}
+
+func TestGrowBytes(t *testing.T) {
+	var b []byte
+	for size := 1; size <= 1<<16; size *= 2 {
+		b = growBytes(b, size)
+		if len(b) != size {
+			t.Fatalf("Expected length %d, got %d", size, len(b))
+		}
+	}
+
+	// Growing reuses spare capacity and zero-fills the new tail
+	b = b[:10]
+	b[9] = 0xff
+	b = b[:5]
+	b = growBytes(b, 10)
+	if b[9] != 0 {
+		t.Errorf("Expected grown tail to be zeroed, got %#x", b[9])
+	}
+}
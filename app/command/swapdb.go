@@ -0,0 +1,41 @@
+package command
+
+import (
+	"strconv"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+// SwapDBCommand implements the SWAPDB command
+type SwapDBCommand struct {
+	dbs *store.Databases
+}
+
+// NewSwapDBCommand creates a new SWAPDB command
+func NewSwapDBCommand(dbs *store.Databases) *SwapDBCommand {
+	return &SwapDBCommand{dbs: dbs}
+}
+
+// Name returns the command name
+func (c *SwapDBCommand) Name() string {
+	return "SWAPDB"
+}
+
+// Execute handles the SWAPDB command
+func (c *SwapDBCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	first, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "invalid first DB index")
+	}
+	second, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return resp.Reply{}, errors.New(errors.ErrorTypeCommand, "invalid second DB index")
+	}
+
+	if err := c.dbs.Swap(first, second); err != nil {
+		return resp.Reply{}, err
+	}
+	return resp.SimpleString("OK"), nil
+}
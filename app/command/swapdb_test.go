@@ -0,0 +1,37 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestSwapDBCommand_Name(t *testing.T) {
+	cmd := NewSwapDBCommand(store.GetDatabases())
+	if cmd.Name() != "SWAPDB" {
+		t.Errorf("Expected command name to be 'SWAPDB', got %s", cmd.Name())
+	}
+}
+
+func TestSwapDBCommand_Execute(t *testing.T) {
+	dbs := store.GetDatabases()
+	db14, _ := dbs.DB(14)
+	db15, _ := dbs.DB(15)
+	db14.Set("swapdb-key", "value", 0)
+	db15.Delete("swapdb-key")
+	cmd := NewSwapDBCommand(dbs)
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "swap", args: []string{"14", "15"}, expected: "+OK\r\n"},
+		{name: "same db", args: []string{"15", "15"}, expected: "+OK\r\n"},
+		{name: "out of range", args: []string{"0", "16"}, errMsg: "DB index is out of range"},
+		{name: "invalid first index", args: []string{"x", "1"}, errMsg: "invalid first DB index"},
+		{name: "invalid second index", args: []string{"1", "x"}, errMsg: "invalid second DB index"},
+		{name: "wrong number of arguments", args: []string{"1"}, errMsg: "wrong number of arguments for 'swapdb' command"},
+	})
+
+	if db14.Exists("swapdb-key") || !db15.Exists("swapdb-key") {
+		t.Error("Expected the key to move to database 15 with the swap")
+	}
+	db15.Delete("swapdb-key")
+}
@@ -0,0 +1,32 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// TouchCommand implements the TOUCH command
+type TouchCommand struct {
+}
+
+// NewTouchCommand creates a new TOUCH command
+func NewTouchCommand() *TouchCommand {
+	return &TouchCommand{}
+}
+
+// Name returns the command name
+func (c *TouchCommand) Name() string {
+	return "TOUCH"
+}
+
+// Execute handles the TOUCH command. Access times are not tracked, so touching
+// a key only checks that it exists.
+func (c *TouchCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	count := 0
+	for _, key := range args {
+		if client.DB.Exists(key) {
+			count++
+		}
+	}
+
+	return resp.Integer(int64(count)), nil
+}
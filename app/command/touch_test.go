@@ -0,0 +1,26 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestTouchCommand_Name(t *testing.T) {
+	cmd := NewTouchCommand()
+	if cmd.Name() != "TOUCH" {
+		t.Errorf("Expected command name to be 'TOUCH', got %s", cmd.Name())
+	}
+}
+
+func TestTouchCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("touch-key", "value", 0)
+	storeInstance.Delete("touch-missing")
+	cmd := NewTouchCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "existing and missing keys", args: []string{"touch-key", "touch-missing"}, expected: ":1\r\n"},
+		{name: "no arguments", args: []string{}, errMsg: "wrong number of arguments for 'touch' command"},
+	})
+}
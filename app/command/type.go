@@ -0,0 +1,28 @@
+package command
+
+import (
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// TypeCommand implements the TYPE command
+type TypeCommand struct {
+}
+
+// NewTypeCommand creates a new TYPE command
+func NewTypeCommand() *TypeCommand {
+	return &TypeCommand{}
+}
+
+// Name returns the command name
+func (c *TypeCommand) Name() string {
+	return "TYPE"
+}
+
+// Execute handles the TYPE command
+func (c *TypeCommand) Execute(client *Client, args []string) (resp.Reply, error) {
+	valueType, ok := client.DB.Type(args[0])
+	if !ok {
+		return resp.SimpleString("none"), nil
+	}
+	return resp.SimpleString(valueType.String()), nil
+}
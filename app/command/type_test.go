@@ -0,0 +1,30 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/store"
+)
+
+func TestTypeCommand_Name(t *testing.T) {
+	cmd := NewTypeCommand()
+	if cmd.Name() != "TYPE" {
+		t.Errorf("Expected command name to be 'TYPE', got %s", cmd.Name())
+	}
+}
+
+func TestTypeCommand_Execute(t *testing.T) {
+	storeInstance := store.GetStore()
+	storeInstance.Set("type-string", "value", 0)
+	storeInstance.Delete("type-zset")
+	storeInstance.Delete("type-missing")
+	execute(NewGeoAddCommand(), []string{"type-zset", "13.361389", "38.115556", "Palermo"})
+	cmd := NewTypeCommand()
+
+	runCommandTests(t, cmd, []commandTestCase{
+		{name: "string", args: []string{"type-string"}, expected: "+string\r\n"},
+		{name: "sorted set", args: []string{"type-zset"}, expected: "+zset\r\n"},
+		{name: "missing key", args: []string{"type-missing"}, expected: "+none\r\n"},
+		{name: "wrong number of arguments", args: []string{"a", "b"}, errMsg: "wrong number of arguments for 'type' command"},
+	})
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// maxIncludeDepth is how deeply config files may include one another, which
+// stops a file that includes itself
+const maxIncludeDepth = 16
+
+// accumulatingDirectives are the directives that may be repeated, each
+// adding to the setting instead of replacing it, such as one
+// client-output-buffer-limit line per client class
+var accumulatingDirectives = map[string]bool{
+	"client-output-buffer-limit": true,
+}
+
+// Messages of the errors in directives, as in Redis
+const (
+	errBadDirective = "Bad directive or wrong number of arguments"
+	errUnbalanced   = "Unbalanced quotes in configuration line"
+)
+
+// errTooManyIncludes is returned for includes nested more than
+// maxIncludeDepth deep
+var errTooManyIncludes = errors.New("Too many nested includes")
+
+// FileError reports a directive of a config file, or of the command-line
+// overrides, that couldn't be applied
+type FileError struct {
+	// Source is the path of the file, or empty for the command line
+	Source string
+	// Line is the line of the directive in the file, or the number of the
+	// option on the command line, starting from 1
+	Line int
+	// Text is the directive
+	Text string
+	// Msg says what is wrong with it
+	Msg string
+}
+
+// Error describes the error the way Redis does
+func (e *FileError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("Reading the command line, at option %d\n>>> '%s'\n%s", e.Line, e.Text, e.Msg)
+	}
+	return fmt.Sprintf("Reading the configuration file %s, at line %d\n>>> '%s'\n%s", e.Source, e.Line, e.Text, e.Msg)
+}
+
+// loader reads the directives of config files and command-line overrides,
+// checking each of them, then sets the parameters they give all at once
+type loader struct {
+	// settings are the parameters given so far, each once
+	settings []Setting
+	// index maps the parameters given so far to their place in settings
+	index map[string]int
+}
+
+// newLoader creates a loader for a config file and its overrides
+func newLoader() *loader {
+	return &loader{index: make(map[string]int)}
+}
+
+// commit sets and applies the parameters given. An error applying them names
+// the parameter at fault, as a *ParameterError.
+func (l *loader) commit() error {
+	return storeInstance.update(l.settings, false)
+}
+
+// LoadFile applies the directives of the redis.conf-format file at path.
+// Each line holds a directive and its arguments, which may be quoted as in
+// Redis; empty lines and lines starting with # are skipped. Besides the
+// settings, a file may include other files with include, whose path may be a
+// glob pattern, and rename commands with rename-command. Loading stops at the
+// first directive in error, which is returned as a *FileError, and no
+// parameter is set then.
+func LoadFile(path string) error {
+	l := newLoader()
+	if err := l.loadFile(path, 0); err != nil {
+		return err
+	}
+	return l.commit()
+}
+
+// LoadArgs applies the command-line arguments of the server the way
+// redis-server does: an optional config file path, "-" reading the file from
+// standard input, then overrides such as "--port 7000 --dir /tmp", each
+// option and its arguments being a directive applied after the file.
+func LoadArgs(args []string) error {
+	l := newLoader()
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		var err error
+		if args[0] == "-" {
+			err = l.load(os.Stdin, "from standard input", 0)
+		} else {
+			err = l.loadFile(args[0], 0)
+		}
+		if err != nil {
+			return err
+		}
+		args = args[1:]
+	}
+
+	var directives [][]string
+	for _, arg := range args {
+		if option, ok := strings.CutPrefix(arg, "--"); ok {
+			directives = append(directives, []string{option})
+			continue
+		}
+		if len(directives) == 0 {
+			return &FileError{Line: 1, Text: arg, Msg: errBadDirective}
+		}
+		directives[len(directives)-1] = append(directives[len(directives)-1], arg)
+	}
+	for i, directive := range directives {
+		if err := l.apply(directive, 0); err != nil {
+			return lineError(err, "", i+1, strings.Join(directive, " "))
+		}
+	}
+	return l.commit()
+}
+
+// loadFile applies the directives of the file at path, included depth deep
+func (l *loader) loadFile(path string, depth int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			err = pathErr.Err
+		}
+		return fmt.Errorf("Fatal error, can't open config file '%s': %v", path, err)
+	}
+	defer f.Close()
+	return l.load(f, path, depth)
+}
+
+// load applies the directives read from r, the file source
+func (l *loader) load(r io.Reader, source string, depth int) error {
+	scanner := bufio.NewScanner(r)
+	// Lines may be as long as the arguments they hold
+	scanner.Buffer(nil, 1<<30)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		split, ok := resp.SplitArgs([]byte(line))
+		if !ok {
+			return &FileError{Source: source, Line: lineNum, Text: line, Msg: errUnbalanced}
+		}
+		args := make([]string, len(split))
+		for i, arg := range split {
+			args[i] = string(arg)
+		}
+		if err := l.apply(args, depth); err != nil {
+			return lineError(err, source, lineNum, line)
+		}
+	}
+	return scanner.Err()
+}
+
+// lineError returns the error of the directive text at line of source. Errors
+// of included files are returned as they are, reporting where in the included
+// file the error is.
+func lineError(err error, source string, line int, text string) error {
+	var fileErr *FileError
+	if errors.As(err, &fileErr) {
+		return err
+	}
+	return &FileError{Source: source, Line: line, Text: text, Msg: err.Error()}
+}
+
+// apply reads a directive, args being its name followed by its arguments,
+// from a file included depth deep
+func (l *loader) apply(args []string, depth int) error {
+	name := strings.ToLower(args[0])
+	values := args[1:]
+
+	switch name {
+	case "include":
+		if len(values) != 1 {
+			return errors.New(errBadDirective)
+		}
+		return l.include(values[0], depth+1)
+	case "rename-command":
+		if len(values) != 2 {
+			return errors.New(errBadDirective)
+		}
+		RenameCommand(values[0], values[1])
+		return nil
+	}
+
+	param, ok := lookup(name)
+	if !ok || len(values) == 0 {
+		return errors.New(errBadDirective)
+	}
+	if param.Type != TypeString && param.Type != TypeStringList && len(values) != 1 {
+		return errors.New(errBadDirective)
+	}
+	value := strings.Join(values, " ")
+
+	i, seen := l.index[name]
+	if seen && accumulatingDirectives[name] {
+		value = l.settings[i].Value + " " + value
+	}
+	if _, err := param.parse(value); err != nil {
+		return err
+	}
+	if seen {
+		l.settings[i].Value = value
+		return nil
+	}
+	l.index[name] = len(l.settings)
+	l.settings = append(l.settings, Setting{Name: name, Value: value})
+	return nil
+}
+
+// include applies the files matching pattern, in lexical order, as if their
+// directives were in place of the include. Relative paths are relative to the
+// working directory, as in Redis.
+func (l *loader) include(pattern string, depth int) error {
+	if depth > maxIncludeDepth {
+		return errTooManyIncludes
+	}
+	paths := []string{pattern}
+	if strings.ContainsAny(pattern, "*?[") {
+		var err error
+		if paths, err = filepath.Glob(pattern); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		if err := l.loadFile(path, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
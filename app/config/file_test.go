@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile writes a config file to dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+// expectSetting checks the value of a setting
+func expectSetting(t *testing.T, key, expected string) {
+	t.Helper()
+	if value, ok := Get(key); !ok || value != expected {
+		t.Errorf("Expected %s to be %q, got %q", key, expected, value)
+	}
+}
+
+// resetSettings restores the settings the tests load from files
+func resetSettings() {
+	for _, setting := range []Setting{
+		{"port", "6379"}, {"dir", "/"}, {"requirepass", ""}, {"protected-mode", "yes"},
+		{"maxmemory", "0"}, {"proto-max-bulk-len", "512mb"}, {"tls-auth-clients", "yes"},
+		{"bind", "* -::*"}, {"client-output-buffer-limit", "normal 0 0 0"}, {"timeout", "0"},
+	} {
+		SetConfig(setting.Name, setting.Value)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	defer resetSettings()
+	dir := t.TempDir()
+	writeFile(t, dir, "a.conf", "protected-mode no\n")
+	writeFile(t, dir, "b.conf", "tls-auth-clients OPTIONAL\n")
+	path := writeFile(t, dir, "redis.conf", `# A comment
+port 7000
+
+  dir   "/tmp/with space"
+requirepass 'it\'s' "tab\there"
+MaxMemory 1gb
+proto-max-bulk-len 100mb
+bind 127.0.0.1 ::1
+client-output-buffer-limit normal 0 0 0
+client-output-buffer-limit pubsub 32mb 8mb 60
+rename-command test-file-command ""
+include `+filepath.Join(dir, "[ab].conf")+`
+`)
+	defer RenameCommand("TEST-FILE-COMMAND", "TEST-FILE-COMMAND")
+
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("Expected the file to load, got %v", err)
+	}
+	expectSetting(t, "port", "7000")
+	expectSetting(t, "dir", "/tmp/with space")
+	expectSetting(t, "requirepass", "it's tab\there")
+	expectSetting(t, "maxmemory", "1073741824")
+	expectSetting(t, "proto-max-bulk-len", "104857600")
+	expectSetting(t, "bind", "127.0.0.1 ::1")
+	expectSetting(t, "client-output-buffer-limit", "normal 0 0 0 pubsub 32mb 8mb 60")
+	expectSetting(t, "protected-mode", "no")
+	expectSetting(t, "tls-auth-clients", "optional")
+	if newName, ok := RenamedCommands()["TEST-FILE-COMMAND"]; !ok || newName != "" {
+		t.Errorf("Expected TEST-FILE-COMMAND to be disabled, got %q", RenamedCommands())
+	}
+
+	// Loading the file again replaces the repeated directives
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("Expected the file to load again, got %v", err)
+	}
+	expectSetting(t, "client-output-buffer-limit", "normal 0 0 0 pubsub 32mb 8mb 60")
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	defer resetSettings()
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.conf")
+	writeFile(t, dir, "broken.conf", "timeout 10\ntimeout \"unbalanced\n")
+	writeFile(t, dir, "self.conf", "include "+filepath.Join(dir, "self.conf")+"\n")
+
+	tests := []struct {
+		name    string
+		content string
+		// source is where the error is, if not in the file loaded
+		source string
+		line   int
+		text   string
+		msg    string
+	}{
+		{name: "unbalanced quotes", content: "requirepass 'open", line: 1, text: "requirepass 'open", msg: "Unbalanced quotes in configuration line"},
+		{name: "no argument", content: "# comment\ntimeout", line: 2, text: "timeout", msg: "Bad directive or wrong number of arguments"},
+		{name: "unknown directive", content: "nosuch yes", line: 1, text: "nosuch yes", msg: "Bad directive or wrong number of arguments"},
+		{name: "too many arguments", content: "timeout 1 2", line: 1, text: "timeout 1 2", msg: "Bad directive or wrong number of arguments"},
+		{name: "memory", content: "maxmemory lots", line: 1, text: "maxmemory lots", msg: "argument must be a memory value"},
+		{name: "bounds", content: "port 70000", line: 1, text: "port 70000", msg: "argument must be between 0 and 65535 inclusive"},
+		{name: "rename-command", content: "rename-command get", line: 1, text: "rename-command get", msg: "Bad directive or wrong number of arguments"},
+		{name: "missing include", content: "include " + missing, line: 1, text: "include " + missing, msg: "Fatal error, can't open config file '" + missing + "': no such file or directory"},
+		{name: "include cycle", content: "include " + filepath.Join(dir, "self.conf"), source: filepath.Join(dir, "self.conf"), line: 1, text: "include " + filepath.Join(dir, "self.conf"), msg: "Too many nested includes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, dir, "test.conf", "timeout 30\n"+tt.content)
+			source := path
+			if tt.source != "" {
+				source = tt.source
+			}
+			var fileErr *FileError
+			if err := LoadFile(path); !errors.As(err, &fileErr) || fileErr.Source != source || fileErr.Line != tt.line+1 && source == path || fileErr.Text != tt.text || fileErr.Msg != tt.msg {
+				t.Errorf("Expected line %d %q to fail with %q, got %v", tt.line, tt.text, tt.msg, err)
+			}
+			// A file in error changes no parameter
+			expectSetting(t, "timeout", "0")
+		})
+	}
+
+	t.Run("error in an included file", func(t *testing.T) {
+		broken := filepath.Join(dir, "broken.conf")
+		path := writeFile(t, dir, "test.conf", "include "+broken)
+		var fileErr *FileError
+		if err := LoadFile(path); !errors.As(err, &fileErr) || fileErr.Source != broken || fileErr.Line != 2 {
+			t.Errorf("Expected the error at line 2 of %s, got %v", broken, err)
+		}
+	})
+
+	if err := LoadFile(missing); err == nil {
+		t.Errorf("Expected a missing file to fail to load")
+	}
+}
+
+func TestLoadArgs(t *testing.T) {
+	defer resetSettings()
+	dir := t.TempDir()
+	path := writeFile(t, dir, "redis.conf", "port 6380\ntimeout 10\n")
+
+	if err := LoadArgs([]string{path, "--port", "7000", "--bind", "127.0.0.1", "::1", "--requirepass", ""}); err != nil {
+		t.Fatalf("Expected the arguments to load, got %v", err)
+	}
+	expectSetting(t, "port", "7000")
+	expectSetting(t, "timeout", "10")
+	expectSetting(t, "bind", "127.0.0.1 ::1")
+	expectSetting(t, "requirepass", "")
+
+	if err := LoadArgs([]string{"--timeout", "20"}); err != nil {
+		t.Fatalf("Expected overrides without a file to load, got %v", err)
+	}
+	expectSetting(t, "timeout", "20")
+
+	var fileErr *FileError
+	if err := LoadArgs([]string{path, "stray"}); !errors.As(err, &fileErr) || fileErr.Source != "" || fileErr.Text != "stray" {
+		t.Errorf("Expected a stray argument to fail, got %v", err)
+	}
+	if err := LoadArgs([]string{"--port"}); !errors.As(err, &fileErr) || fileErr.Msg != "Bad directive or wrong number of arguments" {
+		t.Errorf("Expected an override without arguments to fail, got %v", err)
+	}
+	if err := LoadArgs([]string{"--maxmemory", "10mb"}); err != nil {
+		t.Fatalf("Expected the memory override to load, got %v", err)
+	}
+	expectSetting(t, "maxmemory", "10485760")
+}
+
+func TestLoadFile_Apply(t *testing.T) {
+	failApply = true
+	defer func() { failApply = false }()
+
+	path := writeFile(t, t.TempDir(), "redis.conf", "failing yes\n")
+	var paramErr *ParameterError
+	if err := LoadFile(path); !errors.As(err, &paramErr) || paramErr.Name != "failing" {
+		t.Errorf("Expected applying failing to fail, got %v", err)
+	}
+}
+
+func TestFileError_Error(t *testing.T) {
+	err := &FileError{Source: "redis.conf", Line: 3, Text: "port", Msg: "Bad directive or wrong number of arguments"}
+	expected := "Reading the configuration file redis.conf, at line 3\n>>> 'port'\nBad directive or wrong number of arguments"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	err = &FileError{Line: 2, Text: "port", Msg: "Bad directive or wrong number of arguments"}
+	expected = "Reading the command line, at option 2\n>>> 'port'\nBad directive or wrong number of arguments"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type is the type of the values of a parameter, which decides how values are
+// parsed and how they are shown by CONFIG GET
+type Type int
+
+const (
+	// TypeString parameters take any string
+	TypeString Type = iota
+	// TypeBool parameters take yes or no, in any case
+	TypeBool
+	// TypeInt parameters take an integer between Min and Max
+	TypeInt
+	// TypeMemory parameters take a memory size such as 64mb, between Min and
+	// Max bytes, and are shown in bytes
+	TypeMemory
+	// TypeEnum parameters take one of their Values, in any case
+	TypeEnum
+	// TypeStringList parameters take a list of strings separated by spaces
+	TypeStringList
+)
+
+// Errors of values that don't suit the type of their parameter, in Redis's
+// wording
+var (
+	ErrBool   = errors.New("argument must be 'yes' or 'no'")
+	ErrInt    = errors.New("argument couldn't be parsed into an integer")
+	ErrMemory = errors.New("argument must be a memory value")
+)
+
+// ApplyFunc applies the current values of one or more parameters to the
+// running server. Apply functions run once all of the new values are set.
+type ApplyFunc func() error
+
+// Parameter declares a configuration parameter
+type Parameter struct {
+	// Name is the name of the parameter, in lowercase
+	Name string
+	// Type is the type of the parameter's values
+	Type Type
+	// Default is the value of the parameter until it is set
+	Default string
+	// Min and Max bound the values of TypeInt and TypeMemory parameters,
+	// which are unbounded if both are 0
+	Min, Max int64
+	// Values are the values a TypeEnum parameter takes
+	Values []string
+	// Immutable parameters can only be set by config files and command-line
+	// overrides, before the server starts
+	Immutable bool
+	// Validate, if set, checks values that have a structure of their own
+	// once they suit the type
+	Validate func(value string) error
+	// Normalize, if set, checks values that have a structure of their own
+	// once they suit the type, like Validate, and returns them in the form
+	// CONFIG GET shows them
+	Normalize func(value string) (string, error)
+	// Apply, if set, applies the parameter's value to the running server
+	// when it changes
+	Apply ApplyFunc
+	// ApplyGroup, if set, names the parameters sharing an apply function,
+	// such as the certificate and key of TLS. The function runs once when
+	// several parameters of the group are set together.
+	ApplyGroup string
+}
+
+// parse checks that value suits the parameter, returning it in the form
+// CONFIG GET shows it
+func (p *Parameter) parse(value string) (string, error) {
+	switch p.Type {
+	case TypeBool:
+		switch strings.ToLower(value) {
+		case "yes":
+			value = "yes"
+		case "no":
+			value = "no"
+		default:
+			return "", ErrBool
+		}
+	case TypeInt, TypeMemory:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if p.Type == TypeMemory {
+			n, err = ParseMemory(value)
+			if err != nil {
+				return "", ErrMemory
+			}
+		} else if err != nil {
+			return "", ErrInt
+		}
+		if (p.Min != 0 || p.Max != 0) && (n < p.Min || n > p.Max) {
+			return "", fmt.Errorf("argument must be between %d and %d inclusive", p.Min, p.Max)
+		}
+		value = strconv.FormatInt(n, 10)
+	case TypeEnum:
+		i := -1
+		for j, v := range p.Values {
+			if strings.EqualFold(v, value) {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return "", fmt.Errorf("argument(s) must be one of the following: %s", strings.Join(p.Values, ", "))
+		}
+		value = p.Values[i]
+	case TypeStringList:
+		value = strings.Join(strings.Fields(value), " ")
+	}
+
+	if p.Validate != nil {
+		if err := p.Validate(value); err != nil {
+			return "", err
+		}
+	}
+	if p.Normalize != nil {
+		return p.Normalize(value)
+	}
+	return value, nil
+}
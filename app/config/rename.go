@@ -0,0 +1,40 @@
+package config
+
+import (
+	"maps"
+	"strings"
+	"sync"
+)
+
+// renames holds the commands renamed or disabled by rename-command. Unlike
+// the other settings, rename-command may be given once per command and
+// isn't visible to CONFIG GET, as in Redis.
+var renames = struct {
+	mu sync.RWMutex
+	// names maps the uppercase names of the renamed commands to their new
+	// uppercase names, or to "" for disabled commands
+	names map[string]string
+}{names: make(map[string]string)}
+
+// RenameCommand renames the command name to newName, or disables it if
+// newName is empty, in the command registries created afterwards. Renaming a
+// command to its own name undoes the rename. Names are case insensitive.
+func RenameCommand(name, newName string) {
+	renames.mu.Lock()
+	defer renames.mu.Unlock()
+
+	name, newName = strings.ToUpper(name), strings.ToUpper(newName)
+	if name == newName {
+		delete(renames.names, name)
+		return
+	}
+	renames.names[name] = newName
+}
+
+// RenamedCommands returns a map of the uppercase names of the commands
+// renamed by RenameCommand to their new names, "" meaning disabled
+func RenamedCommands() map[string]string {
+	renames.mu.RLock()
+	defer renames.mu.RUnlock()
+	return maps.Clone(renames.names)
+}
@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestRenameCommand(t *testing.T) {
+	RenameCommand("flushall", "")
+	RenameCommand("Config", "myconfig")
+	defer RenameCommand("FLUSHALL", "FLUSHALL")
+	defer RenameCommand("CONFIG", "CONFIG")
+
+	renamed := RenamedCommands()
+	if newName, ok := renamed["FLUSHALL"]; !ok || newName != "" {
+		t.Errorf("Expected FLUSHALL to be disabled, got %q", renamed)
+	}
+	if renamed["CONFIG"] != "MYCONFIG" {
+		t.Errorf("Expected CONFIG to be renamed to MYCONFIG, got %q", renamed)
+	}
+
+	// The returned map is a copy
+	renamed["GET"] = ""
+	if _, ok := RenamedCommands()["GET"]; ok {
+		t.Errorf("Expected RenamedCommands to return a copy")
+	}
+
+	RenameCommand("config", "CONFIG")
+	if _, ok := RenamedCommands()["CONFIG"]; ok {
+		t.Errorf("Expected renaming CONFIG to itself to undo the rename")
+	}
+}
@@ -1,63 +1,231 @@
 package config
 
 import (
-	"regexp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/dotslash21/redis-clone/app/glob"
+)
+
+// Errors of settings CONFIG SET refuses
+var (
+	// ErrUnknownParameter is returned for parameters that weren't declared
+	ErrUnknownParameter = errors.New("unknown parameter")
+	// ErrImmutable is returned when changing an immutable parameter while
+	// the server runs
+	ErrImmutable = errors.New("can't set immutable config")
+	// ErrDuplicate is returned when a parameter is set twice at once
+	ErrDuplicate = errors.New("duplicate parameter")
 )
 
+// ParameterError reports the parameter a setting failed for
+type ParameterError struct {
+	Name string
+	Err  error
+}
+
+// Error names the parameter along with the error
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error of the parameter
+func (e *ParameterError) Unwrap() error {
+	return e.Err
+}
+
+// Setting is a value to set a parameter to
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// parameter is a declared parameter and its current value
+type parameter struct {
+	Parameter
+	value string
+}
+
 type store struct {
-	settings map[string]string
-	mu       sync.RWMutex
+	parameters map[string]*parameter
+	mu         sync.RWMutex
+	// updateMu serializes updates, which apply functions run during without
+	// holding mu, so that they may read the configuration
+	updateMu sync.Mutex
 }
 
 var storeInstance *store = &store{
-	settings: make(map[string]string),
-	mu:       sync.RWMutex{},
+	parameters: make(map[string]*parameter),
+	mu:         sync.RWMutex{},
 }
 
-// SetConfig sets a configuration value
-func SetConfig(key, value string) {
+// Register declares a parameter, which holds its default value until it is
+// set. It panics if the parameter is already declared or its default doesn't
+// suit it, as parameters are declared by the packages using them when the
+// program starts.
+func Register(p Parameter) {
+	value, err := p.parse(p.Default)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default %q for parameter %s: %v", p.Default, p.Name, err))
+	}
+
 	storeInstance.mu.Lock()
 	defer storeInstance.mu.Unlock()
-	storeInstance.settings[key] = value
+	if _, exists := storeInstance.parameters[p.Name]; exists {
+		panic("parameter " + p.Name + " is already declared")
+	}
+	storeInstance.parameters[p.Name] = &parameter{Parameter: p, value: value}
+}
+
+// SetConfig sets a parameter and applies it, whether or not it is immutable,
+// such as for the server's own settings
+func SetConfig(key, value string) error {
+	return storeInstance.update([]Setting{{Name: key, Value: value}}, false)
+}
+
+// Update sets parameters the way CONFIG SET does: every value is checked
+// first, and if one of them is refused, or can't be applied, none of them is
+// changed. Immutable parameters are refused. The error is a *ParameterError
+// naming the parameter at fault.
+func Update(settings []Setting) error {
+	return storeInstance.update(settings, true)
 }
 
-// GetConfig retrieves a configuration value
-func GetConfig(searchPattern string) (map[string]string, error) {
+// update sets and applies the parameters of settings atomically, refusing
+// immutable ones if mutableOnly is set
+func (s *store) update(settings []Setting, mutableOnly bool) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	// Check every setting before changing anything
+	params := make([]*parameter, len(settings))
+	values := make([]string, len(settings))
+	s.mu.RLock()
+	for i, setting := range settings {
+		name := strings.ToLower(setting.Name)
+		param, ok := s.parameters[name]
+		var err error
+		switch {
+		case !ok:
+			err = ErrUnknownParameter
+		case mutableOnly && param.Immutable:
+			err = ErrImmutable
+		case slices.Contains(params[:i], param):
+			err = ErrDuplicate
+		default:
+			values[i], err = param.parse(setting.Value)
+		}
+		if err != nil {
+			s.mu.RUnlock()
+			return &ParameterError{Name: setting.Name, Err: err}
+		}
+		params[i] = param
+	}
+	s.mu.RUnlock()
+
+	previous := s.set(params, values)
+	if param, err := applyAll(params); err != nil {
+		// Restore the previous values, applying them again
+		s.set(params, previous)
+		applyAll(params)
+		return &ParameterError{Name: param.Name, Err: err}
+	}
+	return nil
+}
+
+// set sets params to values, returning their previous values
+func (s *store) set(params []*parameter, values []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := make([]string, len(params))
+	for i, param := range params {
+		previous[i] = param.value
+		param.value = values[i]
+	}
+	return previous
+}
+
+// applyAll runs the apply functions of params, once per apply group,
+// returning the parameter whose function failed along with its error
+func applyAll(params []*parameter) (*parameter, error) {
+	var applied []string
+	for _, param := range params {
+		if param.Apply == nil {
+			continue
+		}
+		if param.ApplyGroup != "" {
+			if slices.Contains(applied, param.ApplyGroup) {
+				continue
+			}
+			applied = append(applied, param.ApplyGroup)
+		}
+		if err := param.Apply(); err != nil {
+			return param, err
+		}
+	}
+	return nil, nil
+}
+
+// lookup returns a declared parameter
+func lookup(name string) (*parameter, bool) {
+	storeInstance.mu.RLock()
+	defer storeInstance.mu.RUnlock()
+	param, ok := storeInstance.parameters[name]
+	return param, ok
+}
+
+// GetConfig returns the parameters whose names match a Redis glob pattern,
+// case-insensitively, along with their values
+func GetConfig(pattern string) map[string]string {
 	storeInstance.mu.RLock()
 	defer storeInstance.mu.RUnlock()
 
 	results := make(map[string]string)
+	for name, param := range storeInstance.parameters {
+		if glob.Match(pattern, name, true) {
+			results[name] = param.value
+		}
+	}
+	return results
+}
 
-	// Convert Redis glob pattern to regex pattern
-	regexPattern := convertGlobToRegex(searchPattern)
+// GetInt returns the integer value of a parameter, or def if the parameter
+// isn't declared or doesn't hold an integer
+func GetInt(key string, def int) int {
+	value, ok := Get(key)
+	if !ok {
+		return def
+	}
 
-	// Compile the regex pattern
-	regex, err := regexp.Compile(regexPattern)
+	n, err := strconv.Atoi(value)
 	if err != nil {
-		return nil, err
+		return def
 	}
+	return n
+}
 
-	// Find all the matching keys
-	for key, value := range storeInstance.settings {
-		if regex.MatchString(key) {
-			results[key] = value
-		}
+// GetBool returns whether a TypeBool parameter is set to yes, or def if the
+// parameter isn't declared
+func GetBool(key string, def bool) bool {
+	value, ok := Get(key)
+	if !ok {
+		return def
 	}
-
-	return results, nil
+	return value == "yes"
 }
 
-// Helper to convert Redis glob pattern to regex
-func convertGlobToRegex(pattern string) string {
-	// First escape the entire string to handle special regex chars
-	escaped := regexp.QuoteMeta(pattern)
-
-	// Then replace escaped wildcards with their regex equivalents
-	escaped = strings.ReplaceAll(escaped, "\\*", ".*")
-	escaped = strings.ReplaceAll(escaped, "\\?", ".")
+// Get returns the value of a parameter, if it is declared
+func Get(key string) (string, bool) {
+	storeInstance.mu.RLock()
+	defer storeInstance.mu.RUnlock()
 
-	// Ensure we match the entire string
-	return "^" + escaped + "$"
+	param, ok := storeInstance.parameters[key]
+	if !ok {
+		return "", false
+	}
+	return param.value, true
 }
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// applied counts the runs of the apply functions of the test parameters
+var applied = map[string]int{}
+
+// failApply makes applyFailing fail while set
+var failApply bool
+
+func applyShared() error {
+	applied["shared"]++
+	return nil
+}
+
+// applyCounting returns apply functions built from the same code, which
+// count their runs under name
+func applyCounting(name string) ApplyFunc {
+	return func() error {
+		applied[name]++
+		return nil
+	}
+}
+
+func applyFailing() error {
+	applied["failing"]++
+	if failApply {
+		return errors.New("failed to apply")
+	}
+	return nil
+}
+
+// init declares the parameters the tests set, as the packages of the server
+// declare theirs
+func init() {
+	for _, p := range []Parameter{
+		{Name: "port", Type: TypeInt, Default: "6379", Min: 0, Max: 65535, Immutable: true},
+		{Name: "dir", Type: TypeString, Default: "/"},
+		{Name: "requirepass", Type: TypeString},
+		{Name: "protected-mode", Type: TypeBool, Default: "yes"},
+		{Name: "maxmemory", Type: TypeMemory, Default: "0", Min: 0, Max: math.MaxInt64},
+		{Name: "proto-max-bulk-len", Type: TypeMemory, Default: "512mb", Min: 1024 * 1024, Max: math.MaxInt64},
+		{Name: "tls-auth-clients", Type: TypeEnum, Default: "yes", Values: []string{"no", "yes", "optional"}},
+		{Name: "bind", Type: TypeStringList, Default: "* -::*", Immutable: true},
+		{Name: "client-output-buffer-limit", Type: TypeString, Default: "normal 0 0 0"},
+		{Name: "timeout", Type: TypeInt, Default: "0", Min: 0, Max: math.MaxInt32},
+		{Name: "shared-a", Type: TypeString, Apply: applyShared, ApplyGroup: "shared"},
+		{Name: "shared-b", Type: TypeString, Apply: applyShared, ApplyGroup: "shared"},
+		{Name: "closure-a", Type: TypeString, Apply: applyCounting("closure-a")},
+		{Name: "closure-b", Type: TypeString, Apply: applyCounting("closure-b")},
+		{Name: "failing", Type: TypeString, Apply: applyFailing},
+		{Name: "validated", Type: TypeString, Default: "even", Validate: func(value string) error {
+			if len(value)%2 != 0 {
+				return errors.New("argument must have an even length")
+			}
+			return nil
+		}},
+	} {
+		Register(p)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	expectSetting(t, "port", "6379")
+	expectSetting(t, "proto-max-bulk-len", "536870912")
+	if _, ok := Get("undeclared"); ok {
+		t.Errorf("Expected undeclared parameters not to be found")
+	}
+	if GetInt("timeout", -1) != 0 || GetInt("undeclared", -1) != -1 {
+		t.Errorf("Expected GetInt to return the value of declared parameters only")
+	}
+	if !GetBool("protected-mode", false) || GetBool("undeclared", false) {
+		t.Errorf("Expected GetBool to return the value of declared parameters only")
+	}
+
+	expectPanic := func(name string, p Parameter) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected %s to panic", name)
+			}
+		}()
+		Register(p)
+	}
+	expectPanic("a duplicate", Parameter{Name: "port", Type: TypeInt, Default: "6379"})
+	expectPanic("an invalid default", Parameter{Name: "invalid-default", Type: TypeBool, Default: "maybe"})
+}
+
+func TestParameter_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    Parameter
+		value    string
+		expected string
+		errMsg   string
+	}{
+		{name: "bool", param: Parameter{Type: TypeBool}, value: "YES", expected: "yes"},
+		{name: "invalid bool", param: Parameter{Type: TypeBool}, value: "1", errMsg: "argument must be 'yes' or 'no'"},
+		{name: "int", param: Parameter{Type: TypeInt}, value: "-42", expected: "-42"},
+		{name: "invalid int", param: Parameter{Type: TypeInt}, value: "1k", errMsg: "argument couldn't be parsed into an integer"},
+		{name: "int out of bounds", param: Parameter{Type: TypeInt, Min: 1, Max: 10}, value: "0", errMsg: "argument must be between 1 and 10 inclusive"},
+		{name: "memory", param: Parameter{Type: TypeMemory}, value: "1gb", expected: "1073741824"},
+		{name: "invalid memory", param: Parameter{Type: TypeMemory}, value: "lots", errMsg: "argument must be a memory value"},
+		{name: "memory out of bounds", param: Parameter{Type: TypeMemory, Min: 1024, Max: 2048}, value: "1k", errMsg: "argument must be between 1024 and 2048 inclusive"},
+		{name: "enum", param: Parameter{Type: TypeEnum, Values: []string{"no", "yes", "optional"}}, value: "Optional", expected: "optional"},
+		{name: "invalid enum", param: Parameter{Type: TypeEnum, Values: []string{"no", "yes", "optional"}}, value: "maybe", errMsg: "argument(s) must be one of the following: no, yes, optional"},
+		{name: "string list", param: Parameter{Type: TypeStringList}, value: " 127.0.0.1   ::1 ", expected: "127.0.0.1 ::1"},
+		{name: "string", param: Parameter{Type: TypeString}, value: " kept as is ", expected: " kept as is "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.param.parse(tt.value)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("Expected %q, got %q (%v)", tt.errMsg, value, err)
+				}
+				return
+			}
+			if err != nil || value != tt.expected {
+				t.Errorf("Expected %q, got %q (%v)", tt.expected, value, err)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	defer Update([]Setting{{Name: "maxmemory", Value: "0"}, {Name: "timeout", Value: "0"}, {Name: "dir", Value: "/"}})
+
+	if err := Update([]Setting{{Name: "MaxMemory", Value: "100mb"}, {Name: "timeout", Value: "60"}}); err != nil {
+		t.Fatalf("Expected the parameters to be set, got %v", err)
+	}
+	expectSetting(t, "maxmemory", "104857600")
+	expectSetting(t, "timeout", "60")
+
+	tests := []struct {
+		name     string
+		settings []Setting
+		param    string
+		err      error
+		errMsg   string
+	}{
+		{name: "unknown", settings: []Setting{{"timeout", "1"}, {"nosuch", "1"}}, param: "nosuch", err: ErrUnknownParameter},
+		{name: "immutable", settings: []Setting{{"timeout", "1"}, {"port", "7000"}}, param: "port", err: ErrImmutable},
+		{name: "duplicate", settings: []Setting{{"timeout", "1"}, {"TIMEOUT", "2"}}, param: "TIMEOUT", err: ErrDuplicate},
+		{name: "invalid value", settings: []Setting{{"timeout", "1"}, {"maxmemory", "lots"}}, param: "maxmemory", err: ErrMemory},
+		{name: "invalid structure", settings: []Setting{{"timeout", "1"}, {"validated", "odd"}}, param: "validated", errMsg: "argument must have an even length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Update(tt.settings)
+			var paramErr *ParameterError
+			if !errors.As(err, &paramErr) || paramErr.Name != tt.param {
+				t.Fatalf("Expected %s to be refused, got %v", tt.param, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+			if tt.errMsg != "" && paramErr.Err.Error() != tt.errMsg {
+				t.Errorf("Expected %q, got %v", tt.errMsg, paramErr.Err)
+			}
+			// None of the settings is applied
+			expectSetting(t, "timeout", "60")
+		})
+	}
+
+	t.Run("immutable parameters are set by SetConfig", func(t *testing.T) {
+		if err := SetConfig("port", "7000"); err != nil {
+			t.Fatalf("Expected port to be set, got %v", err)
+		}
+		defer SetConfig("port", "6379")
+		expectSetting(t, "port", "7000")
+	})
+}
+
+func TestUpdate_Apply(t *testing.T) {
+	clear(applied)
+	if err := Update([]Setting{{"shared-a", "a"}, {"shared-b", "b"}, {"failing", "1"}}); err != nil {
+		t.Fatalf("Expected the parameters to be set, got %v", err)
+	}
+	if applied["shared"] != 1 || applied["failing"] != 1 {
+		t.Errorf("Expected each apply function to run once, got %v", applied)
+	}
+
+	failApply = true
+	defer func() { failApply = false }()
+	err := Update([]Setting{{"shared-a", "new a"}, {"failing", "2"}})
+	var paramErr *ParameterError
+	if !errors.As(err, &paramErr) || paramErr.Name != "failing" || paramErr.Err.Error() != "failed to apply" {
+		t.Fatalf("Expected applying failing to fail, got %v", err)
+	}
+	// The previous values are restored and applied again
+	expectSetting(t, "shared-a", "a")
+	expectSetting(t, "failing", "1")
+	if applied["shared"] != 3 {
+		t.Errorf("Expected the previous values to be applied again, got %v", applied)
+	}
+
+	// Parameters outside a group are applied separately, even when their
+	// functions share code
+	clear(applied)
+	if err := Update([]Setting{{"closure-a", "a"}, {"closure-b", "b"}}); err != nil {
+		t.Fatalf("Expected the parameters to be set, got %v", err)
+	}
+	if applied["closure-a"] != 1 || applied["closure-b"] != 1 {
+		t.Errorf("Expected each closure to run once, got %v", applied)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	if err := Update([]Setting{{"shared-a", "a"}, {"shared-b", "b"}}); err != nil {
+		t.Fatalf("Expected the parameters to be set, got %v", err)
+	}
+	results := GetConfig("shared-*")
+	if len(results) != 2 || results["shared-a"] != "a" || results["shared-b"] != "b" {
+		t.Errorf("Expected shared-a and shared-b, got %v", results)
+	}
+	if results := GetConfig("SHARED-[a-c]"); len(results) != 2 {
+		t.Errorf("Expected shared-a and shared-b, got %v", results)
+	}
+	if results := GetConfig("shared-[^a]"); len(results) != 1 || results["shared-b"] != "b" {
+		t.Errorf("Expected shared-b, got %v", results)
+	}
+	if results := GetConfig("nosuch*"); len(results) != 0 {
+		t.Errorf("Expected no parameters, got %v", results)
+	}
+}
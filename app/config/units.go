@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMemory is returned by ParseMemory for malformed sizes
+var ErrInvalidMemory = errors.New("invalid memory size")
+
+// memoryUnits maps the unit suffixes accepted by ParseMemory to their size
+// in bytes. As in Redis, k, m and g are powers of 1000 and kb, mb and gb are
+// powers of 1024.
+var memoryUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1000,
+	"kb": 1024,
+	"m":  1000 * 1000,
+	"mb": 1024 * 1024,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1024 * 1024 * 1024,
+}
+
+// ParseMemory parses a memory size such as "100", "64k" or "1gb" into bytes.
+// Units are case insensitive.
+func ParseMemory(s string) (int64, error) {
+	lower := strings.ToLower(s)
+	digits := strings.TrimRight(lower, "bkmg")
+
+	multiplier, ok := memoryUnits[lower[len(digits):]]
+	if !ok {
+		return 0, ErrInvalidMemory
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/multiplier {
+		return 0, ErrInvalidMemory
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		valid    bool
+	}{
+		{"0", 0, true},
+		{"100", 100, true},
+		{"100b", 100, true},
+		{"1k", 1000, true},
+		{"1kb", 1024, true},
+		{"32MB", 32 * 1024 * 1024, true},
+		{"2m", 2000000, true},
+		{"1gb", 1 << 30, true},
+		{"3G", 3000000000, true},
+		{"", 0, false},
+		{"mb", 0, false},
+		{"-1", 0, false},
+		{"1tb", 0, false},
+		{"1 mb", 0, false},
+		{"1.5mb", 0, false},
+		{"99999999999999gb", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMemory(tt.input)
+			if tt.valid != (err == nil) {
+				t.Fatalf("Expected valid = %v, got error %v", tt.valid, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -18,9 +18,13 @@ const (
 	ErrorTypeServer
 )
 
+// DefaultCode is the Redis error code used when an error does not specify one
+const DefaultCode = "ERR"
+
 // Error represents a custom error with additional context
 type Error struct {
 	Type    ErrorType
+	Code    string
 	Message string
 	Cause   error
 	Stack   string
@@ -35,6 +39,17 @@ func New(errType ErrorType, message string) *Error {
 	}
 }
 
+// NewWithCode creates a new command Error that is reported to clients with the
+// given Redis error code (e.g. WRONGTYPE) instead of the default ERR
+func NewWithCode(code, message string) *Error {
+	return &Error{
+		Type:    ErrorTypeCommand,
+		Code:    code,
+		Message: message,
+		Stack:   getStack(),
+	}
+}
+
 // Wrap wraps an existing error with additional context
 func Wrap(err error, errType ErrorType, message string) *Error {
 	return &Error{
@@ -67,6 +82,15 @@ func IsCommandError(err error) bool {
 	return false
 }
 
+// ReplyCode returns the Redis error code a client should see for err
+func ReplyCode(err error) string {
+	var e *Error
+	if ok := As(err, &e); ok && e.Code != "" {
+		return e.Code
+	}
+	return DefaultCode
+}
+
 // IsStorageError checks if an error is a storage error
 func IsStorageError(err error) bool {
 	var e *Error
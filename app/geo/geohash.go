@@ -0,0 +1,367 @@
+// Package geo implements the geohash encoding and spatial search helpers used
+// by the GEO commands. Positions are stored as sorted set scores holding a
+// 52-bit interleaved geohash, exactly like Redis, so scores are portable
+// between this server and Redis.
+package geo
+
+import (
+	"math"
+)
+
+const (
+	// Step is the number of bits per coordinate in a stored geohash
+	Step = 26
+
+	// Limits of the coordinates that can be encoded, as in EPSG:900913 / EPSG:3785 / OSGEO:41001
+	LongitudeMin = -180.0
+	LongitudeMax = 180.0
+	LatitudeMin  = -85.05112878
+	LatitudeMax  = 85.05112878
+
+	// EarthRadius is the Earth's quadratic mean radius for WGS-84, in meters
+	EarthRadius = 6372797.560856
+	// mercatorMax is the largest distance, in meters, covered by a single geohash cell
+	mercatorMax = 20037726.37
+
+	alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// Hash is a geohash of the given precision in bits per coordinate
+type Hash struct {
+	Bits uint64
+	Step uint8
+}
+
+// isZero reports whether the hash was cleared from a search
+func (h Hash) isZero() bool {
+	return h.Bits == 0 && h.Step == 0
+}
+
+// coordRange is a range of latitudes or longitudes
+type coordRange struct {
+	min, max float64
+}
+
+// Area is the box covered by a geohash
+type Area struct {
+	Hash      Hash
+	Longitude coordRange
+	Latitude  coordRange
+}
+
+var (
+	longitudeRange = coordRange{LongitudeMin, LongitudeMax}
+	latitudeRange  = coordRange{LatitudeMin, LatitudeMax}
+)
+
+// ValidCoordinates reports whether the pair can be encoded
+func ValidCoordinates(longitude, latitude float64) bool {
+	return longitude >= LongitudeMin && longitude <= LongitudeMax &&
+		latitude >= LatitudeMin && latitude <= LatitudeMax
+}
+
+// interleave64 interleaves the bits of x and y, with the bits of x in the even
+// positions and those of y in the odd positions
+func interleave64(x, y uint32) uint64 {
+	b := [...]uint64{0x5555555555555555, 0x3333333333333333, 0x0F0F0F0F0F0F0F0F, 0x00FF00FF00FF00FF, 0x0000FFFF0000FFFF}
+	s := [...]uint{1, 2, 4, 8, 16}
+
+	xx, yy := uint64(x), uint64(y)
+	for i := 4; i >= 0; i-- {
+		xx = (xx | (xx << s[i])) & b[i]
+		yy = (yy | (yy << s[i])) & b[i]
+	}
+	return xx | (yy << 1)
+}
+
+// deinterleave64 reverses interleave64, returning x in the low 32 bits and y in the high 32 bits
+func deinterleave64(interleaved uint64) uint64 {
+	b := [...]uint64{0x5555555555555555, 0x3333333333333333, 0x0F0F0F0F0F0F0F0F, 0x00FF00FF00FF00FF, 0x0000FFFF0000FFFF, 0x00000000FFFFFFFF}
+	s := [...]uint{0, 1, 2, 4, 8, 16}
+
+	x, y := interleaved, interleaved>>1
+	for i := 0; i < 6; i++ {
+		x = (x | (x >> s[i])) & b[i]
+		y = (y | (y >> s[i])) & b[i]
+	}
+	return x | (y << 32)
+}
+
+// encode encodes the coordinates within the given ranges at the given precision
+func encode(longRange, latRange coordRange, longitude, latitude float64, step uint8) Hash {
+	latOffset := (latitude - latRange.min) / (latRange.max - latRange.min)
+	longOffset := (longitude - longRange.min) / (longRange.max - longRange.min)
+
+	// Convert to fixed point based on the step size
+	latOffset *= float64(uint64(1) << step)
+	longOffset *= float64(uint64(1) << step)
+
+	return Hash{Bits: interleave64(uint32(latOffset), uint32(longOffset)), Step: step}
+}
+
+// Encode returns the 52-bit geohash of a valid longitude, latitude pair
+func Encode(longitude, latitude float64) uint64 {
+	return encode(longitudeRange, latitudeRange, longitude, latitude, Step).Bits
+}
+
+// decode returns the area covered by the hash
+func decode(hash Hash) Area {
+	separated := deinterleave64(hash.Bits)
+	latScale := latitudeRange.max - latitudeRange.min
+	longScale := longitudeRange.max - longitudeRange.min
+
+	ilato := uint32(separated)
+	ilono := uint32(separated >> 32)
+	cells := float64(uint64(1) << hash.Step)
+
+	return Area{
+		Hash: hash,
+		Latitude: coordRange{
+			min: latitudeRange.min + (float64(ilato)/cells)*latScale,
+			max: latitudeRange.min + (float64(ilato+1)/cells)*latScale,
+		},
+		Longitude: coordRange{
+			min: longitudeRange.min + (float64(ilono)/cells)*longScale,
+			max: longitudeRange.min + (float64(ilono+1)/cells)*longScale,
+		},
+	}
+}
+
+// Decode returns the longitude and latitude at the centre of the cell of a 52-bit geohash
+func Decode(bits uint64) (longitude, latitude float64) {
+	area := decode(Hash{Bits: bits, Step: Step})
+
+	longitude = min(max((area.Longitude.min+area.Longitude.max)/2, LongitudeMin), LongitudeMax)
+	latitude = min(max((area.Latitude.min+area.Latitude.max)/2, LatitudeMin), LatitudeMax)
+	return longitude, latitude
+}
+
+// String returns the standard 11 character geohash string for a 52-bit geohash.
+// Stored hashes use a latitude range of +/-85 degrees, so the position is
+// re-encoded with the standard +/-90 degree range first.
+func String(bits uint64) string {
+	longitude, latitude := Decode(bits)
+	hash := encode(coordRange{-180, 180}, coordRange{-90, 90}, longitude, latitude, Step)
+
+	buf := make([]byte, 11)
+	for i := range buf {
+		// Only 52 bits are available, so the last character is always zero
+		idx := uint64(0)
+		if i < 10 {
+			idx = (hash.Bits >> (52 - (i+1)*5)) & 0x1f
+		}
+		buf[i] = alphabet[idx]
+	}
+	return string(buf)
+}
+
+// degToRad converts degrees to radians
+func degToRad(deg float64) float64 {
+	return deg * (math.Pi / 180.0)
+}
+
+// radToDeg converts radians to degrees
+func radToDeg(rad float64) float64 {
+	return rad / (math.Pi / 180.0)
+}
+
+// Distance returns the haversine distance in meters between two points
+func Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	lat1r, lon1r := degToRad(lat1), degToRad(lon1)
+	lat2r, lon2r := degToRad(lat2), degToRad(lon2)
+
+	u := math.Sin((lat2r - lat1r) / 2)
+	v := math.Sin((lon2r - lon1r) / 2)
+	return 2.0 * EarthRadius * math.Asin(math.Sqrt(u*u+math.Cos(lat1r)*math.Cos(lat2r)*v*v))
+}
+
+// latitudeDistance returns the distance in meters between two latitudes on the same meridian
+func latitudeDistance(lat1, lat2 float64) float64 {
+	return EarthRadius * math.Abs(degToRad(lat2)-degToRad(lat1))
+}
+
+// Shape is a search area centred on a point: a circle if Radius is set,
+// otherwise a box of Width by Height. Dimensions are in meters.
+type Shape struct {
+	Longitude, Latitude float64
+	Radius              float64
+	Width, Height       float64
+	IsBox               bool
+}
+
+// Contains reports whether the point lies within the shape, returning its
+// distance in meters from the centre of the shape
+func (s *Shape) Contains(longitude, latitude float64) (float64, bool) {
+	if !s.IsBox {
+		distance := Distance(s.Longitude, s.Latitude, longitude, latitude)
+		return distance, distance <= s.Radius
+	}
+
+	// Latitude distance is cheaper to compute, so it is checked first
+	if latitudeDistance(latitude, s.Latitude) > s.Height/2 {
+		return 0, false
+	}
+	if Distance(longitude, latitude, s.Longitude, latitude) > s.Width/2 {
+		return 0, false
+	}
+	return Distance(s.Longitude, s.Latitude, longitude, latitude), true
+}
+
+// boundingBox returns the minimum longitude, minimum latitude, maximum
+// longitude and maximum latitude of a box enclosing the shape
+func (s *Shape) boundingBox() (float64, float64, float64, float64) {
+	height, width := s.Radius, s.Radius
+	if s.IsBox {
+		height, width = s.Height/2, s.Width/2
+	}
+
+	latDelta := radToDeg(height / EarthRadius)
+	longDeltaTop := radToDeg(width / EarthRadius / math.Cos(degToRad(s.Latitude+latDelta)))
+	longDeltaBottom := radToDeg(width / EarthRadius / math.Cos(degToRad(s.Latitude-latDelta)))
+
+	// The northern and southern hemispheres are mirrored, so the widest edge differs
+	if s.Latitude < 0 {
+		return s.Longitude - longDeltaBottom, s.Latitude - latDelta, s.Longitude + longDeltaBottom, s.Latitude + latDelta
+	}
+	return s.Longitude - longDeltaTop, s.Latitude - latDelta, s.Longitude + longDeltaTop, s.Latitude + latDelta
+}
+
+// estimateStepsByRadius returns the geohash precision whose cells are about
+// the size of the search radius at the given latitude
+func estimateStepsByRadius(rangeMeters, latitude float64) uint8 {
+	if rangeMeters == 0 {
+		return Step
+	}
+
+	step := 1
+	for rangeMeters < mercatorMax {
+		rangeMeters *= 2
+		step++
+	}
+	step -= 2 // Make sure the range is included in most of the base cases
+
+	// Cells get narrower towards the poles
+	if latitude > 66 || latitude < -66 {
+		step--
+		if latitude > 80 || latitude < -80 {
+			step--
+		}
+	}
+
+	return uint8(min(max(step, 1), Step))
+}
+
+// moveX moves the hash one cell east (d > 0) or west (d < 0)
+func moveX(hash Hash, d int) Hash {
+	x := hash.Bits & 0xaaaaaaaaaaaaaaaa
+	y := hash.Bits & 0x5555555555555555
+	zz := uint64(0x5555555555555555) >> (64 - uint(hash.Step)*2)
+
+	if d > 0 {
+		x = x + (zz + 1)
+	} else {
+		x = x | zz
+		x = x - (zz + 1)
+	}
+	x &= 0xaaaaaaaaaaaaaaaa >> (64 - uint(hash.Step)*2)
+	return Hash{Bits: x | y, Step: hash.Step}
+}
+
+// moveY moves the hash one cell north (d > 0) or south (d < 0)
+func moveY(hash Hash, d int) Hash {
+	x := hash.Bits & 0xaaaaaaaaaaaaaaaa
+	y := hash.Bits & 0x5555555555555555
+	zz := uint64(0xaaaaaaaaaaaaaaaa) >> (64 - uint(hash.Step)*2)
+
+	if d > 0 {
+		y = y + (zz + 1)
+	} else {
+		y = y | zz
+		y = y - (zz + 1)
+	}
+	y &= 0x5555555555555555 >> (64 - uint(hash.Step)*2)
+	return Hash{Bits: x | y, Step: hash.Step}
+}
+
+// searchAreas returns the cell containing the centre of the shape and its
+// eight neighbours (north, south, east, west, north-east, north-west,
+// south-east, south-west), with cells that cannot intersect the shape cleared
+func (s *Shape) searchAreas() [9]Hash {
+	minLon, minLat, maxLon, maxLat := s.boundingBox()
+
+	// For boxes, the distance from the centre to a corner bounds the search
+	radius := s.Radius
+	if s.IsBox {
+		radius = math.Sqrt((s.Width/2)*(s.Width/2) + (s.Height/2)*(s.Height/2))
+	}
+
+	steps := estimateStepsByRadius(radius, s.Latitude)
+	hash := encode(longitudeRange, latitudeRange, s.Longitude, s.Latitude, steps)
+	neighbors := neighborsOf(hash)
+
+	// Near the edge of a cell the estimated step may not be small enough for
+	// the neighbours to cover the whole search area
+	north, south := decode(neighbors[1]), decode(neighbors[2])
+	east, west := decode(neighbors[3]), decode(neighbors[4])
+	if steps > 1 && (north.Latitude.max < maxLat || south.Latitude.min > minLat ||
+		east.Longitude.max < maxLon || west.Longitude.min > minLon) {
+		steps--
+		hash = encode(longitudeRange, latitudeRange, s.Longitude, s.Latitude, steps)
+		neighbors = neighborsOf(hash)
+	}
+
+	// Exclude the neighbours that are outside the bounding box
+	if steps >= 2 {
+		area := decode(hash)
+		if area.Latitude.min < minLat {
+			neighbors[2], neighbors[7], neighbors[8] = Hash{}, Hash{}, Hash{}
+		}
+		if area.Latitude.max > maxLat {
+			neighbors[1], neighbors[5], neighbors[6] = Hash{}, Hash{}, Hash{}
+		}
+		if area.Longitude.min < minLon {
+			neighbors[4], neighbors[8], neighbors[6] = Hash{}, Hash{}, Hash{}
+		}
+		if area.Longitude.max > maxLon {
+			neighbors[3], neighbors[7], neighbors[5] = Hash{}, Hash{}, Hash{}
+		}
+	}
+
+	return neighbors
+}
+
+// neighborsOf returns the hash followed by its eight neighbours
+func neighborsOf(hash Hash) [9]Hash {
+	return [9]Hash{
+		hash,
+		moveY(hash, 1),
+		moveY(hash, -1),
+		moveX(hash, 1),
+		moveX(hash, -1),
+		moveY(moveX(hash, 1), 1),
+		moveY(moveX(hash, -1), 1),
+		moveY(moveX(hash, 1), -1),
+		moveY(moveX(hash, -1), -1),
+	}
+}
+
+// ScoreRanges returns the half-open [min, max) ranges of 52-bit geohash scores
+// that must be scanned to find every point within the shape. Points in these
+// ranges still need to be checked with Contains.
+func (s *Shape) ScoreRanges() [][2]uint64 {
+	areas := s.searchAreas()
+	ranges := make([][2]uint64, 0, len(areas))
+
+	seen := make(map[Hash]bool, len(areas))
+	for _, hash := range areas {
+		// With huge radii adjacent neighbours can be the same cell
+		if hash.isZero() || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		shift := 2 * (Step - uint(hash.Step))
+		ranges = append(ranges, [2]uint64{hash.Bits << shift, (hash.Bits + 1) << shift})
+	}
+	return ranges
+}
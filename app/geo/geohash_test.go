@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name      string
+		longitude float64
+		latitude  float64
+		expected  uint64
+	}{
+		{name: "palermo", longitude: 13.361389, latitude: 38.115556, expected: 3479099956230698},
+		{name: "catania", longitude: 15.087269, latitude: 37.502669, expected: 3479447370796909},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.longitude, tt.latitude); got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	longitude, latitude := Decode(3479099956230698)
+	if math.Abs(longitude-13.36138933897018433) > 1e-12 || math.Abs(latitude-38.11555639549629859) > 1e-12 {
+		t.Errorf("Expected (13.361389, 38.115556), got (%v, %v)", longitude, latitude)
+	}
+}
+
+func TestInterleave64(t *testing.T) {
+	for _, v := range [][2]uint32{{0, 0}, {1, 0}, {0, 1}, {0xdeadbeef, 0x12345678}, {0xffffffff, 0xffffffff}} {
+		separated := deinterleave64(interleave64(v[0], v[1]))
+		if uint32(separated) != v[0] || uint32(separated>>32) != v[1] {
+			t.Errorf("Expected %v after round trip, got (%d, %d)", v, uint32(separated), uint32(separated>>32))
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := String(3479099956230698); got != "sqc8b49rny0" {
+		t.Errorf("Expected sqc8b49rny0, got %s", got)
+	}
+	if got := String(3479447370796909); got != "sqdtr74hyu0" {
+		t.Errorf("Expected sqdtr74hyu0, got %s", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	lon1, lat1 := Decode(3479099956230698)
+	lon2, lat2 := Decode(3479447370796909)
+	if got := Distance(lon1, lat1, lon2, lat2); math.Abs(got-166274.1516) > 0.0001 {
+		t.Errorf("Expected 166274.1516, got %.4f", got)
+	}
+}
+
+func TestValidCoordinates(t *testing.T) {
+	if !ValidCoordinates(180, 85.05112878) || !ValidCoordinates(-180, -85.05112878) {
+		t.Errorf("Expected limits to be valid")
+	}
+	if ValidCoordinates(180.1, 0) || ValidCoordinates(0, 85.06) {
+		t.Errorf("Expected out of range coordinates to be invalid")
+	}
+}
+
+func TestShape_Contains(t *testing.T) {
+	circle := Shape{Longitude: 15, Latitude: 37, Radius: 200000}
+	if _, ok := circle.Contains(13.361389, 38.115556); !ok {
+		t.Errorf("Expected Palermo within 200 km of (15, 37)")
+	}
+	if _, ok := circle.Contains(12.758489, 38.788135); ok {
+		t.Errorf("Expected (12.758489, 38.788135) outside 200 km of (15, 37)")
+	}
+
+	box := Shape{Longitude: 15, Latitude: 37, Width: 400000, Height: 400000, IsBox: true}
+	if _, ok := box.Contains(12.758489, 38.788135); !ok {
+		t.Errorf("Expected (12.758489, 38.788135) within a 400 km box around (15, 37)")
+	}
+}
+
+// TestShape_ScoreRanges checks that every point inside the shape falls in one
+// of the score ranges to scan
+func TestShape_ScoreRanges(t *testing.T) {
+	shapes := []Shape{
+		{Longitude: 15, Latitude: 37, Radius: 200000},
+		{Longitude: 0, Latitude: 0, Radius: 1000},
+		{Longitude: -179.9, Latitude: 80, Radius: 50000},
+		{Longitude: 15, Latitude: 37, Width: 400000, Height: 100000, IsBox: true},
+	}
+
+	for _, shape := range shapes {
+		ranges := shape.ScoreRanges()
+		for dlon := -5.0; dlon <= 5; dlon += 0.05 {
+			for dlat := -3.0; dlat <= 3; dlat += 0.05 {
+				lon, lat := shape.Longitude+dlon, shape.Latitude+dlat
+				if !ValidCoordinates(lon, lat) {
+					continue
+				}
+				score := Encode(lon, lat)
+				lon, lat = Decode(score)
+				if _, ok := shape.Contains(lon, lat); !ok {
+					continue
+				}
+
+				found := false
+				for _, r := range ranges {
+					if score >= r[0] && score < r[1] {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("Point (%v, %v) inside %+v is not covered by %v", lon, lat, shape, ranges)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package glob implements the glob-style pattern matching used by Redis
+// commands such as KEYS and SCAN.
+package glob
+
+// maxNesting bounds the recursion of patterns with many '*', protecting the
+// server against abusive patterns
+const maxNesting = 1000
+
+// Match reports whether str matches the glob-style pattern, with the same
+// semantics as Redis's stringmatchlen:
+//
+//   - '*' matches any sequence of bytes, including an empty one
+//   - '?' matches any single byte
+//   - '[abc]' matches one of the listed bytes, '[^abc]' any byte except them,
+//     and '[a-z]' a range of bytes; ranges may be given in either order
+//   - '\' escapes the next byte, both inside and outside brackets
+//
+// Matching is byte-wise, and case-insensitive for ASCII letters if nocase is true.
+func Match(pattern, str string, nocase bool) bool {
+	skipLongerMatches := false
+	return match(pattern, str, nocase, &skipLongerMatches, 0)
+}
+
+// match implements Match. skipLongerMatches is set once the rest of the pattern
+// after a '*' fails to match anywhere in the string, in which case earlier '*'
+// cannot match either by consuming more of the string.
+func match(pattern, str string, nocase bool, skipLongerMatches *bool, nesting int) bool {
+	if nesting > maxNesting {
+		return false
+	}
+
+	p, s := 0, 0
+	for p < len(pattern) && s < len(str) {
+		switch pattern[p] {
+		case '*':
+			for p+1 < len(pattern) && pattern[p+1] == '*' {
+				p++
+			}
+			if p == len(pattern)-1 {
+				return true
+			}
+			for s < len(str) {
+				if match(pattern[p+1:], str[s:], nocase, skipLongerMatches, nesting+1) {
+					return true
+				}
+				if *skipLongerMatches {
+					return false
+				}
+				s++
+			}
+			*skipLongerMatches = true
+			return false
+
+		case '?':
+			s++
+
+		case '[':
+			p++
+			not := p < len(pattern) && pattern[p] == '^'
+			if not {
+				p++
+			}
+			matched := false
+			for {
+				if p < len(pattern) && pattern[p] == '\\' && len(pattern)-p >= 2 {
+					p++
+					if pattern[p] == str[s] {
+						matched = true
+					}
+				} else if p < len(pattern) && pattern[p] == ']' {
+					break
+				} else if p >= len(pattern) {
+					// Unterminated bracket, treat the end of the pattern as ']'
+					p--
+					break
+				} else if len(pattern)-p >= 3 && pattern[p+1] == '-' {
+					start, end, c := pattern[p], pattern[p+2], str[s]
+					if start > end {
+						start, end = end, start
+					}
+					if nocase {
+						start, end, c = toLower(start), toLower(end), toLower(c)
+					}
+					p += 2
+					if c >= start && c <= end {
+						matched = true
+					}
+				} else if equal(pattern[p], str[s], nocase) {
+					matched = true
+				}
+				p++
+			}
+			if not {
+				matched = !matched
+			}
+			if !matched {
+				return false
+			}
+			s++
+
+		case '\\':
+			if len(pattern)-p >= 2 {
+				p++
+			}
+			fallthrough
+
+		default:
+			if !equal(pattern[p], str[s], nocase) {
+				return false
+			}
+			s++
+		}
+
+		p++
+	}
+
+	// Trailing '*' also match the empty remainder of the string
+	if s == len(str) {
+		for p < len(pattern) && pattern[p] == '*' {
+			p++
+		}
+	}
+	return p == len(pattern) && s == len(str)
+}
+
+// equal compares two bytes, ignoring ASCII case if nocase is true
+func equal(a, b byte, nocase bool) bool {
+	if nocase {
+		return toLower(a) == toLower(b)
+	}
+	return a == b
+}
+
+// toLower converts an ASCII upper case letter to lower case
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,68 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		str      string
+		nocase   bool
+		expected bool
+	}{
+		{"*", "", false, true},
+		{"*", "anything", false, true},
+		{"", "", false, true},
+		{"", "a", false, false},
+		{"h?llo", "hello", false, true},
+		{"h?llo", "hllo", false, false},
+		{"h*llo", "hllo", false, true},
+		{"h*llo", "heeeello", false, true},
+		{"h[ae]llo", "hallo", false, true},
+		{"h[ae]llo", "hillo", false, false},
+		{"h[^e]llo", "hallo", false, true},
+		{"h[^e]llo", "hello", false, false},
+		{"h[a-b]llo", "hbllo", false, true},
+		{"h[b-a]llo", "hallo", false, true},
+		{"h[a-b]llo", "hcllo", false, false},
+		{"h[\\]]llo", "h]llo", false, true},
+		{"h\\*llo", "h*llo", false, true},
+		{"h\\*llo", "hello", false, false},
+		{"h[abc", "hb", false, true},
+		{"h[abc", "hd", false, false},
+		{"[", "a", false, false},
+		{"\\", "\\", false, true},
+		{"a*b*c", "abc", false, true},
+		{"a*b*c", "aXbYc", false, true},
+		{"a*b*c", "aXbY", false, false},
+		{"user:*", "user:1000", false, true},
+		{"user:*", "session:1000", false, false},
+		{"abc*", "ab", false, false},
+		{"ab**", "ab", false, true},
+		{"HELLO", "hello", false, false},
+		{"HELLO", "hello", true, true},
+		{"h[A-Z]llo", "hello", true, true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.str, tt.nocase); got != tt.expected {
+			t.Errorf("Match(%q, %q, %t): expected %t, got %t", tt.pattern, tt.str, tt.nocase, tt.expected, got)
+		}
+	}
+}
+
+func TestMatch_AbusivePattern(t *testing.T) {
+	// Without the early exit this pattern takes exponential time
+	pattern := strings.Repeat("a*", 30) + "b"
+	str := strings.Repeat("a", 100)
+	if Match(pattern, str, false) {
+		t.Error("Expected no match")
+	}
+
+	// Patterns nested beyond the limit never match
+	if Match(strings.Repeat("*a", maxNesting+1), strings.Repeat("a", maxNesting+1), false) {
+		t.Error("Expected deeply nested pattern not to match")
+	}
+}
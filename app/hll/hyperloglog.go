@@ -0,0 +1,571 @@
+// Package hll implements Redis's HyperLogLog cardinality estimator.
+//
+// Values use the exact byte layout of Redis, so a HyperLogLog created here can
+// be read by a real Redis server and vice versa. Every value starts with a
+// 16 byte header: the "HYLL" magic, the encoding, three unused bytes and the
+// cached cardinality as a little endian integer whose most significant bit
+// marks the cache as stale. The header is followed by the registers, either
+// packed six bits at a time (dense) or run-length encoded (sparse).
+package hll
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/dotslash21/redis-clone/app/errors"
+)
+
+const (
+	// precision is the number of hash bits used to select a register
+	precision = 14
+	// registers is the number of registers in every HyperLogLog
+	registers = 1 << precision
+	// registerBits is the width of a dense register
+	registerBits = 6
+	// registerMax is the largest value a dense register can hold
+	registerMax = 1<<registerBits - 1
+	// hashBits is the number of hash bits left after selecting a register
+	hashBits = 64 - precision
+
+	// HeaderSize is the size of the header preceding the registers
+	HeaderSize = 16
+	// DenseSize is the size of a dense HyperLogLog, header included
+	DenseSize = HeaderSize + (registers*registerBits+7)/8
+
+	// DefaultSparseMaxBytes is the default hll-sparse-max-bytes: sparse values
+	// that would grow past this size are converted to the dense encoding
+	DefaultSparseMaxBytes = 3000
+
+	encodingDense  = 0
+	encodingSparse = 1
+
+	sparseXZeroBit     = 0x40
+	sparseValBit       = 0x80
+	sparseValMaxValue  = 32
+	sparseValMaxLen    = 4
+	sparseZeroMaxLen   = 64
+	cacheInvalidMarker = 1 << 7
+
+	hashSeed = 0xadc83b19
+	alphaInf = 0.721347520444481703680 // 0.5/ln(2)
+)
+
+var magic = []byte("HYLL")
+
+// ErrInvalid is returned for a value that is not a HyperLogLog
+var ErrInvalid = errors.NewWithCode("WRONGTYPE", "Key is not a valid HyperLogLog string value.")
+
+// ErrCorrupted is returned for a HyperLogLog whose sparse encoding is malformed
+var ErrCorrupted = errors.NewWithCode("INVALIDOBJ", "Corrupted HLL object detected")
+
+// New returns an empty HyperLogLog in the sparse encoding
+func New() []byte {
+	b := make([]byte, HeaderSize, HeaderSize+2)
+	copy(b, magic)
+	b[4] = encodingSparse
+	return appendXZero(b, registers)
+}
+
+// IsValid reports whether b has a valid HyperLogLog header and size
+func IsValid(b []byte) bool {
+	if len(b) < HeaderSize || string(b[:4]) != string(magic) {
+		return false
+	}
+	switch b[4] {
+	case encodingDense:
+		return len(b) == DenseSize
+	case encodingSparse:
+		return true
+	default:
+		return false
+	}
+}
+
+// Add adds element to the HyperLogLog, which must be valid. It returns the
+// possibly reallocated value and whether any register changed, in which case
+// the cached cardinality has been invalidated.
+func Add(b []byte, element []byte, sparseMaxBytes int) ([]byte, bool, error) {
+	index, count := patternLength(element)
+
+	var changed bool
+	var err error
+	if b[4] == encodingDense {
+		changed = denseSet(b[HeaderSize:], index, count)
+	} else {
+		b, changed, err = sparseSet(b, index, count, sparseMaxBytes)
+		if err != nil {
+			return b, false, err
+		}
+	}
+
+	if changed {
+		invalidateCache(b)
+	}
+	return b, changed, nil
+}
+
+// Count returns the estimated cardinality of the HyperLogLog, which must be
+// valid. If the cached cardinality is stale, it is recomputed and written back
+// into b, and updated is true.
+func Count(b []byte) (cardinality uint64, updated bool, err error) {
+	if b[15]&cacheInvalidMarker == 0 {
+		return binary.LittleEndian.Uint64(b[8:16]), false, nil
+	}
+
+	var histogram [hashBits + 2]int
+	if b[4] == encodingDense {
+		denseHistogram(b[HeaderSize:], &histogram)
+	} else if !sparseHistogram(b[HeaderSize:], &histogram) {
+		return 0, false, ErrCorrupted
+	}
+
+	cardinality = estimate(&histogram)
+	binary.LittleEndian.PutUint64(b[8:16], cardinality)
+	return cardinality, true, nil
+}
+
+// Registers holds unpacked register values, used to merge several HyperLogLogs
+type Registers [registers]uint8
+
+// Merge sets every register in r to the maximum of itself and the matching
+// register of the HyperLogLog b, which must be valid.
+func (r *Registers) Merge(b []byte) error {
+	if b[4] == encodingDense {
+		for i := range r {
+			r[i] = max(r[i], denseGet(b[HeaderSize:], i))
+		}
+		return nil
+	}
+
+	index := 0
+	err := forEachSparseRun(b[HeaderSize:], func(value uint8, runLength int) bool {
+		if index+runLength > registers {
+			return false
+		}
+		if value != 0 {
+			for i := index; i < index+runLength; i++ {
+				r[i] = max(r[i], value)
+			}
+		}
+		index += runLength
+		return true
+	})
+	if err != nil || index != registers {
+		return ErrCorrupted
+	}
+	return nil
+}
+
+// Count returns the estimated cardinality of the merged registers
+func (r *Registers) Count() uint64 {
+	var histogram [hashBits + 2]int
+	for _, value := range r {
+		histogram[value]++
+	}
+	return estimate(&histogram)
+}
+
+// Store writes the registers into the HyperLogLog b, which must be valid,
+// keeping the larger value of each register. Sparse values are converted to
+// dense when dense is true or when they would outgrow sparseMaxBytes. The
+// cached cardinality is invalidated.
+func (r *Registers) Store(b []byte, dense bool, sparseMaxBytes int) ([]byte, error) {
+	var err error
+	if dense && b[4] == encodingSparse {
+		if b, err = sparseToDense(b); err != nil {
+			return b, err
+		}
+	}
+
+	for i, value := range r {
+		if value == 0 {
+			continue
+		}
+		if b[4] == encodingDense {
+			denseSet(b[HeaderSize:], i, value)
+		} else if b, _, err = sparseSet(b, i, value, sparseMaxBytes); err != nil {
+			return b, err
+		}
+	}
+
+	invalidateCache(b)
+	return b, nil
+}
+
+// IsDense reports whether the valid HyperLogLog b uses the dense encoding
+func IsDense(b []byte) bool {
+	return b[4] == encodingDense
+}
+
+// invalidateCache marks the cached cardinality as stale
+func invalidateCache(b []byte) {
+	b[15] |= cacheInvalidMarker
+}
+
+// murmurHash64A is the 64-bit MurmurHash2 variant used by Redis
+func murmurHash64A(data []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+
+	h := seed ^ (uint64(len(data)) * m)
+
+	for len(data) >= 8 {
+		k := binary.LittleEndian.Uint64(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		h ^= k
+		h *= m
+		data = data[8:]
+	}
+
+	if len(data) > 0 {
+		for i := len(data) - 1; i >= 0; i-- {
+			h ^= uint64(data[i]) << (8 * i)
+		}
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+// patternLength returns the register an element maps to and the length of the
+// run of zero bits (plus one) in the remaining hash bits
+func patternLength(element []byte) (int, uint8) {
+	hash := murmurHash64A(element, hashSeed)
+	index := int(hash & (registers - 1))
+
+	// Setting a bit past the useful range bounds the count to hashBits+1
+	hash >>= precision
+	hash |= 1 << hashBits
+	return index, uint8(bits.TrailingZeros64(hash) + 1)
+}
+
+// denseGet returns register i of the packed dense registers
+func denseGet(regs []byte, i int) uint8 {
+	byteIndex := i * registerBits / 8
+	fb := uint(i*registerBits) & 7
+	b0 := uint(regs[byteIndex])
+	var b1 uint
+	if byteIndex+1 < len(regs) {
+		b1 = uint(regs[byteIndex+1])
+	}
+	return uint8(((b0 >> fb) | (b1 << (8 - fb))) & registerMax)
+}
+
+// denseSet raises register i to value if value is larger, reporting whether it changed
+func denseSet(regs []byte, i int, value uint8) bool {
+	if value <= denseGet(regs, i) {
+		return false
+	}
+
+	byteIndex := i * registerBits / 8
+	fb := uint(i*registerBits) & 7
+	v := uint(value)
+
+	regs[byteIndex] &^= byte(registerMax << fb)
+	regs[byteIndex] |= byte(v << fb)
+	if byteIndex+1 < len(regs) {
+		regs[byteIndex+1] &^= byte(registerMax >> (8 - fb))
+		regs[byteIndex+1] |= byte(v >> (8 - fb))
+	}
+	return true
+}
+
+// denseHistogram counts how many dense registers hold each value
+func denseHistogram(regs []byte, histogram *[hashBits + 2]int) {
+	for i := 0; i < registers; i++ {
+		histogram[denseGet(regs, i)]++
+	}
+}
+
+// Sparse opcodes:
+//
+//	ZERO:  00xxxxxx          - a run of 1-64 zero registers
+//	XZERO: 01xxxxxx yyyyyyyy - a run of 1-16384 zero registers
+//	VAL:   1vvvvvxx          - a run of 1-4 registers holding the value 1-32
+func isSparseZero(op byte) bool  { return op&0xc0 == 0 }
+func isSparseXZero(op byte) bool { return op&0xc0 == sparseXZeroBit }
+func isSparseVal(op byte) bool   { return op&sparseValBit != 0 }
+
+func sparseZeroLen(op byte) int         { return int(op&0x3f) + 1 }
+func sparseXZeroLen(op0, op1 byte) int  { return (int(op0&0x3f)<<8 | int(op1)) + 1 }
+func sparseValValue(op byte) uint8      { return (op>>2)&0x1f + 1 }
+func sparseValLen(op byte) int          { return int(op&0x3) + 1 }
+func sparseVal(value uint8, n int) byte { return (value-1)<<2 | byte(n-1) | sparseValBit }
+func appendZero(b []byte, n int) []byte { return append(b, byte(n-1)) }
+func appendXZero(b []byte, n int) []byte {
+	return append(b, byte((n-1)>>8)|sparseXZeroBit, byte((n-1)&0xff))
+}
+
+// appendZeroRun appends the shortest opcode describing n zero registers
+func appendZeroRun(b []byte, n int) []byte {
+	if n > sparseZeroMaxLen {
+		return appendXZero(b, n)
+	}
+	return appendZero(b, n)
+}
+
+// forEachSparseRun calls fn for every opcode with the register value and run
+// length it describes, stopping early if fn returns false
+func forEachSparseRun(ops []byte, fn func(value uint8, runLength int) bool) error {
+	for p := 0; p < len(ops); {
+		op := ops[p]
+		var value uint8
+		var runLength int
+
+		switch {
+		case isSparseZero(op):
+			runLength = sparseZeroLen(op)
+			p++
+		case isSparseXZero(op):
+			if p+1 >= len(ops) {
+				return ErrCorrupted
+			}
+			runLength = sparseXZeroLen(op, ops[p+1])
+			p += 2
+		default:
+			value, runLength = sparseValValue(op), sparseValLen(op)
+			p++
+		}
+
+		if !fn(value, runLength) {
+			return ErrCorrupted
+		}
+	}
+	return nil
+}
+
+// sparseHistogram counts how many sparse registers hold each value, reporting
+// false if the encoding does not describe exactly the expected number of registers
+func sparseHistogram(ops []byte, histogram *[hashBits + 2]int) bool {
+	index := 0
+	err := forEachSparseRun(ops, func(value uint8, runLength int) bool {
+		histogram[value] += runLength
+		index += runLength
+		return index <= registers
+	})
+	return err == nil && index == registers
+}
+
+// sparseToDense converts a sparse HyperLogLog into the dense encoding
+func sparseToDense(b []byte) ([]byte, error) {
+	dense := make([]byte, DenseSize)
+	copy(dense, b[:HeaderSize])
+	dense[4] = encodingDense
+
+	index := 0
+	err := forEachSparseRun(b[HeaderSize:], func(value uint8, runLength int) bool {
+		if index+runLength > registers {
+			return false
+		}
+		if value != 0 {
+			for i := index; i < index+runLength; i++ {
+				denseSet(dense[HeaderSize:], i, value)
+			}
+		}
+		index += runLength
+		return true
+	})
+	if err != nil || index != registers {
+		return b, ErrCorrupted
+	}
+	return dense, nil
+}
+
+// sparseSet raises register index to count in a sparse HyperLogLog, splitting
+// the opcode that covers it and merging adjacent VAL opcodes afterwards, exactly
+// as Redis does. The value is promoted to the dense encoding if count cannot be
+// represented or the result would outgrow sparseMaxBytes.
+func sparseSet(b []byte, index int, count uint8, sparseMaxBytes int) ([]byte, bool, error) {
+	if count > sparseValMaxValue {
+		return promoteAndSet(b, index, count)
+	}
+
+	// Step 1: locate the opcode covering the register
+	ops := b[HeaderSize:]
+	p, first, span, prev := 0, 0, 0, -1
+	for p < len(ops) {
+		opLen := 1
+		switch op := ops[p]; {
+		case isSparseZero(op):
+			span = sparseZeroLen(op)
+		case isSparseVal(op):
+			span = sparseValLen(op)
+		default:
+			if p+1 >= len(ops) {
+				return b, false, ErrCorrupted
+			}
+			span = sparseXZeroLen(op, ops[p+1])
+			opLen = 2
+		}
+		if index <= first+span-1 {
+			break
+		}
+		prev = p
+		p += opLen
+		first += span
+	}
+	if span == 0 || p >= len(ops) {
+		return b, false, ErrCorrupted
+	}
+
+	op := ops[p]
+	isZero, isXZero, isVal := isSparseZero(op), isSparseXZero(op), isSparseVal(op)
+	var runLength int
+	switch {
+	case isZero:
+		runLength = sparseZeroLen(op)
+	case isXZero:
+		runLength = sparseXZeroLen(op, ops[p+1])
+	default:
+		runLength = sparseValLen(op)
+	}
+
+	// Step 2: update in place when possible, otherwise split the opcode
+	updated := false
+	if isVal {
+		if sparseValValue(op) >= count {
+			return b, false, nil
+		}
+		if runLength == 1 {
+			ops[p] = sparseVal(count, 1)
+			updated = true
+		}
+	}
+	if isZero && runLength == 1 {
+		ops[p] = sparseVal(count, 1)
+		updated = true
+	}
+
+	if !updated {
+		last := first + span - 1
+		seq := make([]byte, 0, 5)
+
+		if isZero || isXZero {
+			if index != first {
+				seq = appendZeroRun(seq, index-first)
+			}
+			seq = append(seq, sparseVal(count, 1))
+			if index != last {
+				seq = appendZeroRun(seq, last-index)
+			}
+		} else {
+			current := sparseValValue(op)
+			if index != first {
+				seq = append(seq, sparseVal(current, index-first))
+			}
+			seq = append(seq, sparseVal(count, 1))
+			if index != last {
+				seq = append(seq, sparseVal(current, last-index))
+			}
+		}
+
+		// Step 3: substitute the new sequence for the old opcode
+		oldLen := 1
+		if isXZero {
+			oldLen = 2
+		}
+		delta := len(seq) - oldLen
+		if delta > 0 && len(b)+delta > sparseMaxBytes {
+			return promoteAndSet(b, index, count)
+		}
+
+		start := HeaderSize + p
+		tail := append([]byte(nil), b[start+oldLen:]...)
+		b = append(append(b[:start], seq...), tail...)
+		ops = b[HeaderSize:]
+	}
+
+	// Step 4: merge adjacent VAL opcodes with the same value
+	p = 0
+	if prev >= 0 {
+		p = prev
+	}
+	for scan := 5; p < len(ops) && scan > 0; scan-- {
+		switch {
+		case isSparseXZero(ops[p]):
+			p += 2
+			continue
+		case isSparseZero(ops[p]):
+			p++
+			continue
+		}
+
+		if p+1 < len(ops) && isSparseVal(ops[p+1]) {
+			v1, v2 := sparseValValue(ops[p]), sparseValValue(ops[p+1])
+			if merged := sparseValLen(ops[p]) + sparseValLen(ops[p+1]); v1 == v2 && merged <= sparseValMaxLen {
+				ops[p+1] = sparseVal(v1, merged)
+				copy(ops[p:], ops[p+1:])
+				b = b[:len(b)-1]
+				ops = b[HeaderSize:]
+				continue
+			}
+		}
+		p++
+	}
+
+	return b, true, nil
+}
+
+// promoteAndSet converts a sparse HyperLogLog to dense and sets the register
+func promoteAndSet(b []byte, index int, count uint8) ([]byte, bool, error) {
+	dense, err := sparseToDense(b)
+	if err != nil {
+		return b, false, err
+	}
+	return dense, denseSet(dense[HeaderSize:], index, count), nil
+}
+
+// estimate computes the cardinality from a register histogram using the
+// estimator from "New cardinality estimation algorithms for HyperLogLog
+// sketches" (Otmar Ertl, arXiv:1702.01284), as Redis does
+func estimate(histogram *[hashBits + 2]int) uint64 {
+	m := float64(registers)
+
+	z := m * tau((m-float64(histogram[hashBits+1]))/m)
+	for j := hashBits; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+	z += m * sigma(float64(histogram[0])/m)
+
+	return uint64(math.Round(alphaInf * m * m / z))
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		zPrime := z
+		z += x * y
+		y += y
+		if zPrime == z {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		zPrime := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+		if zPrime == z {
+			return z / 3
+		}
+	}
+}
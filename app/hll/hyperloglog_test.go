@@ -0,0 +1,184 @@
+package hll
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	b := New()
+
+	// An empty HyperLogLog is a single XZERO opcode covering every register
+	expected := "HYLL\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x7f\xff"
+	if string(b) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(b))
+	}
+
+	if !IsValid(b) {
+		t.Error("Expected a new HyperLogLog to be valid")
+	}
+
+	count, updated, err := Count(b)
+	if err != nil || count != 0 || updated {
+		t.Errorf("Expected (0, false, nil), got (%d, %t, %v)", count, updated, err)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value []byte
+		valid bool
+	}{
+		{name: "empty", value: []byte{}, valid: false},
+		{name: "plain string", value: []byte("hello world, not an hll"), valid: false},
+		{name: "bad encoding", value: append([]byte("HYLL\x02"), make([]byte, 11)...), valid: false},
+		{name: "truncated dense", value: append([]byte("HYLL\x00"), make([]byte, 100)...), valid: false},
+		{name: "dense", value: append([]byte("HYLL\x00"), make([]byte, DenseSize-5)...), valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if IsValid(tt.value) != tt.valid {
+				t.Errorf("Expected IsValid to return %t", tt.valid)
+			}
+		})
+	}
+}
+
+func TestAddAndCount(t *testing.T) {
+	b := New()
+
+	for _, element := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		var err error
+		if b, _, err = Add(b, []byte(element), DefaultSparseMaxBytes); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Adding an existing element does not change any register
+	var changed bool
+	b, changed, _ = Add(b, []byte("a"), DefaultSparseMaxBytes)
+	if changed {
+		t.Error("Expected re-adding an element to leave the registers unchanged")
+	}
+
+	if b[15]&cacheInvalidMarker == 0 {
+		t.Error("Expected adding elements to invalidate the cached cardinality")
+	}
+
+	count, updated, err := Count(b)
+	if err != nil || count != 7 || !updated {
+		t.Errorf("Expected (7, true, nil), got (%d, %t, %v)", count, updated, err)
+	}
+
+	// The second count is served from the cache
+	count, updated, _ = Count(b)
+	if count != 7 || updated {
+		t.Errorf("Expected cached (7, false), got (%d, %t)", count, updated)
+	}
+}
+
+func TestAccuracyAndPromotion(t *testing.T) {
+	b := New()
+	const n = 100000
+
+	for i := 0; i < n; i++ {
+		var err error
+		if b, _, err = Add(b, []byte(strconv.Itoa(i)), DefaultSparseMaxBytes); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if !IsDense(b) || len(b) != DenseSize {
+		t.Fatalf("Expected the HyperLogLog to be promoted to dense, got %d bytes", len(b))
+	}
+
+	count, _, _ := Count(b)
+	if relErr := math.Abs(float64(count)-n) / n; relErr > 0.02 {
+		t.Errorf("Expected estimate within 2%% of %d, got %d", n, count)
+	}
+}
+
+func TestSparseAndDenseAgree(t *testing.T) {
+	sparse := New()
+	dense, _ := sparseToDense(New())
+
+	for i := 0; i < 2000; i++ {
+		element := []byte("element:" + strconv.Itoa(i))
+		sparse, _, _ = Add(sparse, element, math.MaxInt)
+		dense, _, _ = Add(dense, element, math.MaxInt)
+	}
+
+	if IsDense(sparse) {
+		t.Fatal("Expected the HyperLogLog to stay sparse")
+	}
+
+	sparseCount, _, err := Count(sparse)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	denseCount, _, _ := Count(dense)
+	if sparseCount != denseCount {
+		t.Errorf("Expected sparse and dense counts to match, got %d and %d", sparseCount, denseCount)
+	}
+
+	converted, err := sparseToDense(sparse)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(converted[HeaderSize:]) != string(dense[HeaderSize:]) {
+		t.Error("Expected converting sparse to dense to produce the same registers")
+	}
+}
+
+func TestRegistersMergeAndStore(t *testing.T) {
+	first, second := New(), New()
+	for i := 0; i < 500; i++ {
+		first, _, _ = Add(first, []byte("a"+strconv.Itoa(i)), DefaultSparseMaxBytes)
+		second, _, _ = Add(second, []byte("b"+strconv.Itoa(i)), DefaultSparseMaxBytes)
+	}
+
+	var registers Registers
+	if err := registers.Merge(first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := registers.Merge(second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	union := registers.Count()
+	if relErr := math.Abs(float64(union)-1000) / 1000; relErr > 0.02 {
+		t.Errorf("Expected union estimate close to 1000, got %d", union)
+	}
+
+	stored, err := registers.Store(New(), false, DefaultSparseMaxBytes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count, _, _ := Count(stored); count != union {
+		t.Errorf("Expected stored HyperLogLog to count %d, got %d", union, count)
+	}
+
+	stored, _ = registers.Store(New(), true, DefaultSparseMaxBytes)
+	if !IsDense(stored) {
+		t.Error("Expected Store with dense=true to produce a dense HyperLogLog")
+	}
+}
+
+func TestCorruptedSparse(t *testing.T) {
+	// A sparse HyperLogLog whose opcodes cover fewer registers than expected
+	b := New()
+	b[HeaderSize+1] = 0x00
+	b[15] |= cacheInvalidMarker
+
+	if _, _, err := Count(b); err != ErrCorrupted {
+		t.Errorf("Expected ErrCorrupted, got %v", err)
+	}
+
+	var registers Registers
+	if err := registers.Merge(b); err != ErrCorrupted {
+		t.Errorf("Expected ErrCorrupted, got %v", err)
+	}
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"log"
+	"os"
 
+	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/server"
 )
 
+// main starts the server like redis-server, configured by an optional config
+// file followed by overrides, such as "redis.conf --port 7000"
 func main() {
-	srv, err := server.NewServer(6379)
+	if err := config.LoadArgs(os.Args[1:]); err != nil {
+		log.Fatalf("*** FATAL CONFIG FILE ERROR ***\n%v", err)
+	}
+
+	srv, err := server.NewServer(config.GetInt("port", server.PORT))
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
 	}
@@ -0,0 +1,208 @@
+package resp
+
+import (
+	"bufio"
+	"math"
+	"strconv"
+)
+
+const (
+	// RESP2 is the protocol version spoken by clients by default
+	RESP2 = 2
+	// RESP3 is the protocol version negotiated with HELLO 3
+	RESP3 = 3
+)
+
+const (
+	// Protocol prefixes
+	SimpleStringPrefix = '+'
+	ErrorPrefix        = '-'
+	IntegerPrefix      = ':'
+	BulkStringPrefix   = '$'
+	ArrayPrefix        = '*'
+	NullPrefix         = '_'
+	MapPrefix          = '%'
+	SetPrefix          = '~'
+	DoublePrefix       = ','
+	BooleanPrefix      = '#'
+	BigNumberPrefix    = '('
+	VerbatimPrefix     = '='
+	PushPrefix         = '>'
+
+	CRLF = "\r\n"
+)
+
+// Encoder writes replies to a buffered writer in the RESP2 or RESP3 protocol.
+// Replies are streamed element by element, so aggregates are never built up
+// as a single string. Nothing reaches the underlying writer until it is flushed.
+type Encoder struct {
+	w        *bufio.Writer
+	protocol int
+	// scratch is used to format numbers without allocating
+	scratch [64]byte
+}
+
+// NewEncoder creates an encoder writing RESP2 to w.
+func NewEncoder(w *bufio.Writer) *Encoder {
+	return &Encoder{w: w, protocol: RESP2}
+}
+
+// Protocol returns the protocol version replies are encoded with.
+func (e *Encoder) Protocol() int {
+	return e.protocol
+}
+
+// SetProtocol sets the protocol version replies are encoded with, RESP2 or RESP3.
+func (e *Encoder) SetProtocol(protocol int) {
+	e.protocol = protocol
+}
+
+// Encode writes a reply. Errors from the underlying writer are sticky, so the
+// error returned here or by Flush reports the first failed write.
+func (e *Encoder) Encode(r Reply) error {
+	resp3 := e.protocol >= RESP3
+
+	switch r.Kind {
+	case KindSimpleString:
+		e.writeLine(SimpleStringPrefix, r.Str)
+	case KindError:
+		e.writeLine(ErrorPrefix, r.Str)
+	case KindInteger:
+		e.writeInt(IntegerPrefix, r.Int)
+	case KindBulkString:
+		e.writeBulk(r.Str)
+	case KindNull, KindNullArray:
+		switch {
+		case resp3:
+			e.w.WriteString("_\r\n")
+		case r.Kind == KindNull:
+			e.w.WriteString("$-1\r\n")
+		default:
+			e.w.WriteString("*-1\r\n")
+		}
+	case KindArray:
+		e.writeAggregate(ArrayPrefix, r.Elems)
+	case KindMap:
+		if resp3 {
+			e.writeInt(MapPrefix, int64(len(r.Elems)/2))
+			e.writeElems(r.Elems)
+		} else {
+			e.writeAggregate(ArrayPrefix, r.Elems)
+		}
+	case KindSet:
+		if resp3 {
+			e.writeAggregate(SetPrefix, r.Elems)
+		} else {
+			e.writeAggregate(ArrayPrefix, r.Elems)
+		}
+	case KindPush:
+		if resp3 {
+			e.writeAggregate(PushPrefix, r.Elems)
+		} else {
+			e.writeAggregate(ArrayPrefix, r.Elems)
+		}
+	case KindDouble:
+		text := append(e.scratch[:0], r.Str...)
+		if r.Str == "" {
+			text = appendDouble(text, r.Float)
+		}
+		if resp3 {
+			e.w.WriteByte(DoublePrefix)
+			e.w.Write(text)
+			e.w.WriteString(CRLF)
+		} else {
+			e.writeBulkBytes(text)
+		}
+	case KindBoolean:
+		switch {
+		case !resp3:
+			e.writeInt(IntegerPrefix, r.Int)
+		case r.Int != 0:
+			e.w.WriteString("#t\r\n")
+		default:
+			e.w.WriteString("#f\r\n")
+		}
+	case KindBigNumber:
+		if resp3 {
+			e.writeLine(BigNumberPrefix, r.Str)
+		} else {
+			e.writeBulk(r.Str)
+		}
+	case KindVerbatim:
+		if resp3 {
+			e.writeInt(VerbatimPrefix, int64(len(r.Format)+1+len(r.Str)))
+			e.w.WriteString(r.Format)
+			e.w.WriteByte(':')
+			e.w.WriteString(r.Str)
+			e.w.WriteString(CRLF)
+		} else {
+			e.writeBulk(r.Str)
+		}
+	}
+
+	// bufio.Writer keeps the first write error, which an empty write reports
+	_, err := e.w.Write(nil)
+	return err
+}
+
+// Flush writes any buffered replies to the underlying writer.
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// writeLine writes a prefixed line
+func (e *Encoder) writeLine(prefix byte, line string) {
+	e.w.WriteByte(prefix)
+	e.w.WriteString(line)
+	e.w.WriteString(CRLF)
+}
+
+// writeInt writes a prefixed integer line, used for integers and aggregate headers
+func (e *Encoder) writeInt(prefix byte, n int64) {
+	e.w.WriteByte(prefix)
+	e.w.Write(strconv.AppendInt(e.scratch[:0], n, 10))
+	e.w.WriteString(CRLF)
+}
+
+// writeBulk writes a bulk string
+func (e *Encoder) writeBulk(str string) {
+	e.writeInt(BulkStringPrefix, int64(len(str)))
+	e.w.WriteString(str)
+	e.w.WriteString(CRLF)
+}
+
+// writeBulkBytes writes a bulk string from bytes, which may alias the scratch buffer
+func (e *Encoder) writeBulkBytes(b []byte) {
+	e.w.WriteByte(BulkStringPrefix)
+	e.w.WriteString(strconv.Itoa(len(b)))
+	e.w.WriteString(CRLF)
+	e.w.Write(b)
+	e.w.WriteString(CRLF)
+}
+
+// writeAggregate writes an aggregate header followed by its elements
+func (e *Encoder) writeAggregate(prefix byte, elems []Reply) {
+	e.writeInt(prefix, int64(len(elems)))
+	e.writeElems(elems)
+}
+
+// writeElems writes the elements of an aggregate
+func (e *Encoder) writeElems(elems []Reply) {
+	for _, elem := range elems {
+		e.Encode(elem)
+	}
+}
+
+// appendDouble formats a double the way Redis does, using the shortest
+// representation that round-trips and inf, -inf or nan for special values
+func appendDouble(dst []byte, f float64) []byte {
+	switch {
+	case math.IsInf(f, 1):
+		return append(dst, "inf"...)
+	case math.IsInf(f, -1):
+		return append(dst, "-inf"...)
+	case math.IsNaN(f):
+		return append(dst, "nan"...)
+	}
+	return strconv.AppendFloat(dst, f, 'g', -1, 64)
+}
@@ -0,0 +1,90 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// encode encodes a reply with the given protocol version and returns the output
+func encode(t *testing.T, r Reply, protocol int) string {
+	t.Helper()
+	var b strings.Builder
+	w := bufio.NewWriter(&b)
+	e := NewEncoder(w)
+	e.SetProtocol(protocol)
+	if err := e.Encode(r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return b.String()
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply Reply
+		resp2 string
+		resp3 string
+	}{
+		{"simple string", SimpleString("OK"), "+OK\r\n", "+OK\r\n"},
+		{"error", Error("ERR syntax error"), "-ERR syntax error\r\n", "-ERR syntax error\r\n"},
+		{"integer", Integer(-42), ":-42\r\n", ":-42\r\n"},
+		{"bulk string", BulkString("hello"), "$5\r\nhello\r\n", "$5\r\nhello\r\n"},
+		{"empty bulk string", BulkString(""), "$0\r\n\r\n", "$0\r\n\r\n"},
+		{"null", Null(), "$-1\r\n", "_\r\n"},
+		{"null array", NullArray(), "*-1\r\n", "_\r\n"},
+		{"empty array", Array(nil), "*0\r\n", "*0\r\n"},
+		{"bulk strings", BulkStrings([]string{"a", "bc"}), "*2\r\n$1\r\na\r\n$2\r\nbc\r\n", "*2\r\n$1\r\na\r\n$2\r\nbc\r\n"},
+		{"nested array", Array([]Reply{Integer(1), Array([]Reply{Null()})}), "*2\r\n:1\r\n*1\r\n$-1\r\n", "*2\r\n:1\r\n*1\r\n_\r\n"},
+		{"map", Map([]Reply{BulkString("key"), Integer(1)}), "*2\r\n$3\r\nkey\r\n:1\r\n", "%1\r\n$3\r\nkey\r\n:1\r\n"},
+		{"set", Set([]Reply{BulkString("a")}), "*1\r\n$1\r\na\r\n", "~1\r\n$1\r\na\r\n"},
+		{"push", Push([]Reply{BulkString("message")}), "*1\r\n$7\r\nmessage\r\n", ">1\r\n$7\r\nmessage\r\n"},
+		{"double", Double(3.5), "$3\r\n3.5\r\n", ",3.5\r\n"},
+		{"double integral", Double(10), "$2\r\n10\r\n", ",10\r\n"},
+		{"double inf", Double(math.Inf(1)), "$3\r\ninf\r\n", ",inf\r\n"},
+		{"double -inf", Double(math.Inf(-1)), "$4\r\n-inf\r\n", ",-inf\r\n"},
+		{"double nan", Double(math.NaN()), "$3\r\nnan\r\n", ",nan\r\n"},
+		{"formatted double", FormattedDouble(0.5, "0.50"), "$4\r\n0.50\r\n", ",0.50\r\n"},
+		{"true", Boolean(true), ":1\r\n", "#t\r\n"},
+		{"false", Boolean(false), ":0\r\n", "#f\r\n"},
+		{"big number", BigNumber("3492890328409238509324850943850943825024385"), "$43\r\n3492890328409238509324850943850943825024385\r\n", "(3492890328409238509324850943850943825024385\r\n"},
+		{"verbatim", Verbatim("txt", "Some string"), "$11\r\nSome string\r\n", "=15\r\ntxt:Some string\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encode(t, tt.reply, RESP2); got != tt.resp2 {
+				t.Errorf("RESP2: expected %q, got %q", tt.resp2, got)
+			}
+			if got := encode(t, tt.reply, RESP3); got != tt.resp3 {
+				t.Errorf("RESP3: expected %q, got %q", tt.resp3, got)
+			}
+		})
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestEncoder_WriteError(t *testing.T) {
+	// A small buffer forces the bulk string through to the failing writer
+	e := NewEncoder(bufio.NewWriterSize(failingWriter{}, 16))
+	if e.Protocol() != RESP2 {
+		t.Errorf("Expected new encoders to use RESP2, got %d", e.Protocol())
+	}
+	if err := e.Encode(BulkString(strings.Repeat("x", 100))); err == nil {
+		t.Error("Expected the write error to be reported")
+	}
+	if err := e.Flush(); err == nil {
+		t.Error("Expected the write error to be sticky")
+	}
+}
@@ -0,0 +1,380 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+const (
+	// ReadBufferSize is the size of a connection's read buffer, the same as
+	// Redis's PROTO_IOBUF_LEN. Pipelined commands that fit in the buffer are
+	// parsed without another read from the connection.
+	ReadBufferSize = 16 * 1024
+	// DefaultMaxBulkLen is the default proto-max-bulk-len, the largest bulk
+	// string argument accepted from a client
+	DefaultMaxBulkLen = 512 * 1024 * 1024
+	// DefaultMaxMultibulkLen is the largest number of arguments accepted in a
+	// single command, Redis's INT_MAX limit
+	DefaultMaxMultibulkLen = math.MaxInt32
+	// maxUnauthenticatedMultibulkLen and maxUnauthenticatedBulkLen are the
+	// much lower limits applied to clients that haven't authenticated yet
+	maxUnauthenticatedMultibulkLen = 10
+	maxUnauthenticatedBulkLen      = 16 * 1024
+	// maxInlineSize is the longest inline request, or multibulk or bulk length
+	// line, accepted: Redis's PROTO_INLINE_MAX_SIZE
+	maxInlineSize = 64 * 1024
+	// maxPreallocArgs caps how many argument slots are allocated up front from
+	// a client-supplied multibulk length
+	maxPreallocArgs = 1024
+	// maxPreallocBulk caps how many bytes are allocated up front from a
+	// client-supplied bulk length; larger arguments grow as their data arrives
+	maxPreallocBulk = 32 * 1024
+)
+
+// ProtocolError is returned by Reader when a client sends a malformed request.
+// The connection cannot be resynchronised afterwards, so the server replies
+// with the error and closes it.
+type ProtocolError struct {
+	msg string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.msg
+}
+
+func protocolError(format string, args ...any) error {
+	return &ProtocolError{msg: fmt.Sprintf(format, args...)}
+}
+
+// IsProtocolError reports whether err is a ProtocolError.
+func IsProtocolError(err error) bool {
+	var protoErr *ProtocolError
+	return errors.As(err, &protoErr)
+}
+
+// errLineTooLong is returned by readLine when a line exceeds its limit
+var errLineTooLong = errors.New("line too long")
+
+// Reader parses client requests, either multibulk arrays of bulk strings or
+// inline commands, from a buffered stream. Lengths sent by the client are
+// checked against the reader's limits before anything is allocated for them.
+type Reader struct {
+	r               *bufio.Reader
+	maxBulkLen      int64
+	maxBulkLenFunc  func() int64
+	maxMultibulkLen int64
+	unauthenticated bool
+}
+
+// NewReader creates a reader with the default limits.
+func NewReader(rd io.Reader) *Reader {
+	return &Reader{
+		r:               bufio.NewReaderSize(rd, ReadBufferSize),
+		maxBulkLen:      DefaultMaxBulkLen,
+		maxMultibulkLen: DefaultMaxMultibulkLen,
+	}
+}
+
+// SetMaxBulkLen sets the largest bulk string argument accepted (proto-max-bulk-len).
+func (r *Reader) SetMaxBulkLen(n int64) {
+	r.maxBulkLen = n
+}
+
+// SetMaxBulkLenFunc makes the reader call fn for the largest bulk string
+// argument accepted each time it checks a length, so a limit changed while
+// the reader waits for a command applies to that command. It takes
+// precedence over SetMaxBulkLen.
+func (r *Reader) SetMaxBulkLenFunc(fn func() int64) {
+	r.maxBulkLenFunc = fn
+}
+
+// bulkLimit returns the largest bulk string argument currently accepted
+func (r *Reader) bulkLimit() int64 {
+	if r.maxBulkLenFunc != nil {
+		return r.maxBulkLenFunc()
+	}
+	return r.maxBulkLen
+}
+
+// SetMaxMultibulkLen sets the largest number of arguments accepted in one command.
+func (r *Reader) SetMaxMultibulkLen(n int64) {
+	r.maxMultibulkLen = n
+}
+
+// SetUnauthenticated applies the limits for clients that haven't
+// authenticated yet: requests of more than 10 arguments, or with arguments of
+// more than 16KB, are rejected.
+func (r *Reader) SetUnauthenticated(unauthenticated bool) {
+	r.unauthenticated = unauthenticated
+}
+
+// Buffered returns the number of bytes that have been read from the
+// connection but not parsed yet.
+func (r *Reader) Buffered() int {
+	return r.r.Buffered()
+}
+
+// Pending reports whether the read buffer already holds a complete command,
+// so the next ReadCommand won't wait for the connection. Empty requests, which
+// ReadCommand skips, don't count. A malformed request counts as complete, as
+// ReadCommand fails on it without reading further.
+func (r *Reader) Pending() bool {
+	buf, _ := r.r.Peek(r.r.Buffered())
+	for len(buf) > 0 {
+		n, empty := r.scanRequest(buf)
+		if n == 0 {
+			return false
+		}
+		if !empty {
+			return true
+		}
+		buf = buf[n:]
+	}
+	return false
+}
+
+// scanRequest returns the size of the request at the start of buf and whether
+// it is empty, without parsing its arguments. The size is 0 if buf doesn't
+// hold the whole request yet.
+func (r *Reader) scanRequest(buf []byte) (n int, empty bool) {
+	end := bytes.IndexByte(buf, '\n')
+	if end < 0 {
+		return 0, false
+	}
+	if buf[0] != ArrayPrefix {
+		return end + 1, len(bytes.TrimLeft(buf[:end], " \t\n\v\f\r")) == 0
+	}
+
+	count, ok := parseLength(bytes.TrimSuffix(buf[1:end], []byte{'\r'}))
+	if !ok || count > r.maxMultibulkLen || r.unauthenticated && count > maxUnauthenticatedMultibulkLen {
+		return end + 1, false
+	}
+	if count <= 0 {
+		return end + 1, true
+	}
+
+	pos := end + 1
+	for range count {
+		end = bytes.IndexByte(buf[pos:], '\n')
+		if end < 0 {
+			return 0, false
+		}
+		line := bytes.TrimSuffix(buf[pos:pos+end], []byte{'\r'})
+		if len(line) == 0 || line[0] != BulkStringPrefix {
+			return pos + end + 1, false
+		}
+		length, ok := parseLength(line[1:])
+		if !ok || length < 0 || length > r.bulkLimit() || r.unauthenticated && length > maxUnauthenticatedBulkLen {
+			return pos + end + 1, false
+		}
+		if length+2 > int64(len(buf)-pos-end-1) {
+			return 0, false
+		}
+		pos += end + 1 + int(length) + 2
+	}
+	return pos, false
+}
+
+// ReadCommand reads the next command and returns its arguments, the first of
+// which is the command name. Empty requests are skipped, as in Redis.
+// Malformed requests return a ProtocolError; other errors come from the
+// underlying reader, io.EOF included.
+func (r *Reader) ReadCommand() ([][]byte, error) {
+	for {
+		b, err := r.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		var args [][]byte
+		if b[0] == ArrayPrefix {
+			args, err = r.readMultibulk()
+		} else {
+			args, err = r.readInline()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(args) > 0 {
+			return args, nil
+		}
+	}
+}
+
+// readMultibulk reads a request sent as an array of bulk strings
+func (r *Reader) readMultibulk() ([][]byte, error) {
+	line, err := r.readLine(maxInlineSize)
+	if err == errLineTooLong {
+		return nil, protocolError("too big mbulk count string")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	count, ok := parseLength(line[1:])
+	if !ok || count > r.maxMultibulkLen {
+		return nil, protocolError("invalid multibulk length")
+	}
+	if r.unauthenticated && count > maxUnauthenticatedMultibulkLen {
+		return nil, protocolError("unauthenticated multibulk length")
+	}
+	if count <= 0 {
+		return nil, nil
+	}
+
+	args := make([][]byte, 0, min(count, maxPreallocArgs))
+	for range count {
+		arg, err := r.readBulk()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// readBulk reads one bulk string argument of a multibulk request
+func (r *Reader) readBulk() ([]byte, error) {
+	line, err := r.readLine(maxInlineSize)
+	if err == errLineTooLong {
+		return nil, protocolError("too big bulk count string")
+	}
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if len(line) == 0 || line[0] != BulkStringPrefix {
+		// Redis reports the byte found instead, with line breaks shown as spaces
+		got := byte(' ')
+		if len(line) > 0 {
+			got = line[0]
+		}
+		return nil, protocolError("expected '$', got '%c'", got)
+	}
+
+	length, ok := parseLength(line[1:])
+	if !ok || length < 0 || length > r.bulkLimit() {
+		return nil, protocolError("invalid bulk length")
+	}
+	if r.unauthenticated && length > maxUnauthenticatedBulkLen {
+		return nil, protocolError("unauthenticated bulk length")
+	}
+
+	// Grow the argument as its data arrives, so a large length costs nothing
+	// until the client actually sends the bytes
+	arg := make([]byte, 0, min(length, maxPreallocBulk))
+	for int64(len(arg)) < length {
+		if len(arg) == cap(arg) {
+			arg = slices.Grow(arg, int(min(length-int64(len(arg)), int64(len(arg)))))
+		}
+		n, err := r.r.Read(arg[len(arg):min(int64(cap(arg)), length)])
+		arg = arg[:len(arg)+n]
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+
+	var crlf [2]byte
+	if _, err := io.ReadFull(r.r, crlf[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if crlf != [2]byte{'\r', '\n'} {
+		return nil, protocolError("expected CRLF after bulk string")
+	}
+	return arg, nil
+}
+
+// readInline reads a request sent as a single line of arguments, which may be
+// quoted as described for SplitArgs
+func (r *Reader) readInline() ([][]byte, error) {
+	line, err := r.readLine(maxInlineSize)
+	if err == errLineTooLong {
+		return nil, protocolError("too big inline request")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	args, ok := SplitArgs(line)
+	if !ok {
+		return nil, protocolError("unbalanced quotes in request")
+	}
+	return args, nil
+}
+
+// readLine reads a line and returns it without its line terminator. Lines
+// longer than limit bytes return errLineTooLong. The returned slice is only
+// valid until the next read.
+func (r *Reader) readLine(limit int) ([]byte, error) {
+	line, err := r.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		// The line doesn't fit in the read buffer, so copy it out piece by piece
+		line = bytes.Clone(line)
+		for err == bufio.ErrBufferFull {
+			if len(line) > limit {
+				return nil, errLineTooLong
+			}
+			var more []byte
+			more, err = r.r.ReadSlice('\n')
+			line = append(line, more...)
+		}
+	}
+	if len(line) > limit {
+		return nil, errLineTooLong
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+// unexpectedEOF converts io.EOF in the middle of a request to io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// parseLength parses a length with the strictness of Redis's string2ll: an
+// optional minus sign followed by digits, without leading zeros or a plus sign.
+func parseLength(b []byte) (int64, bool) {
+	if len(b) == 0 || len(b) > 20 {
+		return 0, false
+	}
+
+	negative := b[0] == '-'
+	if negative {
+		b = b[1:]
+		if len(b) == 0 {
+			return 0, false
+		}
+	}
+	if len(b) > 1 && b[0] == '0' {
+		return 0, false
+	}
+
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n > (math.MaxInt64-int64(c-'0'))/10 {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	if negative {
+		n = -n
+	}
+	return n, true
+}
@@ -0,0 +1,253 @@
+package resp
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readAll parses every command in input and returns them as string slices
+func readAll(r *Reader) ([][]string, error) {
+	var commands [][]string
+	for {
+		args, err := r.ReadCommand()
+		if err == io.EOF {
+			return commands, nil
+		}
+		if err != nil {
+			return commands, err
+		}
+		command := make([]string, len(args))
+		for i, arg := range args {
+			command[i] = string(arg)
+		}
+		commands = append(commands, command)
+	}
+}
+
+func TestReader_ReadCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+		errMsg   string
+	}{
+		{
+			name:     "multibulk",
+			input:    "*2\r\n$4\r\nECHO\r\n$5\r\nhello\r\n",
+			expected: [][]string{{"ECHO", "hello"}},
+		},
+		{
+			name:     "binary safe bulk",
+			input:    "*2\r\n$4\r\nECHO\r\n$4\r\na\r\nb\r\n",
+			expected: [][]string{{"ECHO", "a\r\nb"}},
+		},
+		{
+			name:     "empty bulk",
+			input:    "*2\r\n$4\r\nECHO\r\n$0\r\n\r\n",
+			expected: [][]string{{"ECHO", ""}},
+		},
+		{
+			name:     "pipelined",
+			input:    "*1\r\n$4\r\nPING\r\nPING\r\n*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n",
+			expected: [][]string{{"PING"}, {"PING"}, {"SET", "k", "v"}},
+		},
+		{
+			name:     "inline",
+			input:    "SET  key value\r\nGET key\n",
+			expected: [][]string{{"SET", "key", "value"}, {"GET", "key"}},
+		},
+		{
+			name:     "inline with quotes",
+			input:    "SET k \"hello world\"\r\nSET k 'it''s'\r\n",
+			expected: [][]string{{"SET", "k", "hello world"}},
+			errMsg:   "Protocol error: unbalanced quotes in request",
+		},
+		{
+			name:     "empty requests are skipped",
+			input:    "*0\r\n*-1\r\n\r\n   \r\n*1\r\n$4\r\nPING\r\n",
+			expected: [][]string{{"PING"}},
+		},
+		{
+			name:   "invalid multibulk length",
+			input:  "*abc\r\n",
+			errMsg: "Protocol error: invalid multibulk length",
+		},
+		{
+			name:   "multibulk length over INT_MAX",
+			input:  "*2147483648\r\n",
+			errMsg: "Protocol error: invalid multibulk length",
+		},
+		{
+			name:   "multibulk length with leading zero",
+			input:  "*01\r\n$4\r\nPING\r\n",
+			errMsg: "Protocol error: invalid multibulk length",
+		},
+		{
+			name:   "missing bulk prefix",
+			input:  "*1\r\n+PING\r\n",
+			errMsg: "Protocol error: expected '$', got '+'",
+		},
+		{
+			name:   "invalid bulk length",
+			input:  "*1\r\n$x\r\n",
+			errMsg: "Protocol error: invalid bulk length",
+		},
+		{
+			name:   "negative bulk length",
+			input:  "*1\r\n$-1\r\n",
+			errMsg: "Protocol error: invalid bulk length",
+		},
+		{
+			name:   "bulk length over proto-max-bulk-len",
+			input:  "*1\r\n$536870913\r\n",
+			errMsg: "Protocol error: invalid bulk length",
+		},
+		{
+			name:   "bulk without CRLF",
+			input:  "*1\r\n$4\r\nPINGXX",
+			errMsg: "Protocol error: expected CRLF after bulk string",
+		},
+		{
+			name:   "too big mbulk count string",
+			input:  "*" + strings.Repeat("1", 70*1024),
+			errMsg: "Protocol error: too big mbulk count string",
+		},
+		{
+			name:   "too big bulk count string",
+			input:  "*1\r\n$" + strings.Repeat("1", 70*1024),
+			errMsg: "Protocol error: too big bulk count string",
+		},
+		{
+			name:   "too big inline request",
+			input:  "SET k " + strings.Repeat("v", 70*1024),
+			errMsg: "Protocol error: too big inline request",
+		},
+		{
+			name:   "truncated request",
+			input:  "*2\r\n$4\r\nECHO\r\n",
+			errMsg: "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands, err := readAll(NewReader(strings.NewReader(tt.input)))
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("Expected error %q, got %v", tt.errMsg, err)
+				}
+				if !slices.EqualFunc(commands, tt.expected, slices.Equal) {
+					t.Errorf("Expected %q before the error, got %q", tt.expected, commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.EqualFunc(commands, tt.expected, slices.Equal) {
+				t.Errorf("Expected %q, got %q", tt.expected, commands)
+			}
+		})
+	}
+}
+
+func TestReader_Limits(t *testing.T) {
+	r := NewReader(strings.NewReader("*2\r\n$3\r\nGET\r\n$5\r\nhello\r\n"))
+	r.SetMaxBulkLen(4)
+	if _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: invalid bulk length" {
+		t.Errorf("Expected invalid bulk length, got %v", err)
+	}
+
+	r = NewReader(strings.NewReader("*2\r\n$3\r\nGET\r\n$5\r\nhello\r\n"))
+	r.SetMaxBulkLenFunc(func() int64 { return 4 })
+	if _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: invalid bulk length" {
+		t.Errorf("Expected invalid bulk length from the limit function, got %v", err)
+	}
+
+	r = NewReader(strings.NewReader("*3\r\n"))
+	r.SetMaxMultibulkLen(2)
+	if _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: invalid multibulk length" {
+		t.Errorf("Expected invalid multibulk length, got %v", err)
+	}
+
+	r = NewReader(strings.NewReader("*11\r\n"))
+	r.SetUnauthenticated(true)
+	if _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: unauthenticated multibulk length" {
+		t.Errorf("Expected unauthenticated multibulk length, got %v", err)
+	}
+
+	r = NewReader(strings.NewReader("*2\r\n$4\r\nAUTH\r\n$16385\r\n"))
+	r.SetUnauthenticated(true)
+	if _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: unauthenticated bulk length" {
+		t.Errorf("Expected unauthenticated bulk length, got %v", err)
+	}
+}
+
+func TestReader_LargeLengthsAllocateLazily(t *testing.T) {
+	// A client announcing huge lengths without sending the data must not make
+	// the reader allocate for them
+	allocs := testing.AllocsPerRun(10, func() {
+		r := NewReader(strings.NewReader("*2147483647\r\n$536870912\r\nabc"))
+		r.ReadCommand()
+	})
+	if allocs > 20 {
+		t.Errorf("Expected a handful of allocations, got %v", allocs)
+	}
+
+	value := strings.Repeat("x", 100*1024)
+	r := NewReader(strings.NewReader("*1\r\n$102400\r\n" + value + "\r\n"))
+	args, err := r.ReadCommand()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(args) != 1 || string(args[0]) != value {
+		t.Errorf("Large bulk string was not read back intact")
+	}
+}
+
+func TestReader_Buffered(t *testing.T) {
+	r := NewReader(strings.NewReader("PING\r\nPING\r\n"))
+	if _, err := r.ReadCommand(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.Buffered() != len("PING\r\n") {
+		t.Errorf("Expected the second command to be buffered, got %d bytes", r.Buffered())
+	}
+}
+
+func TestReader_Pending(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffered string
+		expected bool
+	}{
+		{"nothing buffered", "", false},
+		{"complete multibulk", "*1\r\n$4\r\nPING\r\n", true},
+		{"complete inline", "PING\r\n", true},
+		{"partial header", "*2\r", false},
+		{"partial bulk header", "*1\r\n$4", false},
+		{"partial bulk data", "*1\r\n$4\r\nPI", false},
+		{"missing bulk CRLF", "*1\r\n$4\r\nPING", false},
+		{"partial inline", "PI", false},
+		{"empty requests only", "*0\r\n\r\n", false},
+		{"empty request before command", "*0\r\n\r\nPING\r\n", true},
+		{"empty request before partial command", "\r\n*1\r\n", false},
+		{"malformed length", "*x\r\n", true},
+		{"malformed bulk", "*1\r\n:1\r\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read one command first so the rest of the input sits in the buffer
+			r := NewReader(strings.NewReader("PING\r\n" + tt.buffered))
+			if _, err := r.ReadCommand(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := r.Pending(); got != tt.expected {
+				t.Errorf("Expected Pending() = %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
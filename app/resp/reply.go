@@ -0,0 +1,145 @@
+package resp
+
+// Kind identifies the type of a Reply
+type Kind int
+
+const (
+	// KindSimpleString is a status reply such as OK
+	KindSimpleString Kind = iota
+	// KindError is an error reply, whose text starts with an error code such as ERR
+	KindError
+	// KindInteger is a signed 64-bit integer
+	KindInteger
+	// KindBulkString is a binary-safe string
+	KindBulkString
+	// KindNull is a missing value, encoded as a null bulk string in RESP2
+	KindNull
+	// KindNullArray is a missing aggregate, encoded as a null array in RESP2
+	KindNullArray
+	// KindArray is an ordered list of replies
+	KindArray
+	// KindMap is a list of key-value pairs, encoded as a flat array in RESP2
+	KindMap
+	// KindSet is an unordered collection of unique replies, encoded as an array in RESP2
+	KindSet
+	// KindDouble is a floating point number, encoded as a bulk string in RESP2
+	KindDouble
+	// KindBoolean is true or false, encoded as the integer 1 or 0 in RESP2
+	KindBoolean
+	// KindBigNumber is an arbitrary precision integer, encoded as a bulk string in RESP2
+	KindBigNumber
+	// KindVerbatim is a string with a format hint, encoded as a bulk string in RESP2
+	KindVerbatim
+	// KindPush is an out-of-band message, encoded as an array in RESP2
+	KindPush
+)
+
+// Reply is a protocol-independent command reply, which an Encoder writes as
+// RESP2 or RESP3 depending on the protocol negotiated by the client.
+type Reply struct {
+	Kind Kind
+	// Str holds simple strings, errors, bulk strings, big numbers and verbatim
+	// text, and the text of doubles with a fixed format
+	Str string
+	// Int holds integers, and booleans as 0 or 1
+	Int int64
+	// Float holds doubles
+	Float float64
+	// Format is the three-character format of a verbatim string, such as txt
+	Format string
+	// Elems holds the elements of arrays, sets and pushes, and the alternating
+	// keys and values of maps
+	Elems []Reply
+}
+
+// SimpleString creates a status reply. str must not contain CR or LF.
+func SimpleString(str string) Reply {
+	return Reply{Kind: KindSimpleString, Str: str}
+}
+
+// Error creates an error reply. msg starts with the error code, as in
+// "ERR syntax error", and must not contain CR or LF.
+func Error(msg string) Reply {
+	return Reply{Kind: KindError, Str: msg}
+}
+
+// Integer creates an integer reply.
+func Integer(n int64) Reply {
+	return Reply{Kind: KindInteger, Int: n}
+}
+
+// BulkString creates a bulk string reply.
+func BulkString(str string) Reply {
+	return Reply{Kind: KindBulkString, Str: str}
+}
+
+// Null creates a null reply, such as GET's reply for a missing key.
+func Null() Reply {
+	return Reply{Kind: KindNull}
+}
+
+// NullArray creates a null reply standing for a missing aggregate, which
+// RESP2 encodes as a null array.
+func NullArray() Reply {
+	return Reply{Kind: KindNullArray}
+}
+
+// Array creates an array reply.
+func Array(elems []Reply) Reply {
+	return Reply{Kind: KindArray, Elems: elems}
+}
+
+// BulkStrings creates an array reply of bulk strings.
+func BulkStrings(strs []string) Reply {
+	elems := make([]Reply, len(strs))
+	for i, str := range strs {
+		elems[i] = BulkString(str)
+	}
+	return Array(elems)
+}
+
+// Map creates a map reply from alternating keys and values.
+func Map(pairs []Reply) Reply {
+	return Reply{Kind: KindMap, Elems: pairs}
+}
+
+// Set creates a set reply.
+func Set(elems []Reply) Reply {
+	return Reply{Kind: KindSet, Elems: elems}
+}
+
+// Double creates a floating point reply.
+func Double(f float64) Reply {
+	return Reply{Kind: KindDouble, Float: f}
+}
+
+// FormattedDouble creates a floating point reply written as text in both
+// protocols, for replies whose precision Redis fixes, such as coordinates.
+func FormattedDouble(f float64, text string) Reply {
+	return Reply{Kind: KindDouble, Float: f, Str: text}
+}
+
+// Boolean creates a boolean reply.
+func Boolean(b bool) Reply {
+	r := Reply{Kind: KindBoolean}
+	if b {
+		r.Int = 1
+	}
+	return r
+}
+
+// BigNumber creates a big number reply from its decimal representation.
+func BigNumber(digits string) Reply {
+	return Reply{Kind: KindBigNumber, Str: digits}
+}
+
+// Verbatim creates a verbatim string reply. format is three characters long,
+// such as txt for plain text or mkd for markdown.
+func Verbatim(format, text string) Reply {
+	return Reply{Kind: KindVerbatim, Format: format, Str: text}
+}
+
+// Push creates an out-of-band push reply.
+func Push(elems []Reply) Reply {
+	return Reply{Kind: KindPush, Elems: elems}
+}
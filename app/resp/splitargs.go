@@ -0,0 +1,128 @@
+package resp
+
+// SplitArgs splits a line into arguments the way Redis's sdssplitargs does,
+// as used for inline commands and configuration files. Arguments are separated
+// by whitespace and may be quoted:
+//
+//   - "double quotes" support the escapes \n \r \t \b \a, \xHH for any byte,
+//     and a backslash before any other character for the character itself
+//   - 'single quotes' are taken literally, except for \' for a quote
+//
+// A closing quote must be followed by whitespace or the end of the line. ok is
+// false if it isn't, or if a quote is never closed. An empty or blank line
+// returns no arguments.
+func SplitArgs(line []byte) (args [][]byte, ok bool) {
+	// at returns the byte at i, or 0 past the end, like reading a C string
+	at := func(i int) byte {
+		if i < len(line) {
+			return line[i]
+		}
+		return 0
+	}
+
+	p := 0
+	for {
+		for p < len(line) && isSpace(line[p]) {
+			p++
+		}
+		if p == len(line) {
+			if args == nil {
+				args = [][]byte{}
+			}
+			return args, true
+		}
+
+		inDouble, inSingle := false, false
+		current := []byte{}
+		for done := false; !done; {
+			c := at(p)
+			switch {
+			case inDouble:
+				switch {
+				case c == '\\' && at(p+1) == 'x' && isHexDigit(at(p+2)) && isHexDigit(at(p+3)):
+					current = append(current, hexValue(at(p+2))<<4|hexValue(at(p+3)))
+					p += 3
+				case c == '\\' && p+1 < len(line):
+					p++
+					switch line[p] {
+					case 'n':
+						current = append(current, '\n')
+					case 'r':
+						current = append(current, '\r')
+					case 't':
+						current = append(current, '\t')
+					case 'b':
+						current = append(current, '\b')
+					case 'a':
+						current = append(current, '\a')
+					default:
+						current = append(current, line[p])
+					}
+				case c == '"':
+					// The closing quote must be followed by a space or nothing at all
+					if p+1 < len(line) && !isSpace(line[p+1]) {
+						return nil, false
+					}
+					done = true
+				case p == len(line):
+					// Unterminated quotes
+					return nil, false
+				default:
+					current = append(current, c)
+				}
+			case inSingle:
+				switch {
+				case c == '\\' && at(p+1) == '\'':
+					p++
+					current = append(current, '\'')
+				case c == '\'':
+					if p+1 < len(line) && !isSpace(line[p+1]) {
+						return nil, false
+					}
+					done = true
+				case p == len(line):
+					return nil, false
+				default:
+					current = append(current, c)
+				}
+			default:
+				switch {
+				case p == len(line) || c == ' ' || c == '\n' || c == '\r' || c == '\t':
+					done = true
+				case c == '"':
+					inDouble = true
+				case c == '\'':
+					inSingle = true
+				default:
+					current = append(current, c)
+				}
+			}
+			if p < len(line) {
+				p++
+			}
+		}
+		args = append(args, current)
+	}
+}
+
+// isSpace reports whether c is whitespace, as C's isspace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r'
+}
+
+// isHexDigit reports whether c is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// hexValue returns the value of the hexadecimal digit c
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,50 @@
+package resp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+		ok       bool
+	}{
+		{"empty", "", []string{}, true},
+		{"blank", " \t\v\f ", []string{}, true},
+		{"plain", "SET key value", []string{"SET", "key", "value"}, true},
+		{"repeated whitespace", "  SET\t key   value  ", []string{"SET", "key", "value"}, true},
+		{"double quotes", `SET k "hello world"`, []string{"SET", "k", "hello world"}, true},
+		{"empty double quotes", `SET k ""`, []string{"SET", "k", ""}, true},
+		{"escapes", `"a\nb\r\t\b\a\"\\\q"`, []string{"a\nb\r\t\b\a\"\\q"}, true},
+		{"hex escapes", `"\x41\x6a\xFF\x00"`, []string{"Aj\xff\x00"}, true},
+		{"invalid hex escape", `"\x4g"`, []string{"x4g"}, true},
+		{"single quotes", `'hello "world"'`, []string{`hello "world"`}, true},
+		{"escaped single quote", `'it\'s' '\n'`, []string{"it's", `\n`}, true},
+		{"quote inside argument", `foo"bar baz"`, []string{"foobar baz"}, true},
+		{"unterminated double quote", `SET k "hello`, nil, false},
+		{"unterminated single quote", `SET k 'hello`, nil, false},
+		{"trailing backslash in quotes", `"hello\`, nil, false},
+		{"closing double quote followed by text", `"hello"world`, nil, false},
+		{"closing single quote followed by text", `'hello'world`, nil, false},
+		{"closing quote followed by tab", "\"hello\"\tworld", []string{"hello", "world"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, ok := SplitArgs([]byte(tt.line))
+			if ok != tt.ok {
+				t.Fatalf("Expected ok = %v, got %v", tt.ok, ok)
+			}
+			got := make([]string, len(args))
+			for i, arg := range args {
+				got[i] = string(arg)
+			}
+			if tt.ok && !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
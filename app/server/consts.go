@@ -1,6 +1,40 @@
 package server
 
+import "time"
+
 const (
 	PORT                = 6379
 	REQUEST_BUFFER_SIZE = 1024
 )
+
+const (
+	// DefaultMaxClients is the default maxclients, the most connections
+	// accepted at once
+	DefaultMaxClients = 10000
+	// DefaultTCPKeepAlive is the default tcp-keepalive, in seconds
+	DefaultTCPKeepAlive = 300
+	// DefaultTimeout is the default timeout after which idle clients are
+	// closed, in seconds; 0 never closes them
+	DefaultTimeout = 0
+	// DefaultBind is the default bind, the addresses listened on: every IPv4
+	// address, and every IPv6 address if IPv6 is available
+	DefaultBind = "* -::*"
+	// DefaultProtectedMode is the default protected-mode
+	DefaultProtectedMode = "yes"
+	// DefaultTLSPort is the default tls-port; 0 doesn't listen for TLS
+	// connections
+	DefaultTLSPort = 0
+	// DefaultTLSAuthClients is the default tls-auth-clients, which requires
+	// clients to present a certificate signed by a CA of tls-ca-cert-file
+	DefaultTLSAuthClients = "yes"
+	// DefaultUnixSocketPerm is the default unixsocketperm; 0 leaves the
+	// socket file with the permissions the umask gives it
+	DefaultUnixSocketPerm = 0
+
+	// tlsHandshakeTimeout is how long a TLS client has to complete its
+	// handshake
+	tlsHandshakeTimeout = 10 * time.Second
+
+	// clientsCronInterval is how often idle clients are looked for
+	clientsCronInterval = 100 * time.Millisecond
+)
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/resp"
+)
+
+// init declares the parameters of the server and its connections
+func init() {
+	protoMaxBulkLen.Store(resp.DefaultMaxBulkLen)
+	dir, _ := os.Getwd()
+	for _, p := range []config.Parameter{
+		// Unlike Redis, the server doesn't rebind when port is set, so it can
+		// only be set at startup
+		{Name: "port", Type: config.TypeInt, Default: strconv.Itoa(PORT), Min: 0, Max: 65535, Immutable: true},
+		{Name: "bind", Type: config.TypeStringList, Default: DefaultBind, Immutable: true},
+		{Name: "protected-mode", Type: config.TypeBool, Default: DefaultProtectedMode},
+		{Name: "dir", Type: config.TypeString, Default: dir, Apply: applyDir},
+		{Name: "maxclients", Type: config.TypeInt, Default: strconv.Itoa(DefaultMaxClients), Min: 1, Max: math.MaxInt32},
+		{Name: "timeout", Type: config.TypeInt, Default: strconv.Itoa(DefaultTimeout), Min: 0, Max: math.MaxInt32},
+		{Name: "tcp-keepalive", Type: config.TypeInt, Default: strconv.Itoa(DefaultTCPKeepAlive), Min: 0, Max: math.MaxInt32},
+		{Name: "proto-max-bulk-len", Type: config.TypeMemory, Default: strconv.Itoa(resp.DefaultMaxBulkLen), Min: 1024 * 1024, Max: math.MaxInt64, Apply: applyProtoMaxBulkLen},
+		{Name: "tls-port", Type: config.TypeInt, Default: strconv.Itoa(DefaultTLSPort), Min: 0, Max: 65535, Immutable: true},
+		{Name: "tls-cert-file", Type: config.TypeString, Apply: applyTLS, ApplyGroup: "tls"},
+		{Name: "tls-key-file", Type: config.TypeString, Apply: applyTLS, ApplyGroup: "tls"},
+		{Name: "tls-ca-cert-file", Type: config.TypeString, Apply: applyTLS, ApplyGroup: "tls"},
+		{Name: "tls-auth-clients", Type: config.TypeEnum, Default: DefaultTLSAuthClients, Values: []string{"no", "yes", "optional"}, Apply: applyTLS, ApplyGroup: "tls"},
+		{Name: "unixsocket", Type: config.TypeString, Immutable: true},
+		{Name: "unixsocketperm", Type: config.TypeString, Default: strconv.Itoa(DefaultUnixSocketPerm), Immutable: true, Validate: validateOctal},
+	} {
+		config.Register(p)
+	}
+}
+
+// protoMaxBulkLen is the proto-max-bulk-len setting, which connections read
+// as they parse each bulk string
+var protoMaxBulkLen atomic.Int64
+
+// applyProtoMaxBulkLen makes connections use the proto-max-bulk-len setting,
+// including the ones already open
+func applyProtoMaxBulkLen() error {
+	protoMaxBulkLen.Store(int64(config.GetInt("proto-max-bulk-len", resp.DefaultMaxBulkLen)))
+	return nil
+}
+
+// applyDir changes the working directory to the dir setting
+func applyDir() error {
+	dir, _ := config.Get("dir")
+	return os.Chdir(dir)
+}
+
+// applyTLS reloads the TLS configuration once a certificate is configured,
+// so that setting a TLS parameter fails if it can't be loaded
+func applyTLS() error {
+	settings := currentTLSSettings()
+	if settings.certFile == "" && settings.keyFile == "" {
+		return nil
+	}
+	return loadTLSConfig()
+}
+
+// validateOctal checks that value is a number in octal, such as file
+// permissions
+func validateOctal(value string) error {
+	if _, err := strconv.ParseUint(value, 8, 32); err != nil {
+		return config.ErrInt
+	}
+	return nil
+}
@@ -1,10 +1,10 @@
 package server
 
 import (
-	"bufio"
 	"context"
+	"crypto/tls"
+	stderrors "errors"
 	"fmt"
-	"io"
 	"log"
 	"net"
 	"os"
@@ -15,48 +15,264 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dotslash21/redis-clone/app/acl"
 	"github.com/dotslash21/redis-clone/app/command"
+	"github.com/dotslash21/redis-clone/app/config"
 	"github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/resp"
 	"github.com/dotslash21/redis-clone/app/store"
 )
 
 // Server represents a Redis server
 type Server struct {
-	listener  net.Listener
+	listeners []net.Listener
 	registry  *command.Registry
-	store     *store.Store
-	conns     sync.Map
+	dbs       *store.Databases
+	clients   *command.Clients
 	shutdown  chan struct{}
 	waitGroup sync.WaitGroup
 }
 
-// NewServer creates a new Redis server
+// protectedModeMessage is the error non-loopback clients get in protected mode
+const protectedModeMessage = "-DENIED Redis is running in protected mode because protected mode is enabled and no password is set for the default user. " +
+	"In this mode connections are only accepted from the loopback interface. " +
+	"If you want to connect from external computers to Redis you may adopt one of the following solutions: " +
+	"1) Just disable protected mode sending the command 'CONFIG SET protected-mode no' from the loopback interface by connecting to Redis from the same host the server is running, however MAKE SURE Redis is not publicly accessible from internet if you do so. Use CONFIG REWRITE to make this change permanent. " +
+	"2) Alternatively you can just disable the protected mode by editing the Redis configuration file, and setting the protected mode option to 'no', and then restarting the server. " +
+	"3) If you started the server manually just for testing, restart it with the '--protected-mode no' option. " +
+	"4) Set up an authentication password for the default user. " +
+	"NOTE: You only need to do one of the above things in order for the server to start accepting connections from the outside.\r\n"
+
+// NewServer creates a new Redis server listening on port, and on the
+// tls-port setting for TLS connections if it is set, on each of the addresses
+// of the bind setting, and on the Unix socket of the unixsocket setting if it
+// is set. A port of 0 doesn't listen for plain connections.
 func NewServer(port int) (*Server, error) {
-	listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeServer, fmt.Sprintf("failed to bind to port %d", port))
+	bind, ok := config.Get("bind")
+	if !ok {
+		bind = DefaultBind
+	}
+	addrs := strings.Fields(bind)
+
+	var listeners []net.Listener
+	if port != 0 {
+		plain, err := listen(addrs, port)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrorTypeServer, fmt.Sprintf("failed to bind to port %d", port))
+		}
+		listeners = plain
+	}
+	if tlsPort := config.GetInt("tls-port", DefaultTLSPort); tlsPort != 0 {
+		secure, err := listenTLS(addrs, tlsPort)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, errors.Wrap(err, errors.ErrorTypeServer, fmt.Sprintf("failed to listen for TLS connections on port %d", tlsPort))
+		}
+		listeners = append(listeners, secure...)
+	}
+	if path, _ := config.Get("unixsocket"); path != "" {
+		listener, err := listenUnix(path)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, errors.Wrap(err, errors.ErrorTypeServer, fmt.Sprintf("failed to listen on Unix socket %s", path))
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New(errors.ErrorTypeServer, "none of port, tls-port and unixsocket is set")
 	}
 
 	s := &Server{
-		listener: listener,
-		registry: command.NewRegistry(),
-		store:    store.GetStore(),
-		shutdown: make(chan struct{}),
+		listeners: listeners,
+		registry:  command.NewRegistry(),
+		dbs:       store.GetDatabases(),
+		clients:   command.NewClients(),
+		shutdown:  make(chan struct{}),
 	}
 
 	// Register commands
-	s.registerCommands()
+	if err := s.registerCommands(); err != nil {
+		closeListeners(s.listeners)
+		return nil, errors.Wrap(err, errors.ErrorTypeServer, "failed to register commands")
+	}
+	if err := s.registry.CheckRenames(); err != nil {
+		closeListeners(s.listeners)
+		return nil, errors.Wrap(err, errors.ErrorTypeServer, "invalid rename-command")
+	}
+
+	if path, _ := config.Get("aclfile"); path != "" {
+		if err := acl.GetACL().Load(path, s.registry.CommandExists); err != nil {
+			closeListeners(s.listeners)
+			return nil, errors.Wrap(err, errors.ErrorTypeServer, fmt.Sprintf("failed to load ACL file %s", path))
+		}
+	}
 
 	return s, nil
 }
 
-// registerCommands registers all supported Redis commands
-func (s *Server) registerCommands() {
-	s.registry.Register(command.NewPingCommand())
-	s.registry.Register(command.NewEchoCommand())
-	s.registry.Register(command.NewSetCommand(s.store))
-	s.registry.Register(command.NewGetCommand(s.store))
-	s.registry.Register(command.NewConfigCommand())
+// listen listens on port on each of addrs. As in Redis, "*" stands for every
+// IPv4 address and "::*" for every IPv6 address, and an address prefixed with
+// "-" is skipped if it isn't available on this host. Addresses of a protocol
+// the host doesn't support are skipped too, as long as one listener is
+// created.
+func listen(addrs []string, port int) ([]net.Listener, error) {
+	var listeners []net.Listener
+
+	for _, addr := range addrs {
+		optional := strings.HasPrefix(addr, "-")
+		host := strings.TrimPrefix(addr, "-")
+
+		network := "tcp"
+		switch host {
+		case "*":
+			network, host = "tcp4", "0.0.0.0"
+		case "::*":
+			network, host = "tcp6", "::"
+		default:
+			if ip := net.ParseIP(host); ip != nil && ip.To4() != nil {
+				network = "tcp4"
+			} else if ip != nil {
+				network = "tcp6"
+			}
+		}
+
+		listener, err := net.Listen(network, net.JoinHostPort(host, fmt.Sprint(port)))
+		if err != nil {
+			if (optional && stderrors.Is(err, syscall.EADDRNOTAVAIL)) || stderrors.Is(err, syscall.EAFNOSUPPORT) {
+				log.Printf("Skipping bind address %s: %v", addr, err)
+				continue
+			}
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("no bind address available among %q", strings.Join(addrs, " "))
+	}
+	return listeners, nil
+}
+
+// listenTLS listens for TLS connections on port on each of addrs, with the
+// certificates of the TLS settings
+func listenTLS(addrs []string, port int) ([]net.Listener, error) {
+	if err := loadTLSConfig(); err != nil {
+		return nil, err
+	}
+	listeners, err := listen(addrs, port)
+	if err != nil {
+		return nil, err
+	}
+	for i, listener := range listeners {
+		listeners[i] = tls.NewListener(listener, tlsServerConfig())
+	}
+	return listeners, nil
+}
+
+// listenUnix listens on the Unix socket at path, replacing any stale socket
+// file left there, with the permissions of the unixsocketperm setting. The
+// socket file is removed when the listener is closed.
+func listenUnix(path string) (net.Listener, error) {
+	perm := uint64(DefaultUnixSocketPerm)
+	if value, ok := config.Get("unixsocketperm"); ok {
+		var err error
+		if perm, err = strconv.ParseUint(value, 8, 32); err != nil {
+			return nil, fmt.Errorf("invalid unixsocketperm %q", value)
+		}
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if perm != 0 {
+		if err := os.Chmod(path, os.FileMode(perm)); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+	return listener, nil
+}
+
+// closeListeners stops listening on every listener
+func closeListeners(listeners []net.Listener) error {
+	var errs []error
+	for _, listener := range listeners {
+		errs = append(errs, listener.Close())
+	}
+	return stderrors.Join(errs...)
+}
+
+// registerCommands registers all supported Redis commands, failing if a
+// rename-command makes two of them share a name
+func (s *Server) registerCommands() error {
+	for _, cmd := range []command.Command{
+		command.NewPingCommand(),
+		command.NewEchoCommand(),
+		command.NewHelloCommand(),
+		command.NewAuthCommand(),
+		command.NewQuitCommand(),
+		command.NewClientCommand(s.clients),
+		command.NewACLCommand(s.registry, s.clients),
+		command.NewCommandCommand(s.registry),
+		command.NewSetCommand(),
+		command.NewGetCommand(),
+		command.NewConfigCommand(),
+		command.NewInfoCommand(s.dbs),
+		command.NewSelectCommand(s.dbs),
+		command.NewSwapDBCommand(s.dbs),
+		command.NewDelCommand(),
+		command.NewUnlinkCommand(),
+		command.NewExistsCommand(),
+		command.NewTypeCommand(),
+		command.NewRenameCommand(),
+		command.NewRenameNxCommand(),
+		command.NewCopyCommand(s.dbs),
+		command.NewMoveCommand(s.dbs),
+		command.NewTouchCommand(),
+		command.NewRandomKeyCommand(),
+		command.NewDBSizeCommand(),
+		command.NewScanCommand(),
+		command.NewKeysCommand(),
+		command.NewFlushDBCommand(),
+		command.NewFlushAllCommand(s.dbs),
+		command.NewSetBitCommand(),
+		command.NewGetBitCommand(),
+		command.NewBitCountCommand(),
+		command.NewBitPosCommand(),
+		command.NewBitOpCommand(),
+		command.NewBitFieldCommand(),
+		command.NewBitFieldRoCommand(),
+		command.NewPfAddCommand(),
+		command.NewPfCountCommand(),
+		command.NewPfMergeCommand(),
+		command.NewGeoAddCommand(),
+		command.NewGeoDistCommand(),
+		command.NewGeoPosCommand(),
+		command.NewGeoHashCommand(),
+		command.NewGeoSearchCommand(),
+		command.NewGeoSearchStoreCommand(),
+		command.NewGeoRadiusCommand(),
+		command.NewGeoRadiusRoCommand(),
+		command.NewGeoRadiusByMemberCommand(),
+		command.NewGeoRadiusByMemberRoCommand(),
+	} {
+		if err := s.registry.Register(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Registry returns the registry of the server's commands, to which
+// applications embedding the server may add commands and middleware before
+// running it
+func (s *Server) Registry() *command.Registry {
+	return s.registry
 }
 
 // Run starts the server and listens for connections
@@ -66,21 +282,24 @@ func (s *Server) Run() error {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start accepting connections
-	go s.acceptConnections()
+	for _, listener := range s.listeners {
+		go s.acceptConnections(listener)
+	}
+	go s.clientsCron()
 
 	// Wait for shutdown signal
 	<-sigChan
 	return s.Shutdown()
 }
 
-// acceptConnections accepts incoming connections
-func (s *Server) acceptConnections() {
+// acceptConnections accepts incoming connections on listener
+func (s *Server) acceptConnections(listener net.Listener) {
 	for {
 		select {
 		case <-s.shutdown:
 			return
 		default:
-			conn, err := s.listener.Accept()
+			conn, err := listener.Accept()
 			if err != nil {
 				if !strings.Contains(err.Error(), "use of closed network connection") {
 					log.Printf("Error accepting connection: %v", err)
@@ -88,69 +307,197 @@ func (s *Server) acceptConnections() {
 				return
 			}
 
+			if s.clients.Count() >= config.GetInt("maxclients", DefaultMaxClients) {
+				conn.Write([]byte("-ERR max number of clients reached\r\n"))
+				conn.Close()
+				continue
+			}
+			if refusedByProtectedMode(conn) {
+				conn.Write([]byte(protectedModeMessage))
+				conn.Close()
+				continue
+			}
+			setKeepAlive(conn, config.GetInt("tcp-keepalive", DefaultTCPKeepAlive))
+
+			client := command.NewClient(conn)
+			s.clients.Add(client)
 			s.waitGroup.Add(1)
-			s.conns.Store(conn.RemoteAddr(), conn)
-			go s.handleConnection(conn)
+			go s.handleConnection(conn, client)
 		}
 	}
 }
 
+// refusedByProtectedMode reports whether conn must be refused because
+// protected-mode is on, the default user has no password and the client isn't
+// connecting from the loopback interface.
+func refusedByProtectedMode(conn net.Conn) bool {
+	if !config.GetBool("protected-mode", true) {
+		return false
+	}
+	if user, ok := acl.GetACL().User(acl.DefaultUser); !ok || !user.NoPass() {
+		return false
+	}
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	return ok && !addr.IP.IsLoopback()
+}
+
+// setKeepAlive enables TCP keepalive on conn the way Redis does: the first
+// probe is sent after interval seconds of silence, then every third of that,
+// and the connection is dropped after three unanswered probes. An interval of
+// 0 disables keepalive.
+func setKeepAlive(conn net.Conn, interval int) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return
+	}
+	if interval <= 0 {
+		tcpConn.SetKeepAlive(false)
+		return
+	}
+	tcpConn.SetKeepAliveConfig(net.KeepAliveConfig{
+		Enable:   true,
+		Idle:     time.Duration(interval) * time.Second,
+		Interval: time.Duration(max(interval/3, 1)) * time.Second,
+		Count:    3,
+	})
+}
+
+// clientsCron periodically closes clients that have been idle for longer
+// than the timeout setting, and clients whose pending replies exceed their
+// output buffer limit while they are slow to read them. Clients waiting on
+// CLIENT PAUSE aren't idle.
+func (s *Server) clientsCron() {
+	ticker := time.NewTicker(clientsCronInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+		}
+
+		timeout := time.Duration(config.GetInt("timeout", DefaultTimeout)) * time.Second
+		for _, client := range s.clients.All() {
+			if timeout > 0 && client.Idle() > timeout && !client.Blocked() {
+				client.Kill()
+				continue
+			}
+			enforceOutputBufferLimit(client)
+		}
+	}
+}
+
+// enforceOutputBufferLimit kills client if its pending replies exceed the
+// output buffer limit of its class.
+func enforceOutputBufferLimit(client *command.Client) {
+	if client.Killed() {
+		return
+	}
+	if reason := client.OutputBufferLimitExceeded(); reason != "" {
+		log.Printf("Client id=%d addr=%s closed for overcoming of output buffer limits: %s", client.ID, client.Addr, reason)
+		client.Kill()
+	}
+}
+
 // handleConnection handles a client connection
-func (s *Server) handleConnection(conn net.Conn) {
+func (s *Server) handleConnection(conn net.Conn, client *command.Client) {
+	reader := resp.NewReader(conn)
+	reader.SetMaxBulkLenFunc(protoMaxBulkLen.Load)
+
 	defer func() {
 		conn.Close()
-		s.conns.Delete(conn.RemoteAddr())
+		s.clients.Remove(client)
 		s.waitGroup.Done()
 	}()
 
-	reader := bufio.NewReader(conn)
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if err := handshake(tlsConn, client); err != nil {
+			log.Printf("Error accepting a TLS connection from %s: %v", client.Addr, err)
+			return
+		}
+	}
 
 	for {
 		select {
 		case <-s.shutdown:
+			client.Flush()
 			return
 		default:
-			// Set read deadline to handle hanging connections
-			// conn.SetReadDeadline(time.Now().Add(time.Second))
-
-			cmd, args, err := parseRESP(reader)
+			reader.SetUnauthenticated(!client.Authenticated())
+			request, err := reader.ReadCommand()
 			if err != nil {
-				if err == io.EOF {
-					return
-				}
-				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-					continue
+				if resp.IsProtocolError(err) {
+					// The rest of the stream can't be parsed, so reply and hang up
+					client.WriteReply(resp.Error("ERR " + err.Error()))
+					client.Flush()
 				}
-				log.Printf("Error parsing command: %v", err)
-				continue
+				return
+			}
+
+			cmd := strings.ToUpper(string(request[0]))
+			args := make([]string, len(request)-1)
+			for i, arg := range request[1:] {
+				args[i] = string(arg)
 			}
 
-			response, err := s.registry.Execute(cmd, args)
+			client.BeginCommand(cmd, reader.Buffered())
+			meta, _ := s.registry.Metadata(cmd)
+			s.clients.WaitIfPaused(client, meta)
+
+			reply, err := s.registry.Execute(client, cmd, args)
 			if err != nil {
 				if errors.IsCommandError(err) {
 					log.Printf("Command error executing %s: %v", cmd, err)
-					response = fmt.Sprintf("-ERR %v\r\n", err)
+					reply = resp.Error(fmt.Sprintf("%s %v", errors.ReplyCode(err), err))
 				} else {
 					log.Printf("Internal error executing %s: %v", cmd, err)
-					response = "-ERR internal server error\r\n"
+					reply = resp.Error("ERR internal server error")
 				}
 			}
 
-			if _, err = conn.Write([]byte(response)); err != nil {
+			err = client.ReplyToCommand(reply)
+			enforceOutputBufferLimit(client)
+			closing := client.Flags&command.ClientCloseAfterReply != 0 || client.Killed()
+
+			// Replies to pipelined commands are written together once the
+			// client has no more complete commands waiting
+			if err == nil && (closing || !reader.Pending()) {
+				err = client.Flush()
+			}
+			if err != nil {
 				log.Printf("Error writing to connection: %v", err)
 				return
 			}
+			if closing {
+				return
+			}
 		}
 	}
 }
 
+// handshake completes the TLS handshake of conn, then authenticates client as
+// the ACL user its certificate names, if any
+func handshake(conn *tls.Conn, client *command.Client) error {
+	conn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+	client.AuthenticateCertificate(conn.ConnectionState())
+	return nil
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
 	// Signal shutdown
 	close(s.shutdown)
 
-	// Close listener to stop accepting new connections
-	if err := s.listener.Close(); err != nil {
+	// Close listeners to stop accepting new connections
+	if err := closeListeners(s.listeners); err != nil {
 		return errors.Wrap(err, errors.ErrorTypeServer, "failed to close listener")
 	}
 
@@ -174,124 +521,3 @@ func (s *Server) Shutdown() error {
 
 	return nil
 }
-
-// parseRESP reads a RESP array from the client and returns the command and args.
-func parseRESP(reader *bufio.Reader) (string, []string, error) {
-	line, err := readLine(reader)
-	if err != nil {
-		return "", nil, err
-	}
-
-	log.Printf("RESP parsing first line: %q", line)
-
-	// Check if the command is an inline command
-	if len(line) != 0 && !strings.HasPrefix(line, "*") {
-		log.Printf("Parsing as inline command")
-		return parseInlineCommand(line)
-	}
-
-	log.Printf("Parsing as RESP array")
-	cmd, args, err := parseRESPArray(reader, line)
-	if err != nil {
-		return "", nil, err
-	}
-
-	log.Printf("Parsed command: %s, args: %v", cmd, args)
-	return cmd, args, nil
-}
-
-// readLine reads a line and trims CRLF
-func readLine(reader *bufio.Reader) (string, error) {
-	line, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimRight(line, "\r\n"), nil
-}
-
-// parseRESPArray parses a RESP array and returns command and arguments
-func parseRESPArray(reader *bufio.Reader, firstLine string) (string, []string, error) {
-	// Parse array length
-	arrayLen, err := parseArrayLength(firstLine)
-	if err != nil {
-		return "", nil, err
-	}
-
-	// Read each bulk string
-	parts := make([]string, 0, arrayLen)
-	for i := 0; i < arrayLen; i++ {
-		// Read the bulk string
-		str, err := readBulkString(reader)
-		if err != nil {
-			return "", nil, err
-		}
-		parts = append(parts, str)
-	}
-
-	if len(parts) == 0 {
-		return "", nil, errors.New(errors.ErrorTypeCommand, "empty command")
-	}
-
-	return strings.ToUpper(parts[0]), parts[1:], nil
-}
-
-// parseArrayLength parses the array length from a RESP array header
-func parseArrayLength(line string) (int, error) {
-	if !strings.HasPrefix(line, "*") {
-		return 0, errors.New(errors.ErrorTypeCommand, "expected array")
-	}
-
-	length, err := strconv.Atoi(line[1:])
-	if err != nil {
-		return 0, errors.New(errors.ErrorTypeCommand, "invalid array length")
-	}
-
-	return length, nil
-}
-
-// readBulkString reads a RESP bulk string
-func readBulkString(reader *bufio.Reader) (string, error) {
-	// Read the length line
-	lengthLine, err := readLine(reader)
-	if err != nil {
-		return "", errors.Wrap(err, errors.ErrorTypeCommand, "failed to read bulk string length line")
-	}
-
-	if !strings.HasPrefix(lengthLine, "$") {
-		return "", errors.New(errors.ErrorTypeCommand, "expected bulk string")
-	}
-
-	// Parse the length
-	strLen, err := strconv.Atoi(lengthLine[1:])
-	if err != nil {
-		return "", errors.New(errors.ErrorTypeCommand, "invalid bulk string length")
-	}
-
-	// Handle null bulk string
-	if strLen == -1 {
-		return "", nil
-	}
-
-	// Read the string data
-	argBytes := make([]byte, strLen)
-	if _, err := io.ReadFull(reader, argBytes); err != nil {
-		return "", errors.Wrap(err, errors.ErrorTypeCommand, "failed to read bulk string data")
-	}
-
-	// Discard the trailing CRLF
-	if _, err := reader.Discard(2); err != nil {
-		return "", errors.Wrap(err, errors.ErrorTypeCommand, "failed to read bulk string CRLF")
-	}
-
-	return string(argBytes), nil
-}
-
-// parseInlineCommand parses a simple inline command from the client
-func parseInlineCommand(line string) (string, []string, error) {
-	parts := strings.Fields(line)
-	if len(parts) == 0 {
-		return "", nil, errors.New(errors.ErrorTypeCommand, "empty command")
-	}
-
-	return strings.ToUpper(parts[0]), parts[1:], nil
-}
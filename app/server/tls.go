@@ -0,0 +1,102 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+// tlsSettings are the settings the TLS configuration is built from
+type tlsSettings struct {
+	certFile    string
+	keyFile     string
+	caCertFile  string
+	authClients string
+}
+
+// currentTLSSettings returns the TLS settings currently configured
+func currentTLSSettings() tlsSettings {
+	settings := tlsSettings{authClients: DefaultTLSAuthClients}
+	settings.certFile, _ = config.Get("tls-cert-file")
+	settings.keyFile, _ = config.Get("tls-key-file")
+	settings.caCertFile, _ = config.Get("tls-ca-cert-file")
+	if value, ok := config.Get("tls-auth-clients"); ok {
+		settings.authClients = strings.ToLower(value)
+	}
+	return settings
+}
+
+// load builds the TLS configuration the settings describe, reading the
+// certificate, key and CA certificates from their files
+func (s tlsSettings) load() (*tls.Config, error) {
+	if s.certFile == "" || s.keyFile == "" {
+		return nil, fmt.Errorf("tls-cert-file and tls-key-file must be set")
+	}
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch s.authClients {
+	case "no":
+		cfg.ClientAuth = tls.NoClientCert
+	case "optional":
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case "yes":
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid tls-auth-clients %q, must be one of no, optional or yes", s.authClients)
+	}
+	if cfg.ClientAuth == tls.NoClientCert {
+		return cfg, nil
+	}
+
+	if s.caCertFile == "" {
+		return nil, fmt.Errorf("tls-ca-cert-file must be set to authenticate clients")
+	}
+	pem, err := os.ReadFile(s.caCertFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ClientCAs = x509.NewCertPool()
+	if !cfg.ClientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", s.caCertFile)
+	}
+	return cfg, nil
+}
+
+// tlsConfig is the TLS configuration of the TLS listeners. It's built when
+// the listeners are created, then again each time a TLS setting is set, so
+// certificates are reloaded with CONFIG SET, even to the same paths.
+var tlsConfig atomic.Pointer[tls.Config]
+
+// loadTLSConfig builds the TLS configuration from the current settings for
+// the TLS listeners to use from the next handshake. The previous
+// configuration is kept if the settings can't be loaded.
+func loadTLSConfig() error {
+	cfg, err := currentTLSSettings().load()
+	if err != nil {
+		return err
+	}
+	tlsConfig.Store(cfg)
+	return nil
+}
+
+// tlsServerConfig returns the configuration TLS listeners are created with,
+// which defers to tlsConfig on each handshake
+func tlsServerConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return tlsConfig.Load(), nil
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	apperrors "github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// DefaultDatabases is the number of databases when the databases setting is unset
+const DefaultDatabases = 16
+
+// ErrDBIndexOutOfRange is returned when selecting a database that does not exist.
+var ErrDBIndexOutOfRange = apperrors.New(apperrors.ErrorTypeCommand, "DB index is out of range")
+
+// Databases is the set of logical databases of the server, numbered from 0.
+// Connections select one of them with SELECT; values can be moved between them
+// with MOVE and COPY, and whole databases exchanged with SWAPDB.
+type Databases struct {
+	dbs []*Store
+}
+
+var (
+	// databases is the process-wide set of databases
+	databases     *Databases
+	databasesOnce sync.Once
+)
+
+// NewDatabases creates n empty databases sharing a background lazyfree goroutine.
+func NewDatabases(n int) *Databases {
+	lf := newLazyfree()
+	d := &Databases{dbs: make([]*Store, n)}
+	for i := range d.dbs {
+		d.dbs[i] = newStore(i, lf)
+	}
+	return d
+}
+
+// GetDatabases returns the process-wide databases, creating them on first use
+// with the number given by the databases setting.
+func GetDatabases() *Databases {
+	databasesOnce.Do(func() {
+		n := config.GetInt("databases", DefaultDatabases)
+		if n < 1 {
+			n = DefaultDatabases
+		}
+		databases = NewDatabases(n)
+	})
+	return databases
+}
+
+// Count returns the number of databases.
+func (d *Databases) Count() int {
+	return len(d.dbs)
+}
+
+// DB returns the database with the given index, or ErrDBIndexOutOfRange.
+func (d *Databases) DB(index int64) (*Store, error) {
+	if index < 0 || index >= int64(len(d.dbs)) {
+		return nil, ErrDBIndexOutOfRange
+	}
+	return d.dbs[index], nil
+}
+
+// Swap atomically exchanges the contents of two databases, so connections that
+// selected one of them see the other's keys from then on.
+func (d *Databases) Swap(i, j int64) error {
+	a, err := d.DB(i)
+	if err != nil {
+		return err
+	}
+	b, err := d.DB(j)
+	if err != nil {
+		return err
+	}
+	if a == b {
+		return nil
+	}
+	if a.id > b.id {
+		a, b = b, a
+	}
+
+	// The expiry heaps are locked around the swap so no expiry can be tracked
+	// against the wrong database while the keyspaces are exchanged
+	a.exp.mu.Lock()
+	b.exp.mu.Lock()
+	a.data.Swap(b.data)
+	a.exp.items, b.exp.items = b.exp.items, a.exp.items
+	b.exp.mu.Unlock()
+	a.exp.mu.Unlock()
+	return nil
+}
+
+// FlushAll removes every key from every database. If async is true, the
+// removed values are released by a background goroutine.
+func (d *Databases) FlushAll(async bool) {
+	for _, db := range d.dbs {
+		db.Flush(async)
+	}
+}
+
+// atomicWith calls fn while holding the locks of key in s and of otherKey in
+// other. The database with the lower index is always locked first, so
+// concurrent operations between the same pair of databases cannot deadlock.
+func (s *Store) atomicWith(key string, other *Store, otherKey string, fn func(entries, otherEntries types.LockedEntries[string, *RedisValue])) {
+	if s.id <= other.id {
+		s.data.AtomicWith(key, other.data, otherKey, fn)
+		return
+	}
+	other.data.AtomicWith(otherKey, s.data, key, func(otherEntries, entries types.LockedEntries[string, *RedisValue]) {
+		fn(entries, otherEntries)
+	})
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDatabases_DB(t *testing.T) {
+	d := NewDatabases(4)
+	if d.Count() != 4 {
+		t.Errorf("Expected 4 databases, got %d", d.Count())
+	}
+
+	db, err := d.DB(3)
+	if err != nil || db.ID() != 3 {
+		t.Errorf("Expected database 3, got %v (err: %v)", db, err)
+	}
+	for _, index := range []int64{-1, 4} {
+		if _, err := d.DB(index); err != ErrDBIndexOutOfRange {
+			t.Errorf("DB(%d): expected ErrDBIndexOutOfRange, got %v", index, err)
+		}
+	}
+
+	if GetStore() != GetDatabases().dbs[0] {
+		t.Error("Expected GetStore to return database 0")
+	}
+}
+
+func TestDatabases_Swap(t *testing.T) {
+	d := NewDatabases(2)
+	db0, _ := d.DB(0)
+	db1, _ := d.DB(1)
+	db0.Set("a", "in db0", time.Hour)
+	db1.Set("b", "in db1", 0)
+
+	if err := d.Swap(1, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if db0.Exists("a") || !db0.Exists("b") {
+		t.Error("Expected database 0 to hold the keys of database 1")
+	}
+	if value, err := db1.Get("a"); err != nil || value != "in db0" {
+		t.Errorf("Expected database 1 to hold a, got %q (err: %v)", value, err)
+	}
+	if db1.exp.Len() != 1 || db0.exp.Len() != 0 {
+		t.Errorf("Expected the expiry heaps to be swapped, got %d and %d items", db0.exp.Len(), db1.exp.Len())
+	}
+
+	if err := d.Swap(0, 2); err != ErrDBIndexOutOfRange {
+		t.Errorf("Expected ErrDBIndexOutOfRange, got %v", err)
+	}
+}
+
+func TestMoveAndCopyBetweenDatabases(t *testing.T) {
+	d := NewDatabases(2)
+	db0, _ := d.DB(0)
+	db1, _ := d.DB(1)
+	db0.Set("key", "value", time.Hour)
+
+	if !db0.Copy("key", db1, "copy", false) {
+		t.Error("Expected the key to be copied to database 1")
+	}
+	if !db0.Move("key", db1) {
+		t.Error("Expected the key to be moved to database 1")
+	}
+	if db0.Exists("key") || !db1.Exists("key") {
+		t.Error("Expected the key to only exist in database 1")
+	}
+	if db1.exp.Len() != 2 {
+		t.Errorf("Expected the TTLs to be tracked in database 1, got %d items", db1.exp.Len())
+	}
+
+	// Moving from the higher database back and onto an existing key
+	db0.Set("key", "other", 0)
+	if db1.Move("key", db0) {
+		t.Error("Expected MOVE not to overwrite an existing key")
+	}
+	if db1.Move("missing", db0) {
+		t.Error("Expected MOVE of a missing key to report false")
+	}
+}
+
+func TestKeyspaceStats(t *testing.T) {
+	d := NewDatabases(1)
+	db, _ := d.DB(0)
+	db.Set("persistent", "value", 0)
+	db.Set("volatile", "value", time.Hour)
+
+	keys, expires, avgTTL := db.KeyspaceStats()
+	if keys != 2 || expires != 1 {
+		t.Errorf("Expected 2 keys and 1 expire, got %d and %d", keys, expires)
+	}
+	if avgTTL <= 59*time.Minute || avgTTL > time.Hour {
+		t.Errorf("Expected an average TTL of about an hour, got %v", avgTTL)
+	}
+}
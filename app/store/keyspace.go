@@ -0,0 +1,275 @@
+package store
+
+import (
+	"slices"
+	"time"
+
+	apperrors "github.com/dotslash21/redis-clone/app/errors"
+	"github.com/dotslash21/redis-clone/app/glob"
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+// ErrNoSuchKey is returned when renaming a key that does not exist.
+var ErrNoSuchKey = apperrors.New(apperrors.ErrorTypeCommand, "no such key")
+
+// String returns the name of the type as reported by the TYPE command.
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeZSet:
+		return "zset"
+	default:
+		return "unknown"
+	}
+}
+
+// clone returns a deep copy of the value, including its TTL.
+func (v *RedisValue) clone() *RedisValue {
+	c := &RedisValue{Type: v.Type, ExpireAt: v.ExpireAt}
+	switch v.Type {
+	case TypeString:
+		c.Value = slices.Clone(v.Value)
+	case TypeZSet:
+		c.ZSet = v.ZSet.Clone()
+	}
+	return c
+}
+
+// Exists reports whether key holds a live value.
+func (s *Store) Exists(key string) bool {
+	found := false
+	s.data.View(key, func(val *RedisValue, exists bool) {
+		found = exists && !val.isExpiredAt(time.Now())
+	})
+	return found
+}
+
+// Type returns the type of the value stored at key, and false if the key is
+// missing or expired.
+func (s *Store) Type(key string) (ValueType, bool) {
+	var valueType ValueType
+	found := false
+	s.data.View(key, func(val *RedisValue, exists bool) {
+		if exists && !val.isExpiredAt(time.Now()) {
+			valueType, found = val.Type, true
+		}
+	})
+	return valueType, found
+}
+
+// Unlink removes a key like Delete, but values that are expensive to free are
+// released by a background goroutine. It reports whether a live key was removed.
+func (s *Store) Unlink(key string) bool {
+	var removed *RedisValue
+	s.data.Compute(key, func(val *RedisValue, exists bool) (*RedisValue, bool) {
+		if exists && !val.isExpiredAt(time.Now()) {
+			removed = val
+		}
+		return nil, false
+	})
+
+	if removed != nil && freeEffort(removed) > lazyfreeThreshold {
+		s.lazyfree.free(removed)
+	}
+	return removed != nil
+}
+
+// Rename atomically moves the value at src, including its TTL, to dst,
+// replacing any existing value. If nx is true, nothing is done when dst
+// already exists. It reports whether the key was renamed, and returns
+// ErrNoSuchKey if src does not exist.
+func (s *Store) Rename(src, dst string, nx bool) (bool, error) {
+	var err error
+	renamed := false
+	var expireAt time.Time
+
+	s.data.Atomic([]string{src, dst}, func(entries types.LockedEntries[string, *RedisValue]) {
+		now := time.Now()
+		val, exists := entries.Get(src)
+		if !exists || val.isExpiredAt(now) {
+			err = ErrNoSuchKey
+			return
+		}
+
+		if src == dst {
+			renamed = !nx
+			return
+		}
+		if nx {
+			if current, exists := entries.Get(dst); exists && !current.isExpiredAt(now) {
+				return
+			}
+		}
+
+		entries.Delete(src)
+		entries.Set(dst, val)
+		renamed = true
+		expireAt = val.ExpireAt
+	})
+
+	if !expireAt.IsZero() {
+		s.trackExpiry(dst, expireAt)
+	}
+	return renamed, err
+}
+
+// Copy atomically stores a copy of the value at src, including its TTL, at
+// dst in the database dstDB, which may be s itself. An existing dst is only
+// overwritten if replace is true. It reports whether the value was copied.
+func (s *Store) Copy(src string, dstDB *Store, dst string, replace bool) bool {
+	copied := false
+	var expireAt time.Time
+
+	s.atomicWith(src, dstDB, dst, func(entries, dstEntries types.LockedEntries[string, *RedisValue]) {
+		now := time.Now()
+		val, exists := entries.Get(src)
+		if !exists || val.isExpiredAt(now) {
+			return
+		}
+		if current, exists := dstEntries.Get(dst); exists && !current.isExpiredAt(now) && !replace {
+			return
+		}
+
+		dstEntries.Set(dst, val.clone())
+		copied = true
+		expireAt = val.ExpireAt
+	})
+
+	if !expireAt.IsZero() {
+		dstDB.trackExpiry(dst, expireAt)
+	}
+	return copied
+}
+
+// Move atomically moves key, including its TTL, to the database dstDB. Nothing
+// is done if the key already exists in dstDB. It reports whether the key was moved.
+func (s *Store) Move(key string, dstDB *Store) bool {
+	moved := false
+	var expireAt time.Time
+
+	s.atomicWith(key, dstDB, key, func(entries, dstEntries types.LockedEntries[string, *RedisValue]) {
+		now := time.Now()
+		val, exists := entries.Get(key)
+		if !exists || val.isExpiredAt(now) {
+			return
+		}
+		if current, exists := dstEntries.Get(key); exists && !current.isExpiredAt(now) {
+			return
+		}
+
+		entries.Delete(key)
+		dstEntries.Set(key, val)
+		moved = true
+		expireAt = val.ExpireAt
+	})
+
+	if !expireAt.IsZero() {
+		dstDB.trackExpiry(key, expireAt)
+	}
+	return moved
+}
+
+// RandomKey returns a random live key, or false if the store is empty.
+func (s *Store) RandomKey() (string, bool) {
+	now := time.Now()
+	key, _, ok := s.data.RandomEntry(func(_ string, val *RedisValue) bool {
+		return !val.isExpiredAt(now)
+	})
+	return key, ok
+}
+
+// DBSize returns the number of keys in the store. Like Redis, it may include
+// keys that have expired but have not been removed yet.
+func (s *Store) DBSize() int {
+	return s.data.Len()
+}
+
+// Flush atomically removes every key. If async is true, the removed values are
+// released by a background goroutine instead of the caller.
+func (s *Store) Flush(async bool) {
+	old := s.data.Reset()
+
+	s.exp.mu.Lock()
+	s.exp.items = s.exp.items[:0:0]
+	s.exp.mu.Unlock()
+
+	if async && old.Len() > 0 {
+		s.lazyfree.free(old)
+	}
+}
+
+// KeyspaceStats returns the number of live keys, how many of them have a TTL,
+// and their average remaining TTL, as reported by INFO keyspace. Unlike DBSize
+// it visits the whole keyspace, holding one shard's lock at a time.
+func (s *Store) KeyspaceStats() (keys, expires int, avgTTL time.Duration) {
+	now := time.Now()
+	var totalTTL time.Duration
+
+	s.data.ForEach(func(_ string, val *RedisValue) {
+		if val.isExpiredAt(now) {
+			return
+		}
+		keys++
+		if !val.ExpireAt.IsZero() {
+			expires++
+			totalTTL += val.ExpireAt.Sub(now)
+		}
+	})
+
+	if expires > 0 {
+		avgTTL = totalTTL / time.Duration(expires)
+	}
+	return keys, expires, avgTTL
+}
+
+// LazyfreedObjects returns the number of objects released in the background.
+func (s *Store) LazyfreedObjects() int64 {
+	return s.lazyfree.freed.Load()
+}
+
+// scanMaxPrealloc caps the capacity Scan preallocates for its result, so a
+// huge COUNT hint cannot make it allocate more than the keys it finds.
+const scanMaxPrealloc = 1024
+
+// Scan performs one step of an incremental iteration over the keyspace,
+// starting from cursor 0. It returns the live keys found that match pattern
+// and, if typeName is not empty, whose type is typeName, along with the cursor
+// to continue from, which is 0 once the iteration is complete. count is a hint
+// of how many keys to visit. Keys present for the whole iteration are returned
+// at least once, but may be returned more than once.
+func (s *Store) Scan(cursor uint64, count int, pattern, typeName string) ([]string, uint64) {
+	now := time.Now()
+	matchAll := pattern == "*"
+	// count comes from the client, so it only bounds the initial capacity
+	keys := make([]string, 0, min(count, scanMaxPrealloc))
+
+	next := s.data.Scan(cursor, count, func(key string, val *RedisValue) {
+		if val.isExpiredAt(now) {
+			return
+		}
+		if typeName != "" && val.Type.String() != typeName {
+			return
+		}
+		if !matchAll && !glob.Match(pattern, key, false) {
+			return
+		}
+		keys = append(keys, key)
+	})
+	return keys, next
+}
+
+// Keys returns every live key matching pattern. It visits the whole keyspace,
+// holding one shard's lock at a time.
+func (s *Store) Keys(pattern string) []string {
+	now := time.Now()
+	matchAll := pattern == "*"
+	var keys []string
+
+	s.data.ForEach(func(key string, val *RedisValue) {
+		if !val.isExpiredAt(now) && (matchAll || glob.Match(pattern, key, false)) {
+			keys = append(keys, key)
+		}
+	})
+	return keys
+}
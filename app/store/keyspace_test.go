@@ -0,0 +1,211 @@
+package store
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+func TestExistsAndType(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	s.Set("string", "value", 0)
+	zset := types.NewSortedSet()
+	zset.Add("member", 1)
+	s.SetZSet("zset", zset)
+	s.Set("expired", "value", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	tests := []struct {
+		key      string
+		exists   bool
+		expected string
+	}{
+		{"string", true, "string"},
+		{"zset", true, "zset"},
+		{"expired", false, ""},
+		{"missing", false, ""},
+	}
+	for _, tt := range tests {
+		if got := s.Exists(tt.key); got != tt.exists {
+			t.Errorf("Exists(%q): expected %t, got %t", tt.key, tt.exists, got)
+		}
+		valueType, ok := s.Type(tt.key)
+		if ok != tt.exists || (ok && valueType.String() != tt.expected) {
+			t.Errorf("Type(%q): expected (%q, %t), got (%q, %t)", tt.key, tt.expected, tt.exists, valueType, ok)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	s.Set("src", "value", time.Hour)
+	renamed, err := s.Rename("src", "dst", false)
+	if err != nil || !renamed {
+		t.Fatalf("Expected rename to succeed, got (%t, %v)", renamed, err)
+	}
+	if s.Exists("src") {
+		t.Error("Expected src to be removed")
+	}
+	if val, ok := s.data.Get("dst"); !ok || string(val.Value) != "value" || val.ExpireAt.IsZero() {
+		t.Errorf("Expected dst to hold the value and TTL, got %+v", val)
+	}
+
+	if _, err := s.Rename("missing", "dst", false); err != ErrNoSuchKey {
+		t.Errorf("Expected ErrNoSuchKey, got %v", err)
+	}
+
+	s.Set("other", "other", 0)
+	if renamed, err := s.Rename("other", "dst", true); err != nil || renamed {
+		t.Errorf("Expected NX rename onto an existing key to do nothing, got (%t, %v)", renamed, err)
+	}
+	if renamed, err := s.Rename("dst", "dst", false); err != nil || !renamed {
+		t.Errorf("Expected renaming a key to itself to succeed, got (%t, %v)", renamed, err)
+	}
+	if renamed, err := s.Rename("dst", "dst", true); err != nil || renamed {
+		t.Errorf("Expected NX renaming a key to itself to do nothing, got (%t, %v)", renamed, err)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	zset := types.NewSortedSet()
+	zset.Add("member", 1)
+	s.SetZSet("src", zset)
+
+	if !s.Copy("src", s, "dst", false) {
+		t.Fatal("Expected copy to succeed")
+	}
+	// The copy must be independent of the source
+	s.UpdateZSet("dst", func(z *types.SortedSet, exists bool) error {
+		z.Add("other", 2)
+		return nil
+	})
+	if zset.Len() != 1 {
+		t.Errorf("Expected the source to be unchanged, got %d members", zset.Len())
+	}
+
+	if s.Copy("src", s, "dst", false) {
+		t.Error("Expected copy onto an existing key to fail without replace")
+	}
+	if !s.Copy("src", s, "dst", true) {
+		t.Error("Expected copy with replace to succeed")
+	}
+	if s.Copy("missing", s, "dst", true) {
+		t.Error("Expected copy of a missing key to fail")
+	}
+}
+
+func TestRandomKeyAndDBSize(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	if _, ok := s.RandomKey(); ok {
+		t.Error("Expected no random key from an empty store")
+	}
+
+	s.Set("only", "value", 0)
+	if key, ok := s.RandomKey(); !ok || key != "only" {
+		t.Errorf("Expected (only, true), got (%q, %t)", key, ok)
+	}
+	if size := s.DBSize(); size != 1 {
+		t.Errorf("Expected size 1, got %d", size)
+	}
+}
+
+func TestUnlinkAndFlush(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	zset := types.NewSortedSet()
+	for i := 0; i < lazyfreeThreshold*2; i++ {
+		zset.Add(strconv.Itoa(i), float64(i))
+	}
+	s.SetZSet("big", zset)
+	s.Set("small", "value", 0)
+
+	before := s.LazyfreedObjects()
+	if !s.Unlink("big") || !s.Unlink("small") {
+		t.Fatal("Expected both keys to be unlinked")
+	}
+	if s.Unlink("missing") {
+		t.Error("Expected unlinking a missing key to report false")
+	}
+	waitForLazyfree(t, s, before+1)
+
+	for i := 0; i < 10; i++ {
+		s.Set("key"+strconv.Itoa(i), "value", time.Hour)
+	}
+	before = s.LazyfreedObjects()
+	s.Flush(true)
+	if size := s.DBSize(); size != 0 {
+		t.Errorf("Expected empty store after flush, got %d keys", size)
+	}
+	if s.exp.Len() != 0 {
+		t.Errorf("Expected expiry heap to be emptied, got %d items", s.exp.Len())
+	}
+	waitForLazyfree(t, s, before+1)
+}
+
+func TestScanAndKeys(t *testing.T) {
+	s := GetStore()
+	s.data.Clear()
+
+	for i := 0; i < 100; i++ {
+		s.Set("user:"+strconv.Itoa(i), "value", 0)
+	}
+	zset := types.NewSortedSet()
+	zset.Add("member", 1)
+	s.SetZSet("user:zset", zset)
+	s.Set("session:1", "value", 0)
+	s.Set("user:expired", "value", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	seen := make(map[string]bool)
+	cursor := uint64(0)
+	for {
+		var keys []string
+		keys, cursor = s.Scan(cursor, 10, "user:*", "string")
+		for _, key := range keys {
+			seen[key] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 100 {
+		t.Errorf("Expected SCAN to return the 100 live string user keys, got %d", len(seen))
+	}
+	if seen["user:zset"] || seen["session:1"] || seen["user:expired"] {
+		t.Errorf("Expected SCAN to filter by pattern, type and expiry, got %v", seen)
+	}
+
+	keys := s.Keys("*:1")
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"session:1", "user:1"}) {
+		t.Errorf("Expected KEYS *:1 to return [session:1 user:1], got %v", keys)
+	}
+	if n := len(s.Keys("*")); n != 102 {
+		t.Errorf("Expected KEYS * to return 102 live keys, got %d", n)
+	}
+}
+
+// waitForLazyfree waits for the background goroutine to release at least n objects in total
+func waitForLazyfree(t *testing.T, s *Store, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for s.LazyfreedObjects() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d objects to be freed in the background, got %d", n, s.LazyfreedObjects())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"sync/atomic"
+
+	"github.com/dotslash21/redis-clone/app/types"
+)
+
+const (
+	// lazyfreeThreshold is the freeing effort, roughly the number of
+	// allocations making up a value, above which UNLINK and asynchronous
+	// flushes release the value in the background, as in Redis
+	lazyfreeThreshold = 64
+	// lazyfreeQueueSize bounds the number of objects waiting to be released
+	lazyfreeQueueSize = 1024
+)
+
+// lazyfree releases large values on a background goroutine, so deleting them
+// does not stall the client that asked for the deletion
+type lazyfree struct {
+	queue chan any
+	// freed counts the objects released by the background goroutine
+	freed atomic.Int64
+}
+
+// newLazyfree creates a lazyfree and starts its background goroutine
+func newLazyfree() *lazyfree {
+	lf := &lazyfree{queue: make(chan any, lazyfreeQueueSize)}
+	go lf.run()
+	return lf
+}
+
+// run releases queued objects until the queue is closed
+func (lf *lazyfree) run() {
+	for obj := range lf.queue {
+		release(obj)
+		lf.freed.Add(1)
+	}
+}
+
+// free hands obj to the background goroutine. If the queue is full the object
+// is released by the caller instead.
+func (lf *lazyfree) free(obj any) {
+	select {
+	case lf.queue <- obj:
+	default:
+		release(obj)
+	}
+}
+
+// freeEffort estimates the work needed to release a value
+func freeEffort(v *RedisValue) int {
+	if v.Type == TypeZSet {
+		return v.ZSet.Len()
+	}
+	return 1
+}
+
+// release drops the references held by a value or a detached keyspace so the
+// garbage collector can reclaim them. Clearing large containers is the
+// expensive part of freeing, which is why it happens off the request path.
+func release(obj any) {
+	switch obj := obj.(type) {
+	case *RedisValue:
+		obj.Value = nil
+		obj.ZSet = nil
+	case *types.ThreadSafeMap[string, *RedisValue]:
+		obj.ForEach(func(_ string, v *RedisValue) {
+			release(v)
+		})
+		obj.Clear()
+	}
+}
@@ -0,0 +1,13 @@
+package store
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/dotslash21/redis-clone/app/config"
+)
+
+// init declares the parameters of the databases
+func init() {
+	config.Register(config.Parameter{Name: "databases", Type: config.TypeInt, Default: strconv.Itoa(DefaultDatabases), Min: 1, Max: math.MaxInt32, Immutable: true})
+}
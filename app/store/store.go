@@ -6,15 +6,38 @@ import (
 	"sync"
 	"time"
 
+	apperrors "github.com/dotslash21/redis-clone/app/errors"
 	"github.com/dotslash21/redis-clone/app/types"
 )
 
+// ValueType identifies the Redis data type held by a RedisValue.
+type ValueType int
+
+const (
+	// TypeString is a string value, stored in RedisValue.Value
+	TypeString ValueType = iota
+	// TypeZSet is a sorted set, stored in RedisValue.ZSet
+	TypeZSet
+)
+
+// ErrWrongType is returned when a key holds a different type than an operation expects.
+var ErrWrongType = apperrors.NewWithCode("WRONGTYPE", "Operation against a key holding the wrong kind of value")
+
 // RedisValue holds both the value and metadata (type info, expiry).
+// String values are kept as a byte slice so bit-level commands can modify
+// them in place; Value and ZSet must only be accessed while holding the key's lock.
 type RedisValue struct {
-	Value    string
+	Type     ValueType
+	Value    []byte
+	ZSet     *types.SortedSet
 	ExpireAt time.Time
 }
 
+// isExpiredAt reports whether the value has a TTL that elapsed before now.
+func (v *RedisValue) isExpiredAt(now time.Time) bool {
+	return !v.ExpireAt.IsZero() && now.After(v.ExpireAt)
+}
+
 // expiryItem holds the key and its expiry time for the min-heap.
 type expiryItem struct {
 	key      string
@@ -43,24 +66,36 @@ func (eh *expiryHeap) Pop() interface{} {
 }
 
 // Store is a Redis-like key-value store with a min-heap for expiry.
+// Each Store is one of the logical databases of a Databases.
 type Store struct {
-	data *types.ThreadSafeMap[string, *RedisValue]
-	exp  expiryHeap
+	id       int
+	data     *types.ThreadSafeMap[string, *RedisValue]
+	exp      expiryHeap
+	lazyfree *lazyfree
 }
 
-// store is a singleton instance of Store
-var store *Store
+// newStore creates an empty database with the given index
+func newStore(id int, lf *lazyfree) *Store {
+	s := &Store{
+		id:       id,
+		data:     types.NewThreadSafeMap[string, *RedisValue](),
+		exp:      expiryHeap{items: []expiryItem{}},
+		lazyfree: lf,
+	}
+	heap.Init(&s.exp)
+	return s
+}
 
-// GetStore returns the store instance, creating it if it doesn't exist.
+// GetStore returns database 0 of the process-wide databases, which is the one
+// selected by new connections.
 func GetStore() *Store {
-	if store == nil {
-		store = &Store{
-			data: types.NewThreadSafeMap[string, *RedisValue](),
-			exp:  expiryHeap{items: []expiryItem{}},
-		}
-		heap.Init(&store.exp)
-	}
-	return store
+	s, _ := GetDatabases().DB(0)
+	return s
+}
+
+// ID returns the index of the database.
+func (s *Store) ID() int {
+	return s.id
 }
 
 // flushExpired deletes all expired keys at once.
@@ -77,6 +112,14 @@ func (s *Store) FlushExpired() {
 	}
 }
 
+// trackExpiry schedules key to be removed by FlushExpired at expireAt.
+// It must not be called while holding a key's lock.
+func (s *Store) trackExpiry(key string, expireAt time.Time) {
+	s.exp.mu.Lock()
+	heap.Push(&s.exp, expiryItem{key: key, expireAt: expireAt})
+	s.exp.mu.Unlock()
+}
+
 // isExpired returns true if the key has expired and removes it from the store.
 func (s *Store) isExpired(key string) bool {
 	val, exists := s.data.Get(key)
@@ -95,14 +138,11 @@ func (s *Store) Set(key, value string, ttl time.Duration) {
 	expiry := time.Time{}
 	if ttl > 0 {
 		expiry = time.Now().Add(ttl)
-
-		s.exp.mu.Lock()
-		heap.Push(&s.exp, expiryItem{key: key, expireAt: expiry})
-		s.exp.mu.Unlock()
+		s.trackExpiry(key, expiry)
 	}
 
 	s.data.Set(key, &RedisValue{
-		Value:    value,
+		Value:    []byte(value),
 		ExpireAt: expiry,
 	})
 }
@@ -114,10 +154,138 @@ func (s *Store) Get(key string) (value string, err error) {
 		return "", errors.New("key expired")
 	}
 
-	val, exists := s.data.Get(key)
-	if !exists || s.isExpired(key) {
+	found := false
+	s.data.View(key, func(val *RedisValue, exists bool) {
+		if !exists || val.isExpiredAt(time.Now()) {
+			return
+		}
+		found = true
+		if val.Type != TypeString {
+			err = ErrWrongType
+			return
+		}
+		value = string(val.Value)
+	})
+	if !found {
 		return "", errors.New("key not found")
 	}
 
-	return val.Value, nil
+	return value, err
+}
+
+// ReadString calls fn with the raw bytes of the string stored at key while
+// holding the key's read lock. exists is false if the key is missing or expired.
+// fn must not retain value after returning. ErrWrongType is returned, without
+// calling fn, if the key holds another type.
+func (s *Store) ReadString(key string, fn func(value []byte, exists bool)) error {
+	var err error
+	s.data.View(key, func(val *RedisValue, exists bool) {
+		if !exists || val.isExpiredAt(time.Now()) {
+			fn(nil, false)
+			return
+		}
+		if val.Type != TypeString {
+			err = ErrWrongType
+			return
+		}
+		fn(val.Value, true)
+	})
+	return err
+}
+
+// UpdateString atomically replaces the string stored at key with the result of fn.
+// fn receives the current bytes (nil if the key is missing or expired) and may
+// modify them in place; the returned slice becomes the new value. The key's TTL
+// is preserved. If the key does not exist and fn returns nil, no key is created.
+// If fn returns an error the stored value is left untouched.
+func (s *Store) UpdateString(key string, fn func(value []byte, exists bool) ([]byte, error)) error {
+	var fnErr error
+	s.data.Compute(key, func(val *RedisValue, exists bool) (*RedisValue, bool) {
+		if exists && val.isExpiredAt(time.Now()) {
+			exists = false
+		}
+		if exists && val.Type != TypeString {
+			fnErr = ErrWrongType
+			return val, true
+		}
+
+		var current []byte
+		if exists {
+			current = val.Value
+		}
+
+		updated, err := fn(current, exists)
+		if err != nil {
+			fnErr = err
+			return val, val != nil
+		}
+
+		if !exists {
+			return &RedisValue{Value: updated}, updated != nil
+		}
+		val.Value = updated
+		return val, true
+	})
+	return fnErr
+}
+
+// ReadZSet calls fn with the sorted set stored at key while holding the key's
+// read lock. exists is false if the key is missing or expired, in which case
+// zset is nil. fn must not modify or retain zset. ErrWrongType is returned,
+// without calling fn, if the key holds another type.
+func (s *Store) ReadZSet(key string, fn func(zset *types.SortedSet, exists bool)) error {
+	var err error
+	s.data.View(key, func(val *RedisValue, exists bool) {
+		if !exists || val.isExpiredAt(time.Now()) {
+			fn(nil, false)
+			return
+		}
+		if val.Type != TypeZSet {
+			err = ErrWrongType
+			return
+		}
+		fn(val.ZSet, true)
+	})
+	return err
+}
+
+// UpdateZSet atomically modifies the sorted set stored at key. fn receives the
+// existing set, or a new empty one if the key is missing or expired, and may
+// modify it in place. The key's TTL is preserved, and a set left empty by fn is
+// deleted. ErrWrongType is returned, without calling fn, if the key holds
+// another type, and any error returned by fn is passed through.
+func (s *Store) UpdateZSet(key string, fn func(zset *types.SortedSet, exists bool) error) error {
+	var fnErr error
+	s.data.Compute(key, func(val *RedisValue, exists bool) (*RedisValue, bool) {
+		if exists && val.isExpiredAt(time.Now()) {
+			exists = false
+		}
+		if exists && val.Type != TypeZSet {
+			fnErr = ErrWrongType
+			return val, true
+		}
+
+		if !exists {
+			val = &RedisValue{Type: TypeZSet, ZSet: types.NewSortedSet()}
+		}
+
+		fnErr = fn(val.ZSet, exists)
+		return val, val.ZSet.Len() > 0
+	})
+	return fnErr
+}
+
+// SetZSet stores a sorted set at key, replacing any existing value and TTL.
+func (s *Store) SetZSet(key string, zset *types.SortedSet) {
+	s.data.Set(key, &RedisValue{Type: TypeZSet, ZSet: zset})
+}
+
+// Delete removes a key from the store, reporting whether a live key was removed.
+func (s *Store) Delete(key string) bool {
+	removed := false
+	s.data.Compute(key, func(val *RedisValue, exists bool) (*RedisValue, bool) {
+		removed = exists && !val.isExpiredAt(time.Now())
+		return nil, false
+	})
+	return removed
 }
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+const (
+	// minBuckets is the smallest number of buckets in a hashTable
+	minBuckets = 4
+	// shrinkRatio is how many times the bucket count must exceed the number
+	// of entries before a hashTable shrinks
+	shrinkRatio = 8
+)
+
+// entry is a key-value pair stored in a hashTable bucket, along with the
+// key's hash so resizing does not need to rehash keys.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64
+}
+
+// hashTable is a chained hash table whose buckets can be visited one at a time
+// with a stateless cursor, like the dict used by Redis. The number of buckets
+// is always a power of two and a key's bucket is given by the low bits of its
+// hash, which is what lets SCAN keep its guarantees across resizes.
+// It is not safe for concurrent use.
+type hashTable[K comparable, V any] struct {
+	buckets [][]entry[K, V]
+	count   int
+}
+
+// newHashTable creates an empty hashTable.
+func newHashTable[K comparable, V any]() *hashTable[K, V] {
+	return &hashTable[K, V]{buckets: make([][]entry[K, V], minBuckets)}
+}
+
+// mask returns the bitmask selecting a bucket from a hash.
+func (t *hashTable[K, V]) mask() uint64 {
+	return uint64(len(t.buckets) - 1)
+}
+
+// bucket returns the bucket a hash belongs to.
+func (t *hashTable[K, V]) bucket(hash uint64) []entry[K, V] {
+	return t.buckets[hash&t.mask()]
+}
+
+// get returns the value stored for key and whether it exists.
+func (t *hashTable[K, V]) get(key K, hash uint64) (V, bool) {
+	for _, e := range t.bucket(hash) {
+		if e.hash == hash && e.key == key {
+			return e.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// set stores value for key, growing the table when it gets too full.
+func (t *hashTable[K, V]) set(key K, hash uint64, value V) {
+	idx := hash & t.mask()
+	for i, e := range t.buckets[idx] {
+		if e.hash == hash && e.key == key {
+			t.buckets[idx][i].value = value
+			return
+		}
+	}
+
+	t.buckets[idx] = append(t.buckets[idx], entry[K, V]{key: key, value: value, hash: hash})
+	t.count++
+	if t.count > len(t.buckets) {
+		t.resize(len(t.buckets) * 2)
+	}
+}
+
+// delete removes key, shrinking the table when it gets too sparse. It reports
+// whether the key was present.
+func (t *hashTable[K, V]) delete(key K, hash uint64) bool {
+	idx := hash & t.mask()
+	b := t.buckets[idx]
+	for i, e := range b {
+		if e.hash == hash && e.key == key {
+			last := len(b) - 1
+			b[i] = b[last]
+			b[last] = entry[K, V]{}
+			t.buckets[idx] = b[:last]
+			t.count--
+
+			if len(t.buckets) > minBuckets && t.count*shrinkRatio < len(t.buckets) {
+				t.resize(len(t.buckets) / 2)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// resize moves every entry into a table of the given number of buckets.
+func (t *hashTable[K, V]) resize(size int) {
+	buckets := make([][]entry[K, V], size)
+	mask := uint64(size - 1)
+	for _, b := range t.buckets {
+		for _, e := range b {
+			buckets[e.hash&mask] = append(buckets[e.hash&mask], e)
+		}
+	}
+	t.buckets = buckets
+}
+
+// forEach calls fn for every entry until fn returns false.
+func (t *hashTable[K, V]) forEach(fn func(K, V) bool) bool {
+	for _, b := range t.buckets {
+		for _, e := range b {
+			if !fn(e.key, e.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// random returns a random entry accepted by accept, starting from a random
+// bucket and a random position within it. Entries rejected by accept are skipped.
+func (t *hashTable[K, V]) random(accept func(K, V) bool) (K, V, bool) {
+	if t.count > 0 {
+		start := rand.IntN(len(t.buckets))
+		for i := range len(t.buckets) {
+			b := t.buckets[(start+i)%len(t.buckets)]
+			if len(b) == 0 {
+				continue
+			}
+			offset := rand.IntN(len(b))
+			for j := range len(b) {
+				e := b[(offset+j)%len(b)]
+				if accept(e.key, e.value) {
+					return e.key, e.value, true
+				}
+			}
+		}
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// scan calls fn for every entry in the bucket at cursor and returns the cursor
+// of the next bucket, or 0 once every bucket has been visited, along with the
+// number of entries visited.
+//
+// Cursors are incremented in reverse binary order, so the high bits of the
+// bucket index are incremented first. Every bucket visited so far is a prefix
+// of that order whatever the table size: when the table doubles, each visited
+// bucket's entries move to buckets that come before the cursor, and when it
+// halves, the cursor lands on a merged bucket that contains every entry not
+// yet visited. Keys present for the whole scan are therefore always returned,
+// although after a shrink some may be returned more than once.
+func (t *hashTable[K, V]) scan(cursor uint64, fn func(K, V)) (uint64, int) {
+	mask := t.mask()
+	b := t.buckets[cursor&mask]
+	for _, e := range b {
+		fn(e.key, e.value)
+	}
+
+	// Set the bits above the mask so incrementing the reversed cursor carries
+	// straight into the masked bits; the carry leaves the unmasked bits zero
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	cursor = bits.Reverse64(cursor)
+	return cursor, len(b)
+}
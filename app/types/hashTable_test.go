@@ -0,0 +1,130 @@
+package types
+
+import (
+	"strconv"
+	"testing"
+)
+
+// testHash returns the in-shard hash of a key, as used by ThreadSafeMap
+func testHash(key string) uint64 {
+	_, hash := hashKey(key)
+	return hash
+}
+
+func TestHashTable_SetGetDelete(t *testing.T) {
+	table := newHashTable[string, int]()
+
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		table.set(key, testHash(key), i)
+	}
+	if table.count != 1000 {
+		t.Fatalf("Expected 1000 entries, got %d", table.count)
+	}
+	if len(table.buckets) < 1000 {
+		t.Errorf("Expected the table to grow to at least 1000 buckets, got %d", len(table.buckets))
+	}
+
+	table.set("42", testHash("42"), -1)
+	if value, ok := table.get("42", testHash("42")); !ok || value != -1 {
+		t.Errorf("Expected (-1, true) after overwriting, got (%d, %t)", value, ok)
+	}
+	if table.count != 1000 {
+		t.Errorf("Expected overwriting not to change the count, got %d", table.count)
+	}
+
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		if !table.delete(key, testHash(key)) {
+			t.Fatalf("Expected %s to be deleted", key)
+		}
+	}
+	if table.delete("0", testHash("0")) {
+		t.Error("Expected deleting a missing key to report false")
+	}
+	if table.count != 0 || len(table.buckets) != minBuckets {
+		t.Errorf("Expected an empty table to shrink back to %d buckets, got %d entries in %d buckets", minBuckets, table.count, len(table.buckets))
+	}
+}
+
+// scanAll scans the table to completion, calling between after each step
+func scanAll(table *hashTable[string, int], between func(step int)) map[string]int {
+	seen := make(map[string]int)
+	cursor := uint64(0)
+	for step := 0; ; step++ {
+		cursor, _ = table.scan(cursor, func(k string, _ int) { seen[k]++ })
+		if cursor == 0 {
+			return seen
+		}
+		between(step)
+	}
+}
+
+func TestHashTable_Scan(t *testing.T) {
+	table := newHashTable[string, int]()
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		table.set(key, testHash(key), i)
+	}
+
+	seen := scanAll(table, func(int) {})
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 keys, got %d", len(seen))
+	}
+	for key, n := range seen {
+		if n != 1 {
+			t.Errorf("Expected %s to be returned once without resizes, got %d", key, n)
+		}
+	}
+}
+
+func TestHashTable_ScanWhileGrowing(t *testing.T) {
+	table := newHashTable[string, int]()
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+		table.set(key, testHash(key), i)
+	}
+
+	// Add many keys early in the scan, forcing several resizes
+	next := 100
+	seen := scanAll(table, func(step int) {
+		if step >= 20 {
+			return
+		}
+		for j := 0; j < 50; j++ {
+			key := strconv.Itoa(next)
+			table.set(key, testHash(key), next)
+			next++
+		}
+	})
+
+	for i := 0; i < 100; i++ {
+		if seen[strconv.Itoa(i)] == 0 {
+			t.Errorf("Expected key %d, present for the whole scan, to be returned", i)
+		}
+	}
+}
+
+func TestHashTable_ScanWhileShrinking(t *testing.T) {
+	table := newHashTable[string, int]()
+	for i := 0; i < 2000; i++ {
+		key := strconv.Itoa(i)
+		table.set(key, testHash(key), i)
+	}
+
+	// Keep keys 0-49 and delete the rest during the scan, forcing shrinks
+	next := 50
+	seen := scanAll(table, func(int) {
+		for j := 0; j < 100 && next < 2000; j++ {
+			key := strconv.Itoa(next)
+			table.delete(key, testHash(key))
+			next++
+		}
+	})
+
+	for i := 0; i < 50; i++ {
+		if seen[strconv.Itoa(i)] == 0 {
+			t.Errorf("Expected key %d, present for the whole scan, to be returned", i)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package types
+
+import (
+	"math/rand/v2"
+)
+
+const (
+	// skipListMaxLevel bounds the number of levels of the skip list, which is
+	// enough for 2^64 elements with skipListP = 0.25
+	skipListMaxLevel = 32
+	// skipListP is the probability of a node having an additional level
+	skipListP = 0.25
+)
+
+// skipListNode is a node of the skip list backing a SortedSet
+type skipListNode struct {
+	member  string
+	score   float64
+	forward []*skipListNode
+}
+
+// ScoreRange describes a range of scores, each end inclusive unless marked exclusive
+type ScoreRange struct {
+	Min, Max                   float64
+	MinExclusive, MaxExclusive bool
+}
+
+// aboveMin reports whether score is not below the start of the range
+func (r ScoreRange) aboveMin(score float64) bool {
+	if r.MinExclusive {
+		return score > r.Min
+	}
+	return score >= r.Min
+}
+
+// belowMax reports whether score is not past the end of the range
+func (r ScoreRange) belowMax(score float64) bool {
+	if r.MaxExclusive {
+		return score < r.Max
+	}
+	return score <= r.Max
+}
+
+// SortedSet is a set of unique string members ordered by score, with ties
+// broken by comparing members lexicographically, like a Redis sorted set.
+// Members are indexed by a map for O(1) score lookups and by a skip list for
+// ordered traversal. It is not safe for concurrent use.
+type SortedSet struct {
+	dict   map[string]float64
+	header *skipListNode
+	level  int
+}
+
+// NewSortedSet creates an empty SortedSet
+func NewSortedSet() *SortedSet {
+	return &SortedSet{
+		dict:   make(map[string]float64),
+		header: &skipListNode{forward: make([]*skipListNode, skipListMaxLevel)},
+		level:  1,
+	}
+}
+
+// Len returns the number of members in the set
+func (z *SortedSet) Len() int {
+	return len(z.dict)
+}
+
+// Score returns the score of member and whether it is in the set
+func (z *SortedSet) Score(member string) (float64, bool) {
+	score, ok := z.dict[member]
+	return score, ok
+}
+
+// Add inserts member with the given score, or updates its score if it is
+// already present. It returns true if the member was newly added.
+func (z *SortedSet) Add(member string, score float64) bool {
+	if current, ok := z.dict[member]; ok {
+		if current != score {
+			z.delete(member, current)
+			z.insert(member, score)
+			z.dict[member] = score
+		}
+		return false
+	}
+
+	z.insert(member, score)
+	z.dict[member] = score
+	return true
+}
+
+// Remove deletes member from the set, reporting whether it was present
+func (z *SortedSet) Remove(member string) bool {
+	score, ok := z.dict[member]
+	if !ok {
+		return false
+	}
+	z.delete(member, score)
+	delete(z.dict, member)
+	return true
+}
+
+// Clone returns an independent copy of the set
+func (z *SortedSet) Clone() *SortedSet {
+	clone := NewSortedSet()
+	z.ForEach(func(member string, score float64) bool {
+		clone.Add(member, score)
+		return true
+	})
+	return clone
+}
+
+// ForEach calls fn for every member in ascending order until fn returns false
+func (z *SortedSet) ForEach(fn func(member string, score float64) bool) {
+	for node := z.header.forward[0]; node != nil; node = node.forward[0] {
+		if !fn(node.member, node.score) {
+			return
+		}
+	}
+}
+
+// RangeByScore calls fn in ascending order for every member whose score lies
+// within r, until fn returns false
+func (z *SortedSet) RangeByScore(r ScoreRange, fn func(member string, score float64) bool) {
+	// Find the last node before the range
+	node := z.header
+	for i := z.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && !r.aboveMin(node.forward[i].score) {
+			node = node.forward[i]
+		}
+	}
+
+	for node = node.forward[0]; node != nil && r.belowMax(node.score); node = node.forward[0] {
+		if !fn(node.member, node.score) {
+			return
+		}
+	}
+}
+
+// less reports whether the node orders before the given score and member
+func (n *skipListNode) less(score float64, member string) bool {
+	return n.score < score || (n.score == score && n.member < member)
+}
+
+// randomLevel returns a random level for a new node, where each additional
+// level is skipListP times as likely as the previous one
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Float64() < skipListP {
+		level++
+	}
+	return level
+}
+
+// insert adds a node for member, which must not already be in the skip list
+func (z *SortedSet) insert(member string, score float64) {
+	var update [skipListMaxLevel]*skipListNode
+
+	node := z.header
+	for i := z.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && node.forward[i].less(score, member) {
+			node = node.forward[i]
+		}
+		update[i] = node
+	}
+
+	level := randomLevel()
+	if level > z.level {
+		for i := z.level; i < level; i++ {
+			update[i] = z.header
+		}
+		z.level = level
+	}
+
+	newNode := &skipListNode{member: member, score: score, forward: make([]*skipListNode, level)}
+	for i := 0; i < level; i++ {
+		newNode.forward[i] = update[i].forward[i]
+		update[i].forward[i] = newNode
+	}
+}
+
+// delete removes the node for member with the given score from the skip list
+func (z *SortedSet) delete(member string, score float64) {
+	var update [skipListMaxLevel]*skipListNode
+
+	node := z.header
+	for i := z.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && node.forward[i].less(score, member) {
+			node = node.forward[i]
+		}
+		update[i] = node
+	}
+
+	node = node.forward[0]
+	if node == nil || node.score != score || node.member != member {
+		return
+	}
+
+	for i := 0; i < z.level; i++ {
+		if update[i].forward[i] == node {
+			update[i].forward[i] = node.forward[i]
+		}
+	}
+
+	for z.level > 1 && z.header.forward[z.level-1] == nil {
+		z.level--
+	}
+}
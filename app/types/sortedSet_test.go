@@ -0,0 +1,167 @@
+package types
+
+import (
+	"strconv"
+	"testing"
+)
+
+func collectRange(z *SortedSet, r ScoreRange) []string {
+	var members []string
+	z.RangeByScore(r, func(member string, score float64) bool {
+		members = append(members, member)
+		return true
+	})
+	return members
+}
+
+func TestSortedSet_AddAndScore(t *testing.T) {
+	z := NewSortedSet()
+
+	if !z.Add("a", 1) {
+		t.Error("Expected adding a new member to return true")
+	}
+	if z.Add("a", 5) {
+		t.Error("Expected updating an existing member to return false")
+	}
+
+	score, ok := z.Score("a")
+	if !ok || score != 5 {
+		t.Errorf("Expected (5, true), got (%v, %t)", score, ok)
+	}
+
+	if _, ok := z.Score("missing"); ok {
+		t.Error("Expected missing member to be reported as absent")
+	}
+
+	if z.Len() != 1 {
+		t.Errorf("Expected length 1, got %d", z.Len())
+	}
+}
+
+func TestSortedSet_Ordering(t *testing.T) {
+	z := NewSortedSet()
+	z.Add("c", 2)
+	z.Add("b", 2)
+	z.Add("a", 3)
+	z.Add("d", 1)
+
+	var members []string
+	z.ForEach(func(member string, score float64) bool {
+		members = append(members, member)
+		return true
+	})
+
+	// Equal scores are ordered by member
+	expected := []string{"d", "b", "c", "a"}
+	if len(members) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, members)
+	}
+	for i := range expected {
+		if members[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, members)
+		}
+	}
+}
+
+func TestSortedSet_Remove(t *testing.T) {
+	z := NewSortedSet()
+	z.Add("a", 1)
+	z.Add("b", 2)
+
+	if !z.Remove("a") {
+		t.Error("Expected removing an existing member to return true")
+	}
+	if z.Remove("a") {
+		t.Error("Expected removing a missing member to return false")
+	}
+
+	members := collectRange(z, ScoreRange{Min: 0, Max: 10})
+	if len(members) != 1 || members[0] != "b" {
+		t.Errorf("Expected [b], got %v", members)
+	}
+}
+
+func TestSortedSet_RangeByScore(t *testing.T) {
+	z := NewSortedSet()
+	for i := 0; i < 1000; i++ {
+		z.Add("m"+strconv.Itoa(i), float64(i))
+	}
+
+	tests := []struct {
+		name  string
+		r     ScoreRange
+		count int
+		first string
+	}{
+		{name: "inclusive", r: ScoreRange{Min: 10, Max: 20}, count: 11, first: "m10"},
+		{name: "exclusive max", r: ScoreRange{Min: 10, Max: 20, MaxExclusive: true}, count: 10, first: "m10"},
+		{name: "exclusive min", r: ScoreRange{Min: 10, Max: 20, MinExclusive: true}, count: 10, first: "m11"},
+		{name: "empty", r: ScoreRange{Min: 2000, Max: 3000}, count: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := collectRange(z, tt.r)
+			if len(members) != tt.count {
+				t.Fatalf("Expected %d members, got %d", tt.count, len(members))
+			}
+			if tt.count > 0 && members[0] != tt.first {
+				t.Errorf("Expected first member %s, got %s", tt.first, members[0])
+			}
+		})
+	}
+
+	// Iteration stops when the callback returns false
+	visited := 0
+	z.RangeByScore(ScoreRange{Min: 0, Max: 1000}, func(member string, score float64) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Errorf("Expected iteration to stop after 3 members, visited %d", visited)
+	}
+}
+
+func TestSortedSet_UpdateKeepsOrder(t *testing.T) {
+	z := NewSortedSet()
+	for i := 0; i < 100; i++ {
+		z.Add("m"+strconv.Itoa(i), float64(i))
+	}
+	for i := 0; i < 100; i += 2 {
+		z.Add("m"+strconv.Itoa(i), float64(1000-i))
+	}
+
+	previous := -1.0
+	count := 0
+	z.ForEach(func(member string, score float64) bool {
+		if score < previous {
+			t.Fatalf("Members out of order: %v after %v", score, previous)
+		}
+		previous = score
+		count++
+		return true
+	})
+	if count != 100 {
+		t.Errorf("Expected 100 members, got %d", count)
+	}
+}
+
+func TestSortedSet_Clone(t *testing.T) {
+	z := NewSortedSet()
+	z.Add("a", 1)
+	z.Add("b", 2)
+
+	clone := z.Clone()
+	clone.Add("c", 3)
+	clone.Remove("a")
+
+	if z.Len() != 2 || clone.Len() != 2 {
+		t.Fatalf("Expected both sets to have 2 members, got %d and %d", z.Len(), clone.Len())
+	}
+	if _, ok := z.Score("a"); !ok {
+		t.Errorf("Expected changes to the clone not to affect the original")
+	}
+	if got := collectRange(clone, ScoreRange{Min: 0, Max: 10}); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("Expected clone to contain [b c], got %v", got)
+	}
+}
@@ -3,19 +3,23 @@ package types
 import (
 	"fmt"
 	"hash/fnv"
+	"math/rand/v2"
+	"slices"
 	"sync"
 )
 
 const (
+	// shardBits is the number of hash bits used to select a shard.
+	shardBits = 5
 	// shardCount is the number of shards in the ThreadSafeMap.
 	// Using multiple shards reduces lock contention in concurrent access scenarios.
-	shardCount = 32
+	shardCount = 1 << shardBits
 )
 
 // shard represents a single shard of the ThreadSafeMap that contains a portion
 // of the key-value pairs and its own mutex for concurrent access control.
 type shard[K comparable, V any] struct {
-	data map[K]V
+	data *hashTable[K, V]
 	mu   sync.RWMutex
 }
 
@@ -28,57 +32,64 @@ type ThreadSafeMap[K comparable, V any] struct {
 }
 
 // NewThreadSafeMap creates and initializes a new ThreadSafeMap with the specified key and value types.
-// It initializes all shards with empty maps.
+// It initializes all shards with empty tables.
 func NewThreadSafeMap[K comparable, V any]() *ThreadSafeMap[K, V] {
 	mp := &ThreadSafeMap[K, V]{}
 	for i := range shardCount {
-		mp.shards[i] = &shard[K, V]{data: make(map[K]V)}
+		mp.shards[i] = &shard[K, V]{data: newHashTable[K, V]()}
 	}
 	return mp
 }
 
-// getShard determines which shard a key belongs to by hashing the key.
-// This is an internal method used for distributing keys across shards.
-func (mp *ThreadSafeMap[K, V]) getShard(key K) *shard[K, V] {
-	h := fnv.New32()
+// hashKey hashes a key. The low shardBits bits select the shard, and the
+// remaining bits are the hash used within the shard's table.
+func hashKey[K comparable](key K) (uint32, uint64) {
+	h := fnv.New64a()
 	_, _ = h.Write(fmt.Append(nil, key))
-	return mp.shards[h.Sum32()%shardCount]
+	sum := h.Sum64()
+	return uint32(sum % shardCount), sum >> shardBits
+}
+
+// getShard determines which shard a key belongs to by hashing the key, and
+// returns it with the key's hash within the shard.
+// This is an internal method used for distributing keys across shards.
+func (mp *ThreadSafeMap[K, V]) getShard(key K) (*shard[K, V], uint64) {
+	index, hash := hashKey(key)
+	return mp.shards[index], hash
 }
 
 // Set adds or updates a key-value pair in the map.
 // It is safe to call concurrently with other methods.
 func (mp *ThreadSafeMap[K, V]) Set(key K, value V) {
-	shard := mp.getShard(key)
+	shard, hash := mp.getShard(key)
 
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
 
-	shard.data[key] = value
+	shard.data.set(key, hash, value)
 }
 
 // Get retrieves a value by key from the map.
 // Returns the value and a boolean indicating whether the key was found.
 // It is safe to call concurrently with other methods.
 func (mp *ThreadSafeMap[K, V]) Get(key K) (V, bool) {
-	shard := mp.getShard(key)
+	shard, hash := mp.getShard(key)
 
 	shard.mu.RLock()
 	defer shard.mu.RUnlock()
 
-	value, ok := shard.data[key]
-
-	return value, ok
+	return shard.data.get(key, hash)
 }
 
 // Delete removes a key-value pair from the map.
 // It is safe to call concurrently with other methods.
 func (mp *ThreadSafeMap[K, V]) Delete(key K) {
-	shard := mp.getShard(key)
+	shard, hash := mp.getShard(key)
 
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
 
-	delete(shard.data, key)
+	shard.data.delete(key, hash)
 }
 
 // Len returns the total number of key-value pairs in the map across all shards.
@@ -87,7 +98,7 @@ func (mp *ThreadSafeMap[K, V]) Len() int {
 	length := 0
 	for _, shard := range mp.shards {
 		shard.mu.RLock()
-		length += len(shard.data)
+		length += shard.data.count
 		shard.mu.RUnlock()
 	}
 	return length
@@ -97,44 +108,52 @@ func (mp *ThreadSafeMap[K, V]) Len() int {
 func (mp *ThreadSafeMap[K, V]) Keys() []K {
 	keys := make([]K, 0, mp.Len())
 
-	for _, shard := range mp.shards {
-		shard.mu.RLock()
-		for k := range shard.data {
-			keys = append(keys, k)
-		}
-		shard.mu.RUnlock()
-	}
+	mp.ForEach(func(k K, _ V) {
+		keys = append(keys, k)
+	})
 
 	return keys
 }
 
 // Clear removes all key-value pairs from the map.
 func (mp *ThreadSafeMap[K, V]) Clear() {
+	mp.Reset()
+}
+
+// Reset atomically removes all key-value pairs from the map and returns a new
+// map holding the previous contents, so the caller can release them elsewhere.
+// All shards are locked together, so concurrent readers never observe a
+// partially cleared map.
+func (mp *ThreadSafeMap[K, V]) Reset() *ThreadSafeMap[K, V] {
 	for _, shard := range mp.shards {
 		shard.mu.Lock()
-		shard.data = make(map[K]V)
+	}
+
+	old := &ThreadSafeMap[K, V]{}
+	for i, s := range mp.shards {
+		old.shards[i] = &shard[K, V]{data: s.data}
+		s.data = newHashTable[K, V]()
+	}
+
+	for _, shard := range mp.shards {
 		shard.mu.Unlock()
 	}
+	return old
 }
 
 // Contains checks if the map contains the specified key.
 func (mp *ThreadSafeMap[K, V]) Contains(key K) bool {
-	shard := mp.getShard(key)
-
-	shard.mu.RLock()
-	defer shard.mu.RUnlock()
-
-	_, ok := shard.data[key]
+	_, ok := mp.Get(key)
 	return ok
 }
 
 // GetOrSet returns the existing value for the key if present.
 // Otherwise, it sets the provided value and returns it.
 func (mp *ThreadSafeMap[K, V]) GetOrSet(key K, value V) V {
-	shard := mp.getShard(key)
+	shard, hash := mp.getShard(key)
 
 	shard.mu.RLock()
-	existingValue, ok := shard.data[key]
+	existingValue, ok := shard.data.get(key, hash)
 	shard.mu.RUnlock()
 
 	if ok {
@@ -145,11 +164,11 @@ func (mp *ThreadSafeMap[K, V]) GetOrSet(key K, value V) V {
 	defer shard.mu.Unlock()
 
 	// Double-check the key doesn't exist after acquiring the write lock
-	if existingValue, ok := shard.data[key]; ok {
+	if existingValue, ok := shard.data.get(key, hash); ok {
 		return existingValue
 	}
 
-	shard.data[key] = value
+	shard.data.set(key, hash, value)
 	return value
 }
 
@@ -159,9 +178,225 @@ func (mp *ThreadSafeMap[K, V]) GetOrSet(key K, value V) V {
 func (mp *ThreadSafeMap[K, V]) ForEach(fn func(K, V)) {
 	for _, shard := range mp.shards {
 		shard.mu.RLock()
-		for k, v := range shard.data {
+		shard.data.forEach(func(k K, v V) bool {
 			fn(k, v)
+			return true
+		})
+		shard.mu.RUnlock()
+	}
+}
+
+// View calls fn with the value stored for key while holding the shard's read lock.
+// The boolean passed to fn indicates whether the key was found.
+// fn must not retain references to mutable parts of the value after returning.
+func (mp *ThreadSafeMap[K, V]) View(key K, fn func(V, bool)) {
+	shard, hash := mp.getShard(key)
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	fn(shard.data.get(key, hash))
+}
+
+// Compute atomically reads and updates the value stored for key.
+// fn receives the current value and whether it exists, and returns the new value
+// and whether it should be kept; returning false removes the key from the map.
+// It is safe to call concurrently with other methods.
+func (mp *ThreadSafeMap[K, V]) Compute(key K, fn func(V, bool) (V, bool)) {
+	shard, hash := mp.getShard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	value, ok := shard.data.get(key, hash)
+	newValue, keep := fn(value, ok)
+	if keep {
+		shard.data.set(key, hash, newValue)
+	} else if ok {
+		shard.data.delete(key, hash)
+	}
+}
+
+// LockedEntries gives access to the entries of a set of keys whose shards are
+// locked by Atomic. Only the keys passed to Atomic may be accessed.
+type LockedEntries[K comparable, V any] struct {
+	mp *ThreadSafeMap[K, V]
+}
+
+// Get returns the value stored for key and whether it exists.
+func (l LockedEntries[K, V]) Get(key K) (V, bool) {
+	shard, hash := l.mp.getShard(key)
+	return shard.data.get(key, hash)
+}
+
+// Set stores value for key.
+func (l LockedEntries[K, V]) Set(key K, value V) {
+	shard, hash := l.mp.getShard(key)
+	shard.data.set(key, hash, value)
+}
+
+// Delete removes key.
+func (l LockedEntries[K, V]) Delete(key K) {
+	shard, hash := l.mp.getShard(key)
+	shard.data.delete(key, hash)
+}
+
+// Atomic calls fn while holding the write locks of the shards of all the given
+// keys, so operations spanning several keys, such as renames, are atomic even
+// when the keys live in different shards. Shards are locked in index order so
+// concurrent calls cannot deadlock. fn must only access the given keys.
+func (mp *ThreadSafeMap[K, V]) Atomic(keys []K, fn func(entries LockedEntries[K, V])) {
+	indexes := make([]uint32, 0, len(keys))
+	for _, key := range keys {
+		index, _ := hashKey(key)
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	for _, i := range indexes {
+		mp.shards[i].mu.Lock()
+	}
+	defer func() {
+		for _, i := range slices.Backward(indexes) {
+			mp.shards[i].mu.Unlock()
 		}
+	}()
+
+	fn(LockedEntries[K, V]{mp: mp})
+}
+
+// RandomEntry returns a random entry accepted by the accept function, or false
+// if none was found. Shards are chosen with probability proportional to their
+// size, so the selection is approximately uniform. Entries rejected by accept,
+// such as expired values, are skipped.
+func (mp *ThreadSafeMap[K, V]) RandomEntry(accept func(K, V) bool) (K, V, bool) {
+	var sizes [shardCount]int
+	total := 0
+	for i, shard := range mp.shards {
+		shard.mu.RLock()
+		sizes[i] = shard.data.count
 		shard.mu.RUnlock()
+		total += sizes[i]
+	}
+
+	if total > 0 {
+		// Start from a shard picked by weight, then fall back to the others in
+		// order in case the chosen one has since been emptied
+		target := rand.IntN(total)
+		start := 0
+		for target >= sizes[start] {
+			target -= sizes[start]
+			start++
+		}
+
+		for offset := range shardCount {
+			shard := mp.shards[(start+offset)%shardCount]
+			shard.mu.RLock()
+			k, v, ok := shard.data.random(accept)
+			shard.mu.RUnlock()
+			if ok {
+				return k, v, true
+			}
+		}
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Scan iterates over the map with a stateless cursor, calling fn for every
+// entry of the buckets it visits. Iteration starts with a cursor of 0 and each
+// call returns the cursor to pass to the next one, or 0 once the whole map has
+// been visited. A call stops after visiting at least count entries, or after
+// count*10 empty buckets so sparse tables do not block for long.
+//
+// The low shardBits bits of the cursor are the shard index and the remaining
+// bits the position within that shard's table. Only one shard is read-locked
+// at a time, and every entry present for the whole iteration is visited at
+// least once even if the map is modified or resized between calls. fn is
+// called with the shard's read lock held and must not modify the map.
+func (mp *ThreadSafeMap[K, V]) Scan(cursor uint64, count int, fn func(K, V)) uint64 {
+	index := cursor % shardCount
+	position := cursor >> shardBits
+	visited := 0
+	emptyVisits := count * 10
+
+	for visited < count && emptyVisits > 0 {
+		shard := mp.shards[index]
+		shard.mu.RLock()
+		for visited < count && emptyVisits > 0 {
+			var n int
+			position, n = shard.data.scan(position, fn)
+			visited += n
+			if n == 0 {
+				emptyVisits--
+			}
+			if position == 0 {
+				break
+			}
+		}
+		shard.mu.RUnlock()
+
+		if position == 0 {
+			index++
+			if index == shardCount {
+				return 0
+			}
+		}
+	}
+
+	return position<<shardBits | index
+}
+
+// AtomicWith calls fn while holding the write locks of the shard of key in mp
+// and of the shard of otherKey in other, so operations spanning two maps, such
+// as moving a key between them, are atomic. mp's shard is locked first, so
+// callers must always pass a given pair of maps in the same order to avoid
+// deadlocks. fn must only access the given keys, through the entries of the
+// map each belongs to.
+func (mp *ThreadSafeMap[K, V]) AtomicWith(key K, other *ThreadSafeMap[K, V], otherKey K, fn func(entries, otherEntries LockedEntries[K, V])) {
+	if mp == other {
+		mp.Atomic([]K{key, otherKey}, func(entries LockedEntries[K, V]) {
+			fn(entries, entries)
+		})
+		return
+	}
+
+	first, _ := mp.getShard(key)
+	second, _ := other.getShard(otherKey)
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	fn(LockedEntries[K, V]{mp: mp}, LockedEntries[K, V]{mp: other})
+}
+
+// Swap atomically exchanges the contents of mp and other. All shards of mp are
+// locked before those of other, so callers must always pass a given pair of
+// maps in the same order to avoid deadlocks.
+func (mp *ThreadSafeMap[K, V]) Swap(other *ThreadSafeMap[K, V]) {
+	if mp == other {
+		return
+	}
+
+	for _, shard := range mp.shards {
+		shard.mu.Lock()
+	}
+	for _, shard := range other.shards {
+		shard.mu.Lock()
+	}
+
+	for i := range shardCount {
+		mp.shards[i].data, other.shards[i].data = other.shards[i].data, mp.shards[i].data
+	}
+
+	for _, shard := range other.shards {
+		shard.mu.Unlock()
+	}
+	for _, shard := range mp.shards {
+		shard.mu.Unlock()
 	}
 }
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strconv"
 	"sync"
 	"testing"
 )
@@ -204,6 +205,64 @@ func TestThreadSafeMap_ForEach(t *testing.T) {
 	}
 }
 
+func TestThreadSafeMap_View(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	m.Set("key1", 100)
+
+	var got int
+	var found bool
+	m.View("key1", func(v int, ok bool) {
+		got, found = v, ok
+	})
+	if !found || got != 100 {
+		t.Errorf("Expected (100, true), got (%d, %t)", got, found)
+	}
+
+	m.View("nonexistent", func(v int, ok bool) {
+		found = ok
+	})
+	if found {
+		t.Error("Expected nonexistent key to be reported as missing")
+	}
+}
+
+func TestThreadSafeMap_Compute(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+
+	// Insert a missing key
+	m.Compute("counter", func(v int, ok bool) (int, bool) {
+		if ok {
+			t.Error("Expected counter to be missing on first call")
+		}
+		return v + 1, true
+	})
+
+	// Update an existing key
+	m.Compute("counter", func(v int, ok bool) (int, bool) {
+		return v + 1, true
+	})
+
+	if val, _ := m.Get("counter"); val != 2 {
+		t.Errorf("Expected counter to be 2, got %d", val)
+	}
+
+	// Returning false removes the key
+	m.Compute("counter", func(v int, ok bool) (int, bool) {
+		return 0, false
+	})
+	if m.Contains("counter") {
+		t.Error("Expected counter to be removed")
+	}
+
+	// Returning false for a missing key leaves the map untouched
+	m.Compute("missing", func(v int, ok bool) (int, bool) {
+		return 0, false
+	})
+	if m.Len() != 0 {
+		t.Errorf("Expected empty map, got length %d", m.Len())
+	}
+}
+
 func TestThreadSafeMap_ConcurrentAccess(t *testing.T) {
 	m := NewThreadSafeMap[int, int]()
 	const numGoroutines = 100
@@ -259,3 +318,182 @@ func TestThreadSafeMap_ConcurrentAccess(t *testing.T) {
 		t.Error("Expected the map to contain elements after concurrent operations")
 	}
 }
+
+func TestThreadSafeMap_Reset(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	for i := 0; i < 100; i++ {
+		m.Set(strconv.Itoa(i), i)
+	}
+
+	old := m.Reset()
+	if m.Len() != 0 {
+		t.Errorf("Expected empty map after Reset, got %d entries", m.Len())
+	}
+
+	if old.Len() != 100 {
+		t.Errorf("Expected Reset to return 100 entries, got %d", old.Len())
+	}
+	if value, ok := old.Get("42"); !ok || value != 42 {
+		t.Errorf("Expected (42, true) from the returned map, got (%d, %t)", value, ok)
+	}
+}
+
+func TestThreadSafeMap_Atomic(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	m.Set("src", 1)
+
+	m.Atomic([]string{"src", "dst"}, func(entries LockedEntries[string, int]) {
+		value, ok := entries.Get("src")
+		if !ok {
+			t.Fatal("Expected src to exist")
+		}
+		entries.Set("dst", value)
+		entries.Delete("src")
+	})
+
+	if m.Contains("src") {
+		t.Error("Expected src to be removed")
+	}
+	if value, ok := m.Get("dst"); !ok || value != 1 {
+		t.Errorf("Expected (1, true) for dst, got (%d, %t)", value, ok)
+	}
+
+	// Concurrent moves between the same keys in opposite directions must not deadlock
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.Atomic([]string{"a", "b"}, func(entries LockedEntries[string, int]) { entries.Set("a", 1) })
+		}()
+		go func() {
+			defer wg.Done()
+			m.Atomic([]string{"b", "a"}, func(entries LockedEntries[string, int]) { entries.Set("b", 1) })
+		}()
+	}
+	wg.Wait()
+}
+
+func TestThreadSafeMap_AtomicWith(t *testing.T) {
+	a := NewThreadSafeMap[string, int]()
+	b := NewThreadSafeMap[string, int]()
+	a.Set("key", 1)
+
+	a.AtomicWith("key", b, "key", func(entries, otherEntries LockedEntries[string, int]) {
+		value, _ := entries.Get("key")
+		entries.Delete("key")
+		otherEntries.Set("key", value)
+	})
+	if a.Contains("key") {
+		t.Error("Expected key to be removed from the first map")
+	}
+	if value, ok := b.Get("key"); !ok || value != 1 {
+		t.Errorf("Expected (1, true) in the second map, got (%d, %t)", value, ok)
+	}
+
+	// Both keys in the same map
+	b.AtomicWith("key", b, "copy", func(entries, otherEntries LockedEntries[string, int]) {
+		value, _ := entries.Get("key")
+		otherEntries.Set("copy", value)
+	})
+	if value, ok := b.Get("copy"); !ok || value != 1 {
+		t.Errorf("Expected (1, true) for copy, got (%d, %t)", value, ok)
+	}
+}
+
+func TestThreadSafeMap_Swap(t *testing.T) {
+	a := NewThreadSafeMap[string, int]()
+	b := NewThreadSafeMap[string, int]()
+	for i := 0; i < 100; i++ {
+		a.Set(strconv.Itoa(i), i)
+	}
+	b.Set("only-b", 1)
+
+	a.Swap(b)
+	if a.Len() != 1 || !a.Contains("only-b") {
+		t.Errorf("Expected the first map to hold only-b, got %d entries", a.Len())
+	}
+	if b.Len() != 100 {
+		t.Errorf("Expected the second map to hold 100 entries, got %d", b.Len())
+	}
+	if value, ok := b.Get("42"); !ok || value != 42 {
+		t.Errorf("Expected (42, true), got (%d, %t)", value, ok)
+	}
+}
+
+func TestThreadSafeMap_RandomEntry(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	if _, _, ok := m.RandomEntry(func(string, int) bool { return true }); ok {
+		t.Error("Expected no entry from an empty map")
+	}
+
+	for i := 0; i < 100; i++ {
+		m.Set(strconv.Itoa(i), i)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		key, value, ok := m.RandomEntry(func(_ string, v int) bool { return v%2 == 0 })
+		if !ok || value%2 != 0 || key != strconv.Itoa(value) {
+			t.Fatalf("Expected an accepted entry, got (%q, %d, %t)", key, value, ok)
+		}
+		seen[key] = true
+	}
+	if len(seen) < 25 {
+		t.Errorf("Expected random entries to cover most of the map, saw %d distinct keys", len(seen))
+	}
+
+	if _, _, ok := m.RandomEntry(func(string, int) bool { return false }); ok {
+		t.Error("Expected no entry when every entry is rejected")
+	}
+}
+
+func TestThreadSafeMap_Scan(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	for i := 0; i < 1000; i++ {
+		m.Set(strconv.Itoa(i), i)
+	}
+
+	seen := make(map[string]bool)
+	cursor := uint64(0)
+	calls := 0
+	next := 1000
+	for {
+		cursor = m.Scan(cursor, 10, func(k string, _ int) { seen[k] = true })
+		calls++
+		if cursor == 0 {
+			break
+		}
+
+		// Modify the map between calls: add new keys and remove some of the
+		// keys added during the scan
+		m.Set(strconv.Itoa(next), next)
+		if next > 1000 {
+			m.Delete(strconv.Itoa(next - 1))
+		}
+		next++
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !seen[strconv.Itoa(i)] {
+			t.Errorf("Expected key %d to be returned", i)
+		}
+	}
+	if calls < 50 {
+		t.Errorf("Expected the scan to be split into many calls, got %d", calls)
+	}
+}
+
+func TestThreadSafeMap_ScanEmpty(t *testing.T) {
+	m := NewThreadSafeMap[string, int]()
+	cursor := uint64(0)
+	for i := 0; ; i++ {
+		cursor = m.Scan(cursor, 10, func(string, int) { t.Fatal("Expected no entries") })
+		if cursor == 0 {
+			break
+		}
+		if i > shardCount*minBuckets {
+			t.Fatal("Expected the scan of an empty map to terminate")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestACL tests ACL users and the enforcement of their permissions
+func TestACL(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16395) // Different port from other tests
+	defer ts.Close()
+
+	addr := fmt.Sprintf("localhost:%d", ts.Port)
+	defer ts.Client.Execute("ACL", "LOG", "RESET")
+	defer ts.Client.Execute("ACL", "DELUSER", "alice")
+
+	response, err := ts.Client.Execute("ACL", "SETUSER", "alice", "on", ">wonderland", "~cache:*", "%R~logs:*", "+@read", "+set", "-keys")
+	if err != nil || response != "OK" {
+		t.Fatalf("Failed to create user: %q (%v)", response, err)
+	}
+	ts.Client.Execute("SET", "logs:1", "entry")
+
+	alice, err := helpers.NewRedisClient(addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer alice.Close()
+	if response, err := alice.Execute("AUTH", "alice", "wonderland"); err != nil || response != "OK" {
+		t.Fatalf("Failed to authenticate: %q (%v)", response, err)
+	}
+
+	t.Run("WHOAMI", func(t *testing.T) {
+		if response, err := alice.Execute("ACL", "WHOAMI"); err == nil {
+			t.Errorf("Expected ACL WHOAMI to be denied to alice, got %q", response)
+		}
+		if response, err := ts.Client.Execute("ACL", "WHOAMI"); err != nil || response != "default" {
+			t.Errorf("Expected default, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		if response, err := alice.Execute("SET", "cache:1", "value"); err != nil || response != "OK" {
+			t.Errorf("Expected SET to be allowed, got %q (%v)", response, err)
+		}
+		if response, err := alice.Execute("GET", "logs:1"); err != nil || response != "entry" {
+			t.Errorf("Expected GET to be allowed, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := alice.Execute("DEL", "cache:1")
+		if err == nil || err.Error() != "redis error: NOPERM User alice has no permissions to run the 'del' command" {
+			t.Errorf("Expected DEL to be denied, got %v", err)
+		}
+		_, err = alice.Execute("KEYS", "*")
+		if err == nil || err.Error() != "redis error: NOPERM User alice has no permissions to run the 'keys' command" {
+			t.Errorf("Expected KEYS to be denied, got %v", err)
+		}
+		_, err = alice.Execute("SET", "logs:1", "overwritten")
+		if err == nil || err.Error() != "redis error: NOPERM No permissions to access a key" {
+			t.Errorf("Expected writing to a read-only key to be denied, got %v", err)
+		}
+		_, err = alice.Execute("GET", "secret")
+		if err == nil || err.Error() != "redis error: NOPERM No permissions to access a key" {
+			t.Errorf("Expected reading another key to be denied, got %v", err)
+		}
+	})
+
+	t.Run("ACL LOG", func(t *testing.T) {
+		response, err := ts.Client.Execute("ACL", "LOG", "1")
+		if err != nil {
+			t.Fatalf("Failed to execute ACL LOG: %v", err)
+		}
+		for _, field := range []string{"$3\r\nkey\r\n", "$6\r\nsecret\r\n", "$5\r\nalice\r\n"} {
+			if !strings.Contains(response, field) {
+				t.Errorf("Expected the latest entry to contain %q, got %q", field, response)
+			}
+		}
+	})
+
+	t.Run("DELUSER disconnects", func(t *testing.T) {
+		if response, err := ts.Client.Execute("ACL", "DELUSER", "alice"); err != nil || response != "1" {
+			t.Fatalf("Expected alice to be deleted, got %q (%v)", response, err)
+		}
+		if _, err := alice.Execute("GET", "cache:1"); err == nil {
+			t.Errorf("Expected alice's connection to be closed")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestAuth tests requirepass and authenticating with AUTH and HELLO
+func TestAuth(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16394) // Different port from other tests
+	defer ts.Close()
+
+	addr := fmt.Sprintf("localhost:%d", ts.Port)
+
+	if _, err := ts.Client.Execute("CONFIG", "SET", "requirepass", "s3cret"); err != nil {
+		t.Fatalf("Failed to set requirepass: %v", err)
+	}
+	defer ts.Client.Execute("CONFIG", "SET", "requirepass", "")
+
+	t.Run("existing connections stay authenticated", func(t *testing.T) {
+		if response, err := ts.Client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("NOAUTH", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		for _, args := range [][]string{{"PING"}, {"GET", "key"}, {"CONFIG", "GET", "requirepass"}} {
+			_, err := client.Execute(args[0], args[1:]...)
+			if err == nil || err.Error() != "redis error: NOAUTH Authentication required." {
+				t.Errorf("Expected NOAUTH for %v, got %v", args, err)
+			}
+		}
+
+		_, err = client.Execute("AUTH", "wrong")
+		if err == nil || err.Error() != "redis error: WRONGPASS invalid username-password pair or user is disabled." {
+			t.Errorf("Expected WRONGPASS, got %v", err)
+		}
+		if response, err := client.Execute("AUTH", "s3cret"); err != nil || response != "OK" {
+			t.Fatalf("Expected AUTH to succeed, got %q (%v)", response, err)
+		}
+		if response, err := client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG once authenticated, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("HELLO AUTH", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		_, err = client.Execute("HELLO", "3")
+		if err == nil || !strings.HasPrefix(err.Error(), "redis error: NOAUTH HELLO must be called with the client already authenticated") {
+			t.Errorf("Expected HELLO without AUTH to be refused, got %v", err)
+		}
+		response, err := client.Execute("HELLO", "3", "AUTH", "default", "s3cret")
+		if err != nil || !strings.Contains(response, "$5\r\nproto\r\n:3\r\n") {
+			t.Fatalf("Expected HELLO AUTH to succeed, got %q (%v)", response, err)
+		}
+		if response, err := client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG once authenticated, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("QUIT", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		if response, err := client.Execute("QUIT"); err != nil || response != "OK" {
+			t.Errorf("Expected QUIT to be allowed, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("unauthenticated request limits", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		if err := client.WriteRaw("*11\r\n"); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		_, err = client.ReadResponse()
+		if err == nil || err.Error() != "redis error: ERR Protocol error: unauthenticated multibulk length" {
+			t.Errorf("Expected unauthenticated multibulk length, got %v", err)
+		}
+		if _, err := client.ReadResponse(); err == nil {
+			t.Errorf("Expected the connection to be closed")
+		}
+	})
+}
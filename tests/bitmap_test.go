@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+)
+
+// TestBitmapCommands tests the SETBIT, GETBIT, BITCOUNT, BITPOS, BITOP and BITFIELD commands
+func TestBitmapCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16384) // Different port from other tests
+	defer ts.Close()
+
+	// Track daily active users as bits in a bitmap
+	t.Run("SETBIT, GETBIT and BITCOUNT", func(t *testing.T) {
+		for _, offset := range []string{"3", "7", "1000"} {
+			response, err := ts.Client.Execute("SETBIT", "dau:day1", offset, "1")
+			if err != nil {
+				t.Fatalf("Failed to execute SETBIT command: %v", err)
+			}
+			if response != "0" {
+				t.Errorf("Expected original bit 0, got %q", response)
+			}
+		}
+
+		response, err := ts.Client.Execute("GETBIT", "dau:day1", "1000")
+		if err != nil {
+			t.Fatalf("Failed to execute GETBIT command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("BITCOUNT", "dau:day1")
+		if err != nil {
+			t.Fatalf("Failed to execute BITCOUNT command: %v", err)
+		}
+		if response != "3" {
+			t.Errorf("Expected 3, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("BITCOUNT", "dau:day1", "0", "7", "BIT")
+		if err != nil {
+			t.Fatalf("Failed to execute BITCOUNT command with BIT range: %v", err)
+		}
+		if response != "2" {
+			t.Errorf("Expected 2, got %q", response)
+		}
+	})
+
+	t.Run("BITPOS", func(t *testing.T) {
+		response, err := ts.Client.Execute("BITPOS", "dau:day1", "1", "1")
+		if err != nil {
+			t.Fatalf("Failed to execute BITPOS command: %v", err)
+		}
+		if response != "1000" {
+			t.Errorf("Expected 1000, got %q", response)
+		}
+	})
+
+	t.Run("BITOP", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SETBIT", "dau:day2", "7", "1"); err != nil {
+			t.Fatalf("Failed to execute SETBIT command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("BITOP", "AND", "dau:both", "dau:day1", "dau:day2")
+		if err != nil {
+			t.Fatalf("Failed to execute BITOP command: %v", err)
+		}
+		if response != "126" {
+			t.Errorf("Expected destination length 126, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("BITCOUNT", "dau:both")
+		if err != nil {
+			t.Fatalf("Failed to execute BITCOUNT command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+	})
+
+	t.Run("BITFIELD", func(t *testing.T) {
+		response, err := ts.Client.Execute("BITFIELD", "counters", "INCRBY", "u4", "#0", "15", "OVERFLOW", "FAIL", "INCRBY", "u4", "#0", "1")
+		if err != nil {
+			t.Fatalf("Failed to execute BITFIELD command: %v", err)
+		}
+		expected := "*2\r\n:15\r\n$-1\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+
+		_, err = ts.Client.Execute("BITFIELD_RO", "counters", "INCRBY", "u4", "#0", "1")
+		if err == nil {
+			t.Error("Expected BITFIELD_RO to reject INCRBY")
+		}
+	})
+}
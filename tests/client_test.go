@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestClientCommands tests the CLIENT command family across connections
+func TestClientCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16392) // Different port from other tests
+	defer ts.Close()
+
+	other, err := helpers.NewRedisClient(fmt.Sprintf("localhost:%d", ts.Port))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer other.Close()
+
+	t.Run("CLIENT SETNAME and LIST", func(t *testing.T) {
+		if response, err := other.Execute("CLIENT", "SETNAME", "other"); err != nil || response != "OK" {
+			t.Fatalf("Failed to set the name: %q (%v)", response, err)
+		}
+
+		response, err := ts.Client.Execute("CLIENT", "LIST")
+		if err != nil {
+			t.Fatalf("Failed to execute CLIENT LIST: %v", err)
+		}
+		lines := strings.Split(strings.TrimSuffix(response, "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected two clients, got %q", response)
+		}
+		if !strings.Contains(lines[0], " laddr=127.0.0.1:16392 ") || !strings.Contains(lines[0], " cmd=client ") {
+			t.Errorf("Expected addresses and the last command, got %q", lines[0])
+		}
+		if !strings.Contains(lines[1], " name=other ") || !strings.Contains(lines[1], " cmd=client ") {
+			t.Errorf("Expected the other client's name, got %q", lines[1])
+		}
+	})
+
+	t.Run("CLIENT PAUSE WRITE", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CLIENT", "PAUSE", "200", "WRITE"); err != nil {
+			t.Fatalf("Failed to pause: %v", err)
+		}
+
+		start := time.Now()
+		if response, err := other.Execute("GET", "client:key"); err != nil || response != "" {
+			t.Fatalf("Expected GET to run during the pause, got %q (%v)", response, err)
+		}
+		if time.Since(start) > 100*time.Millisecond {
+			t.Errorf("Expected reads not to be paused")
+		}
+		if _, err := other.Execute("SET", "client:key", "v"); err != nil {
+			t.Fatalf("Failed to execute SET: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+			t.Errorf("Expected SET to wait for the pause, waited %v", elapsed)
+		}
+	})
+
+	t.Run("CLIENT REPLY", func(t *testing.T) {
+		if err := other.WriteRaw("CLIENT REPLY OFF\r\nPING\r\nCLIENT REPLY ON\r\n"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		if response, err := other.ReadResponse(); err != nil || response != "OK" {
+			t.Errorf("Expected only the reply to CLIENT REPLY ON, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("CLIENT KILL", func(t *testing.T) {
+		response, err := ts.Client.Execute("CLIENT", "KILL", "TYPE", "normal")
+		if err != nil || response != "1" {
+			t.Fatalf("Expected to kill the other client, got %q (%v)", response, err)
+		}
+		if _, err := other.Execute("PING"); err == nil {
+			t.Errorf("Expected the other connection to be closed")
+		}
+
+		response, err = ts.Client.Execute("CLIENT", "KILL", "127.0.0.1:1")
+		if err == nil || err.Error() != "redis error: ERR No such client" {
+			t.Errorf("Expected 'No such client', got %q (%v)", response, err)
+		}
+	})
+}
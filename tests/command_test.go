@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestCommand tests describing commands with COMMAND and checking the
+// number of arguments centrally
+func TestCommand(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16404) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("COUNT", func(t *testing.T) {
+		response, err := ts.Client.Execute("COMMAND", "COUNT")
+		if err != nil {
+			t.Fatalf("Failed to execute COMMAND COUNT: %v", err)
+		}
+		list, err := ts.Client.Execute("COMMAND", "LIST")
+		if err != nil {
+			t.Fatalf("Failed to execute COMMAND LIST: %v", err)
+		}
+		count, _ := strconv.Atoi(response)
+		listed, _ := strconv.Atoi(strings.SplitN(list, "\r\n", 2)[0][1:])
+		// LIST names subcommands too, so has more entries than COUNT
+		if count == 0 || listed <= count {
+			t.Errorf("Expected COUNT to count commands and LIST to add subcommands, got %d and %d", count, listed)
+		}
+	})
+
+	t.Run("INFO", func(t *testing.T) {
+		response, err := ts.Client.Execute("COMMAND", "INFO", "set", "nosuch")
+		if err != nil {
+			t.Fatalf("Failed to execute COMMAND INFO: %v", err)
+		}
+		if !strings.HasPrefix(response, "*2\r\n*10\r\n$3\r\nset\r\n:-3\r\n") || !strings.HasSuffix(response, "*-1\r\n") {
+			t.Errorf("Expected SET and a null entry, got %q", response)
+		}
+		if !strings.Contains(response, "+write\r\n+denyoom\r\n") || !strings.Contains(response, "+@write\r\n") {
+			t.Errorf("Expected SET to be a write command, got %q", response)
+		}
+	})
+
+	t.Run("GETKEYS", func(t *testing.T) {
+		response, err := ts.Client.Execute("COMMAND", "GETKEYS", "DEL", "a", "b")
+		if err != nil || response != "*2\r\n$1\r\na\r\n$1\r\nb\r\n" {
+			t.Errorf("Expected the keys a and b, got %q (%v)", response, err)
+		}
+		if _, err := ts.Client.Execute("COMMAND", "GETKEYS", "PING"); err == nil || err.Error() != "redis error: ERR The command has no key arguments" {
+			t.Errorf("Expected PING to have no keys, got %v", err)
+		}
+	})
+
+	t.Run("LIST FILTERBY", func(t *testing.T) {
+		response, err := ts.Client.Execute("COMMAND", "LIST", "FILTERBY", "PATTERN", "client|k*")
+		if err != nil || response != "*1\r\n$11\r\nclient|kill\r\n" {
+			t.Errorf("Expected client|kill, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("arity", func(t *testing.T) {
+		if _, err := ts.Client.Execute("GET"); err == nil || err.Error() != "redis error: ERR wrong number of arguments for 'get' command" {
+			t.Errorf("Expected an arity error, got %v", err)
+		}
+		if _, err := ts.Client.Execute("CLIENT", "ID", "extra"); err == nil || err.Error() != "redis error: ERR wrong number of arguments for 'client|id' command" {
+			t.Errorf("Expected an arity error for the subcommand, got %v", err)
+		}
+	})
+}
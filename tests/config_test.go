@@ -13,11 +13,20 @@ func TestConfigCommands(t *testing.T) {
 
 	// Test CONFIG SET and GET commands with RESP format
 	t.Run("CONFIG SET and GET Commands (RESP Format)", func(t *testing.T) {
-		key := "test-config-key"
-		value := "test-config-value"
+		key := "proto-max-bulk-len"
+		defer ts.Client.Execute("CONFIG", "SET", key, "512mb")
 
-		// Test CONFIG SET
-		setResponse, err := ts.Client.Execute("CONFIG", "SET", key, value)
+		// Test CONFIG GET for the default value
+		getResponse, err := ts.Client.Execute("CONFIG", "GET", key)
+		if err != nil {
+			t.Fatalf("Failed to execute CONFIG GET command: %v", err)
+		}
+		if !strings.Contains(getResponse, key) || !strings.Contains(getResponse, "536870912") {
+			t.Errorf("Expected response to contain key %q and its default, got %q", key, getResponse)
+		}
+
+		// Test CONFIG SET, memory values being shown in bytes
+		setResponse, err := ts.Client.Execute("CONFIG", "SET", key, "100mb")
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG SET command: %v", err)
 		}
@@ -25,91 +34,69 @@ func TestConfigCommands(t *testing.T) {
 			t.Errorf("Expected 'OK', got %q", setResponse)
 		}
 
-		// Test CONFIG GET for the key we just set
-		getResponse, err := ts.Client.Execute("CONFIG", "GET", key)
+		getResponse, err = ts.Client.Execute("CONFIG", "GET", key)
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG GET command: %v", err)
 		}
-
-		// Check that the response contains both the key and value
-		if !strings.Contains(getResponse, key) || !strings.Contains(getResponse, value) {
-			t.Errorf("Expected response to contain key %q and value %q, got %q", key, value, getResponse)
+		if !strings.Contains(getResponse, "104857600") {
+			t.Errorf("Expected response to contain key %q and value %q, got %q", key, "104857600", getResponse)
 		}
 
 		// Test CONFIG GET with wildcard pattern
-		// First set another related config
-		relatedKey := "test-config-related"
-		relatedValue := "related-value"
-		_, err = ts.Client.Execute("CONFIG", "SET", relatedKey, relatedValue)
-		if err != nil {
-			t.Fatalf("Failed to execute CONFIG SET command for related key: %v", err)
-		}
-
-		// Now get all test-config* keys
-		patternResponse, err := ts.Client.Execute("CONFIG", "GET", "test-config*")
+		patternResponse, err := ts.Client.Execute("CONFIG", "GET", "acl*")
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG GET command with pattern: %v", err)
 		}
-
-		// Check that both keys and values are in the response
-		if !strings.Contains(patternResponse, key) || !strings.Contains(patternResponse, value) ||
-			!strings.Contains(patternResponse, relatedKey) || !strings.Contains(patternResponse, relatedValue) {
-			t.Errorf("Expected pattern response to contain all keys and values, got %q", patternResponse)
+		if !strings.Contains(patternResponse, "aclfile") || !strings.Contains(patternResponse, "acl-log-max-len") {
+			t.Errorf("Expected pattern response to contain all matching parameters, got %q", patternResponse)
 		}
 
-		// Test CONFIG GET for non-existent key
+		// Test CONFIG GET for an undeclared parameter
 		emptyResponse, err := ts.Client.Execute("CONFIG", "GET", "nonexistent-config-key")
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG GET command for non-existent key: %v", err)
 		}
 
-		// For non-existent keys, should get an empty array
+		// For undeclared parameters, should get an empty array
 		if !strings.Contains(emptyResponse, "*0") {
 			t.Errorf("Expected empty array response for non-existent key, got %q", emptyResponse)
 		}
 
-		// Test setting multiple key-value pairs with a single CONFIG SET command
-		multi1Key := "multi-config-key1"
-		multi1Value := "multi-config-value1"
-		multi2Key := "multi-config-key2"
-		multi2Value := "multi-config-value2"
-
+		// Test setting multiple parameters with a single CONFIG SET command
+		defer ts.Client.Execute("CONFIG", "SET", "acl-log-max-len", "128")
 		multiSetResponse, err := ts.Client.Execute("CONFIG", "SET",
-			multi1Key, multi1Value,
-			multi2Key, multi2Value)
-
+			"acl-log-max-len", "64",
+			"PROTO-MAX-BULK-LEN", "1gb")
 		if err != nil {
-			t.Fatalf("Failed to execute CONFIG SET command with multiple key-value pairs: %v", err)
+			t.Fatalf("Failed to execute CONFIG SET command with multiple parameters: %v", err)
 		}
 		if multiSetResponse != "OK" {
-			t.Errorf("Expected 'OK' for multi-key CONFIG SET, got %q", multiSetResponse)
+			t.Errorf("Expected 'OK' for multi-parameter CONFIG SET, got %q", multiSetResponse)
 		}
 
-		// Verify both keys were set correctly
-		multi1GetResponse, err := ts.Client.Execute("CONFIG", "GET", multi1Key)
+		multiGetResponse, err := ts.Client.Execute("CONFIG", "GET", "acl-log-max-len")
 		if err != nil {
-			t.Fatalf("Failed to execute CONFIG GET for first multi key: %v", err)
+			t.Fatalf("Failed to execute CONFIG GET for acl-log-max-len: %v", err)
 		}
-		if !strings.Contains(multi1GetResponse, multi1Key) || !strings.Contains(multi1GetResponse, multi1Value) {
-			t.Errorf("Expected response to contain key %q and value %q, got %q", multi1Key, multi1Value, multi1GetResponse)
+		if !strings.Contains(multiGetResponse, "64") {
+			t.Errorf("Expected acl-log-max-len to be 64, got %q", multiGetResponse)
 		}
-
-		multi2GetResponse, err := ts.Client.Execute("CONFIG", "GET", multi2Key)
+		multiGetResponse, err = ts.Client.Execute("CONFIG", "GET", "proto-max-bulk-len")
 		if err != nil {
-			t.Fatalf("Failed to execute CONFIG GET for second multi key: %v", err)
+			t.Fatalf("Failed to execute CONFIG GET for proto-max-bulk-len: %v", err)
 		}
-		if !strings.Contains(multi2GetResponse, multi2Key) || !strings.Contains(multi2GetResponse, multi2Value) {
-			t.Errorf("Expected response to contain key %q and value %q, got %q", multi2Key, multi2Value, multi2GetResponse)
+		if !strings.Contains(multiGetResponse, "1073741824") {
+			t.Errorf("Expected proto-max-bulk-len to be 1073741824, got %q", multiGetResponse)
 		}
 	})
 
 	// Test CONFIG SET and CONFIG GET commands with inline format
 	t.Run("CONFIG SET and GET Commands (Inline Format)", func(t *testing.T) {
-		key := "inline-config-key"
-		value := "inline-config-value"
+		key := "hll-sparse-max-bytes"
+		defer ts.Client.ExecuteInline("CONFIG SET", key, "3000")
 
 		// Test CONFIG SET with inline format
-		setResponse, err := ts.Client.ExecuteInline("CONFIG SET", key, value)
+		setResponse, err := ts.Client.ExecuteInline("CONFIG SET", key, "2kb")
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG SET command with inline format: %v", err)
 		}
@@ -122,48 +109,81 @@ func TestConfigCommands(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG GET command with inline format: %v", err)
 		}
-
-		// Check that the response contains both the key and value
-		if !strings.Contains(getResponse, key) || !strings.Contains(getResponse, value) {
-			t.Errorf("Expected response to contain key %q and value %q, got %q", key, value, getResponse)
+		if !strings.Contains(getResponse, key) || !strings.Contains(getResponse, "2048") {
+			t.Errorf("Expected response to contain key %q and value %q, got %q", key, "2048", getResponse)
 		}
 
-		// Test CONFIG GET for non-existent key with inline format
+		// Test CONFIG GET for an undeclared parameter with inline format
 		emptyResponse, err := ts.Client.ExecuteInline("CONFIG GET", "nonexistent-inline-key")
 		if err != nil {
 			t.Fatalf("Failed to execute CONFIG GET command for non-existent key with inline format: %v", err)
 		}
-
-		// For non-existent keys, should get an empty array
 		if !strings.Contains(emptyResponse, "*0") {
 			t.Errorf("Expected empty array response for non-existent key, got %q", emptyResponse)
 		}
+	})
 
-		// Test setting multiple key-value pairs with inline format
-		multiKey1 := "inline-multi-key1"
-		multiValue1 := "inline-multi-value1"
-		multiKey2 := "inline-multi-key2"
-		multiValue2 := "inline-multi-value2"
-
-		multiSetResponse, err := ts.Client.ExecuteInline("CONFIG SET",
-			multiKey1, multiValue1,
-			multiKey2, multiValue2)
-
+	// Test CONFIG GET with several patterns
+	t.Run("CONFIG GET Several Patterns", func(t *testing.T) {
+		response, err := ts.Client.Execute("CONFIG", "GET", "maxclients", "timeout", "max*")
 		if err != nil {
-			t.Fatalf("Failed to execute CONFIG SET command with multiple key-value pairs in inline format: %v", err)
+			t.Fatalf("Failed to execute CONFIG GET command with several patterns: %v", err)
 		}
-		if multiSetResponse != "OK" {
-			t.Errorf("Expected 'OK' for multi-key CONFIG SET with inline format, got %q", multiSetResponse)
+		// maxclients matches two of the patterns but is listed once
+		if strings.Count(response, "maxclients") != 1 || !strings.Contains(response, "$7\r\ntimeout\r\n$1\r\n0\r\n") {
+			t.Errorf("Expected maxclients and timeout once each, got %q", response)
 		}
+	})
 
-		// Verify both keys were set correctly
-		multiGetResponse, err := ts.Client.ExecuteInline("CONFIG GET", "inline-multi-*")
-		if err != nil {
-			t.Fatalf("Failed to execute CONFIG GET for multi keys: %v", err)
-		}
-		if !strings.Contains(multiGetResponse, multiKey1) || !strings.Contains(multiGetResponse, multiValue1) ||
-			!strings.Contains(multiGetResponse, multiKey2) || !strings.Contains(multiGetResponse, multiValue2) {
-			t.Errorf("Expected response to contain both keys and values, got %q", multiGetResponse)
+	// Test the values CONFIG SET refuses
+	t.Run("CONFIG SET Refused Values", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     []string
+			expected string
+		}{
+			{
+				name:     "unknown parameter",
+				args:     []string{"proto-max-bulk-len", "1mb", "test-config-key", "value"},
+				expected: "ERR Unknown option or number of arguments for CONFIG SET - 'test-config-key'",
+			},
+			{
+				name:     "immutable parameter",
+				args:     []string{"proto-max-bulk-len", "1mb", "port", "7000"},
+				expected: "ERR CONFIG SET failed (possibly related to argument 'port') - can't set immutable config",
+			},
+			{
+				name:     "invalid boolean",
+				args:     []string{"proto-max-bulk-len", "1mb", "protected-mode", "maybe"},
+				expected: "ERR CONFIG SET failed (possibly related to argument 'protected-mode') - argument must be 'yes' or 'no'",
+			},
+			{
+				name:     "invalid enum",
+				args:     []string{"proto-max-bulk-len", "1mb", "tls-auth-clients", "maybe"},
+				expected: "ERR CONFIG SET failed (possibly related to argument 'tls-auth-clients') - argument(s) must be one of the following: no, yes, optional",
+			},
+			{
+				name:     "out of bounds",
+				args:     []string{"proto-max-bulk-len", "1mb", "maxclients", "0"},
+				expected: "ERR CONFIG SET failed (possibly related to argument 'maxclients') - argument must be between 1 and 2147483647 inclusive",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				response, err := ts.Client.Execute("CONFIG", append([]string{"SET"}, tt.args...)...)
+				if err == nil || err.Error() != "redis error: "+tt.expected {
+					t.Errorf("Expected %q, got %q (%v)", tt.expected, response, err)
+				}
+
+				// None of the parameters is set
+				getResponse, err := ts.Client.Execute("CONFIG", "GET", "proto-max-bulk-len")
+				if err != nil {
+					t.Fatalf("Failed to execute CONFIG GET command: %v", err)
+				}
+				if strings.Contains(getResponse, "1048576") {
+					t.Errorf("Expected proto-max-bulk-len to be unchanged, got %q", getResponse)
+				}
+			})
 		}
 	})
 
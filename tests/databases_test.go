@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestDatabaseCommands tests SELECT, MOVE, SWAPDB and INFO keyspace
+func TestDatabaseCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16389) // Different port from other tests
+	defer ts.Close()
+
+	if _, err := ts.Client.Execute("FLUSHALL"); err != nil {
+		t.Fatalf("Failed to execute FLUSHALL command: %v", err)
+	}
+
+	t.Run("SELECT is per connection", func(t *testing.T) {
+		other, err := helpers.NewRedisClient(fmt.Sprintf("localhost:%d", ts.Port))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer other.Close()
+
+		if _, err := ts.Client.Execute("SET", "db:key", "in db0"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+		if _, err := other.Execute("SELECT", "1"); err != nil {
+			t.Fatalf("Failed to execute SELECT command: %v", err)
+		}
+
+		response, err := other.Execute("GET", "db:key")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "" {
+			t.Errorf("Expected nil from database 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("GET", "db:key")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "in db0" {
+			t.Errorf("Expected 'in db0' from database 0, got %q", response)
+		}
+
+		_, err = other.Execute("SELECT", "16")
+		if err == nil || err.Error() != "redis error: ERR DB index is out of range" {
+			t.Errorf("Expected out of range error, got %v", err)
+		}
+	})
+
+	t.Run("MOVE and SWAPDB", func(t *testing.T) {
+		response, err := ts.Client.Execute("MOVE", "db:key", "1")
+		if err != nil {
+			t.Fatalf("Failed to execute MOVE command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		if _, err := ts.Client.Execute("SWAPDB", "0", "1"); err != nil {
+			t.Fatalf("Failed to execute SWAPDB command: %v", err)
+		}
+		response, err = ts.Client.Execute("GET", "db:key")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "in db0" {
+			t.Errorf("Expected the moved key back in database 0 after SWAPDB, got %q", response)
+		}
+	})
+
+	t.Run("INFO keyspace", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SELECT", "2"); err != nil {
+			t.Fatalf("Failed to execute SELECT command: %v", err)
+		}
+		if _, err := ts.Client.Execute("SET", "db:other", "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("INFO", "keyspace")
+		if err != nil {
+			t.Fatalf("Failed to execute INFO command: %v", err)
+		}
+		expected := "# Keyspace\r\ndb0:keys=1,expires=0,avg_ttl=0\r\ndb2:keys=1,expires=0,avg_ttl=0\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+	})
+
+	if _, err := ts.Client.Execute("FLUSHALL"); err != nil {
+		t.Fatalf("Failed to execute FLUSHALL command: %v", err)
+	}
+}
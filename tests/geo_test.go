@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestGeoCommands tests the GEO commands
+func TestGeoCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16386) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("GEOADD and GEODIST", func(t *testing.T) {
+		response, err := ts.Client.Execute("GEOADD", "Sicily", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOADD command: %v", err)
+		}
+		if response != "2" {
+			t.Errorf("Expected 2, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("GEODIST", "Sicily", "Palermo", "Catania", "km")
+		if err != nil {
+			t.Fatalf("Failed to execute GEODIST command: %v", err)
+		}
+		if response != "166.2742" {
+			t.Errorf("Expected '166.2742', got %q", response)
+		}
+	})
+
+	t.Run("GEOPOS and GEOHASH", func(t *testing.T) {
+		response, err := ts.Client.Execute("GEOPOS", "Sicily", "Palermo", "Unknown")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOPOS command: %v", err)
+		}
+		expected := "*2\r\n*2\r\n$20\r\n13.36138933897018433\r\n$20\r\n38.11555639549629859\r\n*-1\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+
+		response, err = ts.Client.Execute("GEOHASH", "Sicily", "Palermo", "Catania")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOHASH command: %v", err)
+		}
+		expected = "*2\r\n$11\r\nsqc8b49rny0\r\n$11\r\nsqdtr74hyu0\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+	})
+
+	t.Run("GEOSEARCH and GEOSEARCHSTORE", func(t *testing.T) {
+		response, err := ts.Client.Execute("GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "ASC", "WITHDIST")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOSEARCH command: %v", err)
+		}
+		expected := "*2\r\n*2\r\n$7\r\nCatania\r\n$7\r\n56.4413\r\n*2\r\n$7\r\nPalermo\r\n$8\r\n190.4424\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+
+		response, err = ts.Client.Execute("GEOSEARCHSTORE", "Sicily:near", "Sicily", "FROMMEMBER", "Catania", "BYBOX", "100", "100", "km")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOSEARCHSTORE command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+	})
+
+	t.Run("GEORADIUS", func(t *testing.T) {
+		response, err := ts.Client.Execute("GEORADIUS", "Sicily", "15", "37", "100", "km")
+		if err != nil {
+			t.Fatalf("Failed to execute GEORADIUS command: %v", err)
+		}
+		if response != "*1\r\n$7\r\nCatania\r\n" {
+			t.Errorf("Expected only Catania, got %q", response)
+		}
+	})
+
+	t.Run("RESP3 coordinates", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(fmt.Sprintf("localhost:%d", ts.Port))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if _, err := client.Execute("HELLO", "3"); err != nil {
+			t.Fatalf("Failed to execute HELLO command: %v", err)
+		}
+
+		response, err := client.Execute("GEOPOS", "Sicily", "Palermo", "Unknown")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOPOS command: %v", err)
+		}
+		expected := "*2\r\n*2\r\n,13.36138933897018433\r\n,38.11555639549629859\r\n_\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+
+		// Coordinates are doubles, while distances stay bulk strings as in Redis
+		response, err = client.Execute("GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "100", "km", "WITHDIST", "WITHCOORD")
+		if err != nil {
+			t.Fatalf("Failed to execute GEOSEARCH command: %v", err)
+		}
+		expected = "*1\r\n*3\r\n$7\r\nCatania\r\n$7\r\n56.4413\r\n*2\r\n,15.08726745843887329\r\n,37.50266842333162032\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+
+		response, err = client.Execute("GEORADIUS", "Sicily", "15", "37", "100", "km", "WITHCOORD")
+		if err != nil {
+			t.Fatalf("Failed to execute GEORADIUS command: %v", err)
+		}
+		expected = "*1\r\n*2\r\n$7\r\nCatania\r\n*2\r\n,15.08726745843887329\r\n,37.50266842333162032\r\n"
+		if response != expected {
+			t.Errorf("Expected %q, got %q", expected, response)
+		}
+	})
+
+	t.Run("GEO commands on a string", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SET", "plain", "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		_, err := ts.Client.Execute("GEOADD", "plain", "13", "38", "Palermo")
+		expected := "redis error: WRONGTYPE Operation against a key holding the wrong kind of value"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got %v", expected, err)
+		}
+
+		_, err = ts.Client.Execute("GET", "Sicily")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got %v", expected, err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHelloCommand tests negotiating RESP3 with HELLO
+func TestHelloCommand(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16390) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("HELLO 3", func(t *testing.T) {
+		response, err := ts.Client.Execute("HELLO", "3", "SETNAME", "resp3-client")
+		if err != nil {
+			t.Fatalf("Failed to execute HELLO command: %v", err)
+		}
+		if !strings.HasPrefix(response, "%7\r\n$6\r\nserver\r\n$5\r\nredis\r\n") || !strings.Contains(response, "$5\r\nproto\r\n:3\r\n") {
+			t.Errorf("Expected a RESP3 map announcing protocol 3, got %q", response)
+		}
+	})
+
+	t.Run("RESP3 replies", func(t *testing.T) {
+		response, err := ts.Client.Execute("GET", "hello:missing")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "" {
+			t.Errorf("Expected null, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("CONFIG", "GET", "hello:missing")
+		if err != nil {
+			t.Fatalf("Failed to execute CONFIG command: %v", err)
+		}
+		if response != "*0\r\n" {
+			t.Errorf("Expected an empty map, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("INFO", "keyspace")
+		if err != nil {
+			t.Fatalf("Failed to execute INFO command: %v", err)
+		}
+		if !strings.HasPrefix(response, "txt:# Keyspace\r\n") {
+			t.Errorf("Expected a verbatim text string, got %q", response)
+		}
+	})
+
+	t.Run("HELLO errors", func(t *testing.T) {
+		_, err := ts.Client.Execute("HELLO", "4")
+		if err == nil || err.Error() != "redis error: NOPROTO unsupported protocol version" {
+			t.Errorf("Expected NOPROTO error, got %v", err)
+		}
+
+		response, err := ts.Client.Execute("HELLO", "2")
+		if err != nil {
+			t.Fatalf("Failed to execute HELLO command: %v", err)
+		}
+		if !strings.HasPrefix(response, "*14\r\n") {
+			t.Errorf("Expected the RESP2 reply to be a flat array, got %q", response)
+		}
+	})
+}
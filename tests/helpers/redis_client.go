@@ -2,7 +2,9 @@ package helpers
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"strings"
@@ -27,6 +29,34 @@ func NewRedisClient(address string) (*RedisClient, error) {
 	}, nil
 }
 
+// NewUnixRedisClient creates a new Redis client connected to the Unix socket
+// at the specified path
+func NewUnixRedisClient(path string) (*RedisClient, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}, nil
+}
+
+// NewTLSRedisClient creates a new Redis client connected over TLS to the
+// specified address
+func NewTLSRedisClient(address string, config *tls.Config) (*RedisClient, error) {
+	conn, err := tls.Dial("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}, nil
+}
+
 // Close closes the client connection
 func (c *RedisClient) Close() error {
 	return c.conn.Close()
@@ -71,6 +101,20 @@ func (c *RedisClient) ExecuteInline(command string, args ...string) (string, err
 	return c.readResponse()
 }
 
+// WriteRaw sends data to the server exactly as given, for testing malformed
+// or pipelined requests
+func (c *RedisClient) WriteRaw(data string) error {
+	if _, err := c.conn.Write([]byte(data)); err != nil {
+		return fmt.Errorf("failed to send data: %w", err)
+	}
+	return nil
+}
+
+// ReadResponse reads the next response from the server
+func (c *RedisClient) ReadResponse() (string, error) {
+	return c.readResponse()
+}
+
 // readResponse reads and parses a Redis RESP protocol response
 func (c *RedisClient) readResponse() (string, error) {
 	// Read the first byte to determine the response type
@@ -97,10 +141,13 @@ func (c *RedisClient) readResponse() (string, error) {
 	case '-': // Error
 		return "", fmt.Errorf("redis error: %s", line[1:])
 
-	case ':': // Integer
+	case ':', ',', '#', '(': // Integer, and RESP3 double, boolean and big number
 		return line[1:], nil
 
-	case '$': // Bulk string
+	case '_': // RESP3 null
+		return "", nil
+
+	case '$', '=': // Bulk string, and RESP3 verbatim string including its format
 		length, err := strconv.Atoi(line[1:])
 		if err != nil {
 			return "", fmt.Errorf("invalid bulk string length: %w", err)
@@ -113,19 +160,23 @@ func (c *RedisClient) readResponse() (string, error) {
 
 		// Read the string content
 		data := make([]byte, length+2) // +2 for CRLF
-		_, err = c.reader.Read(data)
+		_, err = io.ReadFull(c.reader, data)
 		if err != nil {
 			return "", fmt.Errorf("failed to read bulk string data: %w", err)
 		}
 
 		return string(data[:length]), nil
 
-	case '*': // Array
+	case '*', '%', '~', '>': // Array, and RESP3 map, set and push
 		// Parse the array length
 		length, err := strconv.Atoi(line[1:])
 		if err != nil {
 			return "", fmt.Errorf("invalid array length: %w", err)
 		}
+		if respType == '%' {
+			// Maps are followed by a key and a value per entry
+			length *= 2
+		}
 
 		// If it's an empty array, return a specific format
 		if length == 0 {
@@ -138,40 +189,11 @@ func (c *RedisClient) readResponse() (string, error) {
 
 		// Read each array element
 		for i := 0; i < length; i++ {
-			// Read element type
-			elemType, err := c.reader.ReadByte()
+			element, err := c.readRawElement()
 			if err != nil {
-				return "", fmt.Errorf("failed to read array element type: %w", err)
-			}
-
-			c.reader.UnreadByte()
-
-			// Read the full element line
-			elementLine, err := c.reader.ReadString('\n')
-			if err != nil {
-				return "", fmt.Errorf("failed to read array element line: %w", err)
-			}
-
-			result += elementLine
-
-			// If it's a bulk string, read the data too
-			if elemType == '$' {
-				bulkLength, err := strconv.Atoi(strings.TrimRight(elementLine, "\r\n")[1:])
-				if err != nil {
-					return "", fmt.Errorf("invalid bulk string length in array: %w", err)
-				}
-
-				if bulkLength > -1 {
-					// Read the string content including CRLF
-					bulkData := make([]byte, bulkLength+2)
-					_, err = c.reader.Read(bulkData)
-					if err != nil {
-						return "", fmt.Errorf("failed to read bulk string data in array: %w", err)
-					}
-
-					result += string(bulkData)
-				}
+				return "", err
 			}
+			result += element
 		}
 
 		return result, nil
@@ -180,3 +202,51 @@ func (c *RedisClient) readResponse() (string, error) {
 		return "", fmt.Errorf("unknown response type: %c", respType)
 	}
 }
+
+// readRawElement reads a single array element and returns it in raw RESP
+// format, including the elements of nested arrays
+func (c *RedisClient) readRawElement() (string, error) {
+	// Read the full element line
+	elementLine, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read array element line: %w", err)
+	}
+	if len(elementLine) < 3 {
+		return "", fmt.Errorf("invalid array element line: %q", elementLine)
+	}
+
+	result := elementLine
+	length, err := strconv.Atoi(strings.TrimRight(elementLine, "\r\n")[1:])
+
+	switch elementLine[0] {
+	case '$', '=': // Bulk or verbatim string, read the data too
+		if err != nil {
+			return "", fmt.Errorf("invalid bulk string length in array: %w", err)
+		}
+		if length > -1 {
+			// Read the string content including CRLF
+			bulkData := make([]byte, length+2)
+			if _, err := io.ReadFull(c.reader, bulkData); err != nil {
+				return "", fmt.Errorf("failed to read bulk string data in array: %w", err)
+			}
+			result += string(bulkData)
+		}
+
+	case '*', '%', '~', '>': // Nested aggregate
+		if err != nil {
+			return "", fmt.Errorf("invalid nested array length: %w", err)
+		}
+		if elementLine[0] == '%' {
+			length *= 2
+		}
+		for i := 0; i < length; i++ {
+			element, err := c.readRawElement()
+			if err != nil {
+				return "", err
+			}
+			result += element
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestHyperLogLogCommands tests the PFADD, PFCOUNT and PFMERGE commands
+func TestHyperLogLogCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16385) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("PFADD and PFCOUNT", func(t *testing.T) {
+		response, err := ts.Client.Execute("PFADD", "visitors", "alice", "bob", "carol")
+		if err != nil {
+			t.Fatalf("Failed to execute PFADD command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("PFCOUNT", "visitors")
+		if err != nil {
+			t.Fatalf("Failed to execute PFCOUNT command: %v", err)
+		}
+		if response != "3" {
+			t.Errorf("Expected 3, got %q", response)
+		}
+	})
+
+	t.Run("PFMERGE and multi-key PFCOUNT", func(t *testing.T) {
+		if _, err := ts.Client.Execute("PFADD", "visitors:other", "carol", "dave"); err != nil {
+			t.Fatalf("Failed to execute PFADD command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("PFCOUNT", "visitors", "visitors:other")
+		if err != nil {
+			t.Fatalf("Failed to execute PFCOUNT command: %v", err)
+		}
+		if response != "4" {
+			t.Errorf("Expected 4, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("PFMERGE", "visitors:all", "visitors", "visitors:other")
+		if err != nil {
+			t.Fatalf("Failed to execute PFMERGE command: %v", err)
+		}
+		if response != "OK" {
+			t.Errorf("Expected 'OK', got %q", response)
+		}
+
+		response, err = ts.Client.Execute("PFCOUNT", "visitors:all")
+		if err != nil {
+			t.Fatalf("Failed to execute PFCOUNT command: %v", err)
+		}
+		if response != "4" {
+			t.Errorf("Expected 4, got %q", response)
+		}
+	})
+
+	t.Run("Values can be copied with GET and SET", func(t *testing.T) {
+		// Grow past the sparse limit so the dense encoding is exercised too
+		args := []string{"visitors:big"}
+		for i := 0; i < 5000; i++ {
+			args = append(args, "user:"+strconv.Itoa(i))
+		}
+		if _, err := ts.Client.Execute("PFADD", args...); err != nil {
+			t.Fatalf("Failed to execute PFADD command: %v", err)
+		}
+
+		raw, err := ts.Client.Execute("GET", "visitors:big")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if _, err := ts.Client.Execute("SET", "visitors:copy", raw); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		original, _ := ts.Client.Execute("PFCOUNT", "visitors:big")
+		copied, err := ts.Client.Execute("PFCOUNT", "visitors:copy")
+		if err != nil {
+			t.Fatalf("Failed to execute PFCOUNT command on copy: %v", err)
+		}
+		if original != copied {
+			t.Errorf("Expected copy to count %s, got %s", original, copied)
+		}
+	})
+
+	t.Run("PFADD on a non-HyperLogLog string", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SET", "plain", "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		_, err := ts.Client.Execute("PFADD", "plain", "x")
+		expected := "redis error: WRONGTYPE Key is not a valid HyperLogLog string value."
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got %v", expected, err)
+		}
+	})
+}
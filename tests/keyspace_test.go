@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"testing"
+)
+
+// TestKeyspaceCommands tests the keyspace management commands
+func TestKeyspaceCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16387) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("DEL, EXISTS and TYPE", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SET", "ks:a", "1"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+		if _, err := ts.Client.Execute("SET", "ks:b", "2"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("EXISTS", "ks:a", "ks:b", "ks:missing")
+		if err != nil {
+			t.Fatalf("Failed to execute EXISTS command: %v", err)
+		}
+		if response != "2" {
+			t.Errorf("Expected 2, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("TYPE", "ks:a")
+		if err != nil {
+			t.Fatalf("Failed to execute TYPE command: %v", err)
+		}
+		if response != "string" {
+			t.Errorf("Expected 'string', got %q", response)
+		}
+
+		response, err = ts.Client.Execute("DEL", "ks:a", "ks:missing")
+		if err != nil {
+			t.Fatalf("Failed to execute DEL command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("UNLINK", "ks:b")
+		if err != nil {
+			t.Fatalf("Failed to execute UNLINK command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+	})
+
+	t.Run("RENAME and COPY", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SET", "ks:src", "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("RENAME", "ks:src", "ks:dst")
+		if err != nil {
+			t.Fatalf("Failed to execute RENAME command: %v", err)
+		}
+		if response != "OK" {
+			t.Errorf("Expected 'OK', got %q", response)
+		}
+
+		response, err = ts.Client.Execute("COPY", "ks:dst", "ks:copy")
+		if err != nil {
+			t.Fatalf("Failed to execute COPY command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("GET", "ks:copy")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "value" {
+			t.Errorf("Expected 'value', got %q", response)
+		}
+
+		_, err = ts.Client.Execute("RENAME", "ks:src", "ks:dst")
+		expected := "redis error: ERR no such key"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got %v", expected, err)
+		}
+	})
+
+	t.Run("DBSIZE, RANDOMKEY and FLUSHALL", func(t *testing.T) {
+		if _, err := ts.Client.Execute("FLUSHALL"); err != nil {
+			t.Fatalf("Failed to execute FLUSHALL command: %v", err)
+		}
+		if _, err := ts.Client.Execute("SET", "ks:only", "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+
+		response, err := ts.Client.Execute("DBSIZE")
+		if err != nil {
+			t.Fatalf("Failed to execute DBSIZE command: %v", err)
+		}
+		if response != "1" {
+			t.Errorf("Expected 1, got %q", response)
+		}
+
+		response, err = ts.Client.Execute("RANDOMKEY")
+		if err != nil {
+			t.Fatalf("Failed to execute RANDOMKEY command: %v", err)
+		}
+		if response != "ks:only" {
+			t.Errorf("Expected 'ks:only', got %q", response)
+		}
+
+		response, err = ts.Client.Execute("FLUSHDB", "ASYNC")
+		if err != nil {
+			t.Fatalf("Failed to execute FLUSHDB command: %v", err)
+		}
+		if response != "OK" {
+			t.Errorf("Expected 'OK', got %q", response)
+		}
+
+		response, err = ts.Client.Execute("DBSIZE")
+		if err != nil {
+			t.Fatalf("Failed to execute DBSIZE command: %v", err)
+		}
+		if response != "0" {
+			t.Errorf("Expected 0, got %q", response)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestConnectionLimits tests maxclients, the idle client timeout and client
+// output buffer limits
+func TestConnectionLimits(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16393) // Different port from other tests
+	defer ts.Close()
+
+	addr := fmt.Sprintf("localhost:%d", ts.Port)
+
+	t.Run("maxclients", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "maxclients", "2"); err != nil {
+			t.Fatalf("Failed to set maxclients: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "maxclients", "10000")
+
+		second, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer second.Close()
+		if response, err := second.Execute("PING"); err != nil || response != "PONG" {
+			t.Fatalf("Expected the second client to be accepted, got %q (%v)", response, err)
+		}
+
+		third, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer third.Close()
+		_, err = third.ReadResponse()
+		if err == nil || err.Error() != "redis error: ERR max number of clients reached" {
+			t.Errorf("Expected the third client to be refused, got %v", err)
+		}
+		if _, err := third.ReadResponse(); err == nil {
+			t.Errorf("Expected the refused connection to be closed")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "timeout", "1"); err != nil {
+			t.Fatalf("Failed to set timeout: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "timeout", "0")
+
+		idle, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer idle.Close()
+
+		// Keep the test client busy while the other one idles
+		for range 8 {
+			if _, err := ts.Client.Execute("PING"); err != nil {
+				t.Fatalf("Expected the active client to stay connected: %v", err)
+			}
+			time.Sleep(250 * time.Millisecond)
+		}
+
+		if _, err := idle.Execute("PING"); err == nil {
+			t.Errorf("Expected the idle client to be closed")
+		}
+	})
+
+	t.Run("client-output-buffer-limit", func(t *testing.T) {
+		if _, err := ts.Client.Execute("SET", "large", strings.Repeat("x", 4096)); err != nil {
+			t.Fatalf("Failed to set key: %v", err)
+		}
+		if _, err := ts.Client.Execute("CONFIG", "SET", "client-output-buffer-limit", "normal 1kb 0 0"); err != nil {
+			t.Fatalf("Failed to set client-output-buffer-limit: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "client-output-buffer-limit", "normal 0 0 0 replica 256mb 64mb 60 pubsub 32mb 8mb 60")
+
+		// Every class is shown, with sizes in bytes
+		response, err := ts.Client.Execute("CONFIG", "GET", "client-output-buffer-limit")
+		expected := "*2\r\n$26\r\nclient-output-buffer-limit\r\n$72\r\nnormal 1024 0 0 replica 268435456 67108864 60 pubsub 33554432 8388608 60\r\n"
+		if err != nil || response != expected {
+			t.Errorf("Expected %q, got %q (%v)", expected, response, err)
+		}
+		if _, err := ts.Client.Execute("CONFIG", "SET", "client-output-buffer-limit", "pubsub 1mb 0 0"); err == nil {
+			t.Errorf("Expected the pubsub limit to be refused")
+		}
+
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		if response, err := client.Execute("GET", "missing"); err != nil || response != "" {
+			t.Fatalf("Expected small replies to be sent, got %q (%v)", response, err)
+		}
+		if response, err := client.Execute("GET", "large"); err == nil {
+			t.Errorf("Expected the client to be closed for exceeding the hard limit, got %d bytes", len(response))
+		}
+	})
+}
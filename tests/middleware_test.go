@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/command"
+	"github.com/dotslash21/redis-clone/app/resp"
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestMiddleware tests auditing commands with middleware added by an
+// application embedding the server
+func TestMiddleware(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16405) // Different port from other tests
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var audit []string
+	ts.Server.Registry().Use(func(next command.Handler) command.Handler {
+		return func(inv *command.Invocation) (resp.Reply, error) {
+			reply, err := next(inv)
+			mu.Lock()
+			defer mu.Unlock()
+			audit = append(audit, inv.FullName())
+			return reply, err
+		}
+	})
+
+	if _, err := ts.Client.Execute("SET", "audited", "1"); err != nil {
+		t.Fatalf("Failed to execute SET: %v", err)
+	}
+	if _, err := ts.Client.Execute("CLIENT", "ID"); err != nil {
+		t.Fatalf("Failed to execute CLIENT ID: %v", err)
+	}
+	// Commands rejected by the built-in checks reach the middleware too
+	ts.Client.Execute("GET")
+	ts.Client.Execute("NOSUCH")
+
+	if _, err := ts.Client.Execute("CONFIG", "SET", "requirepass", "s3cret"); err != nil {
+		t.Fatalf("Failed to set requirepass: %v", err)
+	}
+	defer ts.Client.Execute("CONFIG", "SET", "requirepass", "")
+	client, err := helpers.NewRedisClient(fmt.Sprintf("localhost:%d", ts.Port))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.Execute("GET", "audited"); err == nil || err.Error() != "redis error: NOAUTH Authentication required." {
+		t.Errorf("Expected NOAUTH, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if expected := []string{"set", "client|id", "get", "nosuch", "config|set", "get"}; !slices.Equal(audit, expected) {
+		t.Errorf("Expected %q to be audited, got %q", expected, audit)
+	}
+}
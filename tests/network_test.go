@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/server"
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// externalAddr returns an address of this host outside the loopback
+// interface, skipping the test if there is none
+func externalAddr(t *testing.T) string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Skipf("Failed to list interface addresses: %v", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil && !ipNet.IP.IsLoopback() {
+			return ipNet.IP.String()
+		}
+	}
+	t.Skip("No address outside the loopback interface")
+	return ""
+}
+
+// TestProtectedMode tests that clients outside the loopback interface are
+// refused while the default user has no password
+func TestProtectedMode(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16396) // Different port from other tests
+	defer ts.Close()
+
+	external := net.JoinHostPort(externalAddr(t), fmt.Sprint(ts.Port))
+
+	t.Run("refused without a password", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(external)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		_, err = client.ReadResponse()
+		if err == nil || !strings.HasPrefix(err.Error(), "redis error: DENIED Redis is running in protected mode") {
+			t.Errorf("Expected a DENIED error, got %v", err)
+		}
+		if _, err := client.ReadResponse(); err == nil {
+			t.Errorf("Expected the refused connection to be closed")
+		}
+	})
+
+	t.Run("loopback clients are accepted", func(t *testing.T) {
+		if response, err := ts.Client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("accepted with a password", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "requirepass", "s3cret"); err != nil {
+			t.Fatalf("Failed to set requirepass: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "requirepass", "")
+
+		client, err := helpers.NewRedisClient(external)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("AUTH", "s3cret"); err != nil || response != "OK" {
+			t.Errorf("Expected AUTH to succeed, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("accepted with protected-mode off", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "protected-mode", "no"); err != nil {
+			t.Fatalf("Failed to set protected-mode: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "protected-mode", "yes")
+
+		client, err := helpers.NewRedisClient(external)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+}
+
+// TestBind tests listening on the addresses of the bind setting only
+func TestBind(t *testing.T) {
+	port := 16397 // Different port from other tests
+
+	config.SetConfig("bind", "127.0.0.1 -::1")
+	defer config.SetConfig("bind", server.DefaultBind)
+
+	ts := NewTestSetup(t, port)
+	defer ts.Close()
+
+	t.Run("listens on the bind addresses", func(t *testing.T) {
+		if response, err := ts.Client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("doesn't listen elsewhere", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(net.JoinHostPort(externalAddr(t), fmt.Sprint(port)))
+		if err == nil {
+			client.Close()
+			t.Errorf("Expected the connection to be refused")
+		}
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		config.SetConfig("bind", "127.0.0.1 not-an-address.invalid")
+		defer config.SetConfig("bind", "127.0.0.1 -::1")
+
+		srv, err := server.NewServer(port + 1)
+		if err == nil {
+			srv.Shutdown()
+			t.Fatalf("Expected NewServer to fail")
+		}
+		// The listeners created before the failure are closed
+		listener, err := net.Listen("tcp4", fmt.Sprintf("127.0.0.1:%d", port+1))
+		if err != nil {
+			t.Fatalf("Expected the port to be released: %v", err)
+		}
+		listener.Close()
+	})
+
+	t.Run("optional addresses", func(t *testing.T) {
+		// 192.0.2.0/24 is reserved for documentation, so never assigned
+		config.SetConfig("bind", "-192.0.2.123 127.0.0.1")
+		defer config.SetConfig("bind", "127.0.0.1 -::1")
+
+		srv, err := server.NewServer(port + 1)
+		if err != nil {
+			t.Fatalf("Expected an unavailable optional address to be skipped: %v", err)
+		}
+		srv.Shutdown()
+
+		config.SetConfig("bind", "192.0.2.123 127.0.0.1")
+		if srv, err := server.NewServer(port + 1); err == nil {
+			srv.Shutdown()
+			t.Errorf("Expected an unavailable address to fail")
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestProtocol tests request parsing, pipelining and protocol errors
+func TestProtocol(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16391) // Different port from other tests
+	defer ts.Close()
+
+	addr := fmt.Sprintf("localhost:%d", ts.Port)
+
+	t.Run("Pipelined requests", func(t *testing.T) {
+		err := ts.Client.WriteRaw("*3\r\n$3\r\nSET\r\n$8\r\nproto:k1\r\n$2\r\nv1\r\nPING\r\n*2\r\n$3\r\nGET\r\n$8\r\nproto:k1\r\n")
+		if err != nil {
+			t.Fatalf("Failed to send pipeline: %v", err)
+		}
+		for _, expected := range []string{"OK", "PONG", "v1"} {
+			response, err := ts.Client.ReadResponse()
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+			if response != expected {
+				t.Errorf("Expected %q, got %q", expected, response)
+			}
+		}
+	})
+
+	t.Run("Large pipeline", func(t *testing.T) {
+		const count = 10000
+		var pipeline strings.Builder
+		for i := range count {
+			key := fmt.Sprintf("proto:pipe:%d", i)
+			fmt.Fprintf(&pipeline, "*3\r\n$3\r\nSET\r\n$%d\r\n%s\r\n$%d\r\n%d\r\n", len(key), key, len(strconv.Itoa(i)), i)
+		}
+
+		// Write from another goroutine so the server can't block on unread replies
+		errCh := make(chan error, 1)
+		go func() { errCh <- ts.Client.WriteRaw(pipeline.String()) }()
+
+		for i := range count {
+			response, err := ts.Client.ReadResponse()
+			if err != nil {
+				t.Fatalf("Failed to read response %d: %v", i, err)
+			}
+			if response != "OK" {
+				t.Fatalf("Expected 'OK' for command %d, got %q", i, response)
+			}
+		}
+		if err := <-errCh; err != nil {
+			t.Fatalf("Failed to send pipeline: %v", err)
+		}
+
+		response, err := ts.Client.Execute("GET", fmt.Sprintf("proto:pipe:%d", count-1))
+		if err != nil || response != strconv.Itoa(count-1) {
+			t.Errorf("Expected %d, got %q (%v)", count-1, response, err)
+		}
+	})
+
+	t.Run("Replies are flushed before a partial command", func(t *testing.T) {
+		// The reply to PING must not wait for the rest of the ECHO command
+		if err := ts.Client.WriteRaw("PING\r\n*2\r\n$4\r\nECHO\r\n$5\r\nhel"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		response, err := ts.Client.ReadResponse()
+		if err != nil || response != "PONG" {
+			t.Fatalf("Expected 'PONG', got %q (%v)", response, err)
+		}
+
+		if err := ts.Client.WriteRaw("lo\r\n"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		response, err = ts.Client.ReadResponse()
+		if err != nil || response != "hello" {
+			t.Errorf("Expected 'hello', got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("Quoted inline commands", func(t *testing.T) {
+		if err := ts.Client.WriteRaw("SET proto:inline \"hello world\\x21\"\r\n"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		response, err := ts.Client.ReadResponse()
+		if err != nil || response != "OK" {
+			t.Fatalf("Expected 'OK', got %q (%v)", response, err)
+		}
+
+		response, err = ts.Client.ExecuteInline("GET", "'proto:inline'")
+		if err != nil {
+			t.Fatalf("Failed to execute GET command: %v", err)
+		}
+		if response != "hello world!" {
+			t.Errorf("Expected 'hello world!', got %q", response)
+		}
+	})
+
+	t.Run("QUIT closes the connection", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+
+		// Replies pipelined before QUIT are still delivered
+		if err := client.WriteRaw("PING\r\nQUIT\r\nPING\r\n"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		for _, expected := range []string{"PONG", "OK"} {
+			response, err := client.ReadResponse()
+			if err != nil || response != expected {
+				t.Fatalf("Expected %q, got %q (%v)", expected, response, err)
+			}
+		}
+		if _, err := client.ReadResponse(); err == nil {
+			t.Errorf("Expected the connection to be closed")
+		}
+	})
+
+	t.Run("proto-max-bulk-len applies to open connections", func(t *testing.T) {
+		client, err := helpers.NewRedisClient(addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("PING"); err != nil || response != "PONG" {
+			t.Fatalf("Expected PONG, got %q (%v)", response, err)
+		}
+
+		if _, err := ts.Client.Execute("CONFIG", "SET", "proto-max-bulk-len", "1mb"); err != nil {
+			t.Fatalf("Failed to set proto-max-bulk-len: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "proto-max-bulk-len", strconv.Itoa(512*1024*1024))
+
+		if err := client.WriteRaw("*1\r\n$1048577\r\n"); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		expected := "redis error: ERR Protocol error: invalid bulk length"
+		if _, err := client.ReadResponse(); err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got %v", expected, err)
+		}
+	})
+
+	protocolErrors := []struct {
+		name     string
+		request  string
+		expected string
+	}{
+		{"huge multibulk length", "*2147483648\r\n", "redis error: ERR Protocol error: invalid multibulk length"},
+		{"invalid bulk length", "*1\r\n$abc\r\n", "redis error: ERR Protocol error: invalid bulk length"},
+		{"bulk length over proto-max-bulk-len", "*1\r\n$536870913\r\n", "redis error: ERR Protocol error: invalid bulk length"},
+		{"missing bulk prefix", "*1\r\n:1\r\n", "redis error: ERR Protocol error: expected '$', got ':'"},
+		{"unbalanced quotes", "SET k \"v\r\n", "redis error: ERR Protocol error: unbalanced quotes in request"},
+	}
+
+	for _, tt := range protocolErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := helpers.NewRedisClient(addr)
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer client.Close()
+
+			if err := client.WriteRaw(tt.request); err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			_, err = client.ReadResponse()
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error %q, got %v", tt.expected, err)
+			}
+
+			// The server closes the connection after a protocol error
+			if _, err := client.ReadResponse(); err == nil {
+				t.Errorf("Expected the connection to be closed")
+			}
+		})
+	}
+}
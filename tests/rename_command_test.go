@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/server"
+)
+
+// TestRenameCommand tests renaming and disabling commands with
+// rename-command
+func TestRenameCommand(t *testing.T) {
+	config.RenameCommand("FLUSHALL", "")
+	config.RenameCommand("CONFIG", "SECRET-CONFIG")
+	defer config.RenameCommand("FLUSHALL", "FLUSHALL")
+	defer config.RenameCommand("CONFIG", "CONFIG")
+
+	// Setup test environment
+	ts := NewTestSetup(t, 16406) // Different port from other tests
+	defer ts.Close()
+
+	t.Run("disabled", func(t *testing.T) {
+		if _, err := ts.Client.Execute("FLUSHALL"); err == nil {
+			t.Errorf("Expected FLUSHALL to be disabled")
+		}
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "GET", "maxclients"); err == nil {
+			t.Errorf("Expected CONFIG not to be found under its old name")
+		}
+		if response, err := ts.Client.Execute("SECRET-CONFIG", "GET", "nosuch"); err != nil || response != "*0\r\n" {
+			t.Errorf("Expected CONFIG to run under its new name, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("invisible to COMMAND", func(t *testing.T) {
+		response, err := ts.Client.Execute("COMMAND", "INFO", "flushall", "config")
+		if err != nil || response != "*2\r\n*-1\r\n*-1\r\n" {
+			t.Errorf("Expected FLUSHALL and CONFIG to be unknown, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("invisible to ACL rules", func(t *testing.T) {
+		if _, err := ts.Client.Execute("ACL", "SETUSER", "renamer", "+flushall"); err == nil {
+			ts.Client.Execute("ACL", "DELUSER", "renamer")
+			t.Errorf("Expected ACL rules not to accept the disabled FLUSHALL")
+		}
+	})
+
+	t.Run("rename to an existing command", func(t *testing.T) {
+		config.RenameCommand("ECHO", "PING")
+		defer config.RenameCommand("ECHO", "ECHO")
+
+		srv, err := server.NewServer(16407)
+		if err == nil {
+			srv.Shutdown()
+			t.Fatalf("Expected NewServer to fail renaming ECHO to PING")
+		}
+		if !strings.Contains(err.Error(), "ECHO") || !strings.Contains(err.Error(), "PING") {
+			t.Errorf("Expected the error to name the conflicting commands, got %v", err)
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		config.RenameCommand("NOSUCH", "")
+		defer config.RenameCommand("NOSUCH", "NOSUCH")
+
+		if srv, err := server.NewServer(16407); err == nil {
+			srv.Shutdown()
+			t.Errorf("Expected NewServer to fail renaming an unknown command")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestScanCommands tests iterating over the keyspace with SCAN and KEYS
+func TestScanCommands(t *testing.T) {
+	// Setup test environment
+	ts := NewTestSetup(t, 16388) // Different port from other tests
+	defer ts.Close()
+
+	for i := 0; i < 50; i++ {
+		if _, err := ts.Client.Execute("SET", "scan:"+strconv.Itoa(i), "value"); err != nil {
+			t.Fatalf("Failed to execute SET command: %v", err)
+		}
+	}
+
+	t.Run("SCAN", func(t *testing.T) {
+		seen := make(map[string]bool)
+		cursor := "0"
+		for {
+			response, err := ts.Client.Execute("SCAN", cursor, "MATCH", "scan:*", "COUNT", "5")
+			if err != nil {
+				t.Fatalf("Failed to execute SCAN command: %v", err)
+			}
+
+			// The reply is the next cursor followed by an array of keys
+			lines := strings.Split(strings.TrimSuffix(response, "\r\n"), "\r\n")
+			cursor = lines[2]
+			for i := 5; i < len(lines); i += 2 {
+				seen[lines[i]] = true
+			}
+			if cursor == "0" {
+				break
+			}
+		}
+
+		if len(seen) != 50 {
+			t.Errorf("Expected SCAN to return 50 keys, got %d", len(seen))
+		}
+	})
+
+	t.Run("SCAN with huge COUNT", func(t *testing.T) {
+		response, err := ts.Client.Execute("SCAN", "0", "MATCH", "scan:*", "COUNT", "2147483647")
+		if err != nil {
+			t.Fatalf("Failed to execute SCAN command: %v", err)
+		}
+
+		// A single call visits the whole keyspace without preallocating COUNT slots
+		lines := strings.Split(strings.TrimSuffix(response, "\r\n"), "\r\n")
+		if lines[2] != "0" {
+			t.Errorf("Expected SCAN to complete in one call, got cursor %s", lines[2])
+		}
+		if n := (len(lines) - 4) / 2; n != 50 {
+			t.Errorf("Expected SCAN to return 50 keys, got %d", n)
+		}
+	})
+
+	t.Run("KEYS", func(t *testing.T) {
+		response, err := ts.Client.Execute("KEYS", "scan:4?")
+		if err != nil {
+			t.Fatalf("Failed to execute KEYS command: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(response, "\r\n"), "\r\n")
+		var keys []string
+		for i := 2; i < len(lines); i += 2 {
+			keys = append(keys, lines[i])
+		}
+		slices.Sort(keys)
+		expected := []string{"scan:40", "scan:41", "scan:42", "scan:43", "scan:44", "scan:45", "scan:46", "scan:47", "scan:48", "scan:49"}
+		if !slices.Equal(keys, expected) {
+			t.Errorf("Expected %v, got %v", expected, keys)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := ts.Client.Execute("SCAN", "abc")
+		if err == nil || err.Error() != "redis error: ERR invalid cursor" {
+			t.Errorf("Expected invalid cursor error, got %v", err)
+		}
+	})
+}
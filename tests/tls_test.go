@@ -0,0 +1,260 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/app/server"
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// testCA signs self-signed certificates for tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+// newTestCA creates a CA and writes its certificate to ca.crt
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, ca.path("ca.crt"), "CERTIFICATE", der)
+	return ca
+}
+
+// path returns the path of a file in the CA's directory
+func (ca *testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// pool returns a certificate pool trusting the CA
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// issue creates a certificate for cn signed by the CA, valid for localhost,
+// and writes it and its key to name.crt and name.key
+func (ca *testCA) issue(t *testing.T, name, cn string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	writePEM(t, ca.path(name+".crt"), "CERTIFICATE", der)
+	writePEM(t, ca.path(name+".key"), "EC PRIVATE KEY", keyDER)
+
+	cert, err := tls.LoadX509KeyPair(ca.path(name+".crt"), ca.path(name+".key"))
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	return cert
+}
+
+// writePEM writes a PEM block to path
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// TestTLS tests serving TLS connections alongside plain ones, client
+// certificates and reloading certificates
+func TestTLS(t *testing.T) {
+	ca := newTestCA(t)
+	ca.issue(t, "server", "server")
+	aliceCert := ca.issue(t, "alice", "alice")
+	strangerCert := ca.issue(t, "stranger", "stranger")
+
+	tlsAddr := "localhost:16400"
+	// The certificate and its key are applied together
+	config.SetConfig("tls-port", "16400")
+	defer config.SetConfig("tls-port", "0")
+	if err := config.Update([]config.Setting{
+		{Name: "tls-cert-file", Value: ca.path("server.crt")},
+		{Name: "tls-key-file", Value: ca.path("server.key")},
+		{Name: "tls-ca-cert-file", Value: ca.path("ca.crt")},
+		{Name: "tls-auth-clients", Value: "yes"},
+	}); err != nil {
+		t.Fatalf("Failed to set the certificates: %v", err)
+	}
+	defer config.Update([]config.Setting{{Name: "tls-cert-file", Value: ""}, {Name: "tls-key-file", Value: ""}, {Name: "tls-ca-cert-file", Value: ""}})
+
+	// Setup test environment
+	ts := NewTestSetup(t, 16399) // Different port from other tests
+	defer ts.Close()
+
+	if _, err := ts.Client.Execute("ACL", "SETUSER", "alice", "on", "nopass", "+@all", "~*"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	defer ts.Client.Execute("ACL", "DELUSER", "alice")
+
+	clientConfig := func(certs ...tls.Certificate) *tls.Config {
+		return &tls.Config{RootCAs: ca.pool(), Certificates: certs, ServerName: "localhost"}
+	}
+
+	t.Run("plain connections", func(t *testing.T) {
+		if response, err := ts.Client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("certificate authenticates as its user", func(t *testing.T) {
+		client, err := helpers.NewTLSRedisClient(tlsAddr, clientConfig(aliceCert))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("ACL", "WHOAMI"); err != nil || response != "alice" {
+			t.Errorf("Expected alice, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("certificate of an unknown user", func(t *testing.T) {
+		client, err := helpers.NewTLSRedisClient(tlsAddr, clientConfig(strangerCert))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("ACL", "WHOAMI"); err != nil || response != "default" {
+			t.Errorf("Expected default, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("client certificate required", func(t *testing.T) {
+		client, err := helpers.NewTLSRedisClient(tlsAddr, clientConfig())
+		if err == nil {
+			defer client.Close()
+			// With TLS 1.3 the server's rejection arrives after the handshake
+			if _, err := client.Execute("PING"); err == nil {
+				t.Errorf("Expected the connection without a certificate to be refused")
+			}
+		}
+	})
+
+	t.Run("client certificate optional", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "tls-auth-clients", "optional"); err != nil {
+			t.Fatalf("Failed to set tls-auth-clients: %v", err)
+		}
+		defer ts.Client.Execute("CONFIG", "SET", "tls-auth-clients", "yes")
+
+		client, err := helpers.NewTLSRedisClient(tlsAddr, clientConfig())
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer client.Close()
+		if response, err := client.Execute("PING"); err != nil || response != "PONG" {
+			t.Errorf("Expected PONG, got %q (%v)", response, err)
+		}
+	})
+
+	serverCN := func(t *testing.T) string {
+		conn, err := tls.Dial("tcp", tlsAddr, clientConfig(aliceCert))
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	t.Run("certificates are reloaded", func(t *testing.T) {
+		ca.issue(t, "reloaded", "reloaded")
+		if _, err := ts.Client.Execute("CONFIG", "SET", "tls-cert-file", ca.path("reloaded.crt"), "tls-key-file", ca.path("reloaded.key")); err != nil {
+			t.Fatalf("Failed to set the certificate: %v", err)
+		}
+		if cn := serverCN(t); cn != "reloaded" {
+			t.Errorf("Expected the reloaded certificate, got %s", cn)
+		}
+	})
+
+	t.Run("certificates rewritten in place are reloaded", func(t *testing.T) {
+		// Rotate by overwriting the files, then set the same paths again
+		ca.issue(t, "reloaded", "rotated")
+		if cn := serverCN(t); cn != "reloaded" {
+			t.Errorf("Expected the certificate to be kept until CONFIG SET, got %s", cn)
+		}
+		if _, err := ts.Client.Execute("CONFIG", "SET", "tls-cert-file", ca.path("reloaded.crt")); err != nil {
+			t.Fatalf("Failed to set the certificate: %v", err)
+		}
+		if cn := serverCN(t); cn != "rotated" {
+			t.Errorf("Expected the rotated certificate, got %s", cn)
+		}
+	})
+
+	t.Run("invalid certificates keep the previous ones", func(t *testing.T) {
+		if _, err := ts.Client.Execute("CONFIG", "SET", "tls-cert-file", ca.path("missing.crt")); err == nil {
+			t.Errorf("Expected the missing certificate to be refused")
+		}
+		if response, _ := ts.Client.Execute("CONFIG", "GET", "tls-cert-file"); !strings.Contains(response, ca.path("reloaded.crt")) {
+			t.Errorf("Expected the previous certificate to stay set, got %q", response)
+		}
+		if cn := serverCN(t); cn != "rotated" {
+			t.Errorf("Expected the previous certificate, got %s", cn)
+		}
+	})
+
+	t.Run("invalid certificates at startup", func(t *testing.T) {
+		// The certificate set disappears before the server starts
+		if err := os.Remove(ca.path("reloaded.crt")); err != nil {
+			t.Fatalf("Failed to remove the certificate: %v", err)
+		}
+		config.SetConfig("tls-port", "16402")
+		defer config.SetConfig("tls-port", "16400")
+
+		if srv, err := server.NewServer(16401); err == nil {
+			srv.Shutdown()
+			t.Errorf("Expected NewServer to fail with a missing certificate")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dotslash21/redis-clone/app/config"
+	"github.com/dotslash21/redis-clone/tests/helpers"
+)
+
+// TestUnixSocket tests serving connections on a Unix socket alongside TCP
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.sock")
+	// A stale socket file left by a previous server is replaced
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create a stale socket file: %v", err)
+	}
+
+	config.SetConfig("unixsocket", path)
+	config.SetConfig("unixsocketperm", "700")
+	defer config.SetConfig("unixsocket", "")
+
+	// Setup test environment
+	ts := NewTestSetup(t, 16403) // Different port from other tests
+	closed := false
+	defer func() {
+		if !closed {
+			ts.Close()
+		}
+	}()
+
+	client, err := helpers.NewUnixRedisClient(path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	t.Run("commands", func(t *testing.T) {
+		if response, err := client.Execute("SET", "unix", "socket"); err != nil || response != "OK" {
+			t.Fatalf("Expected OK, got %q (%v)", response, err)
+		}
+		if response, err := ts.Client.Execute("GET", "unix"); err != nil || response != "socket" {
+			t.Errorf("Expected the TCP client to see the value, got %q (%v)", response, err)
+		}
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat the socket: %v", err)
+		}
+		if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0o700 {
+			t.Errorf("Expected a socket with permissions 0700, got %v", info.Mode())
+		}
+	})
+
+	t.Run("CLIENT INFO", func(t *testing.T) {
+		response, err := client.Execute("CLIENT", "INFO")
+		if err != nil {
+			t.Fatalf("Failed to execute CLIENT INFO: %v", err)
+		}
+		for _, field := range []string{"addr=" + path + ":0 ", "flags=U "} {
+			if !strings.Contains(response, field) {
+				t.Errorf("Expected %q in %q", field, response)
+			}
+		}
+	})
+
+	t.Run("socket file removed on shutdown", func(t *testing.T) {
+		client.Close()
+		ts.Close()
+		closed = true
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected the socket file to be removed, got %v", err)
+		}
+	})
+}